@@ -0,0 +1,53 @@
+package xxid
+
+var (
+	b36EncodedLength   = [...]int{25, 25, 25, 44, 25, 31, 44, 25}
+	binDecodedLength36 = [...]int{25: 16, 31: 20, 44: 28}
+
+	compactB36Length          = [...]int{22, 22, 22, 41, 22, 28, 41, 22}
+	compactBinDecodedLength36 = [...]int{22: 14, 28: 18, 41: 26}
+)
+
+// Base36 encodes the ID into its base36 form, which consists of only
+// digits and uppercase letters. The returned bytes may be of length
+// 25, 31, or 44 according to the machine ID type, or 22, 28, or 41 in
+// the compact layout.
+//
+// The base36 form is a subset of the QR code alphanumeric character set,
+// when printed as QR code, the smaller alphanumeric mode can be used
+// instead of byte mode. Like the base62 form, the base36 form is ordered
+// by the ID's generation time.
+func (id ID) Base36() []byte {
+	buf := id.encodeBinary()
+	if id.compact {
+		out := make([]byte, compactB36Length[id.mIDType])
+		encodePadded(out, buf[:id.binaryLen()], encodeBase36)
+		return out
+	}
+	out := make([]byte, b36EncodedLength[id.mIDType])
+	encodeBase36(out, buf)
+	return out
+}
+
+// ParseBase36 parses an ID from its base36 form.
+// Lowercase letters are accepted as uppercase.
+func ParseBase36(src []byte) (ID, error) {
+	inputLen := len(src)
+	if inputLen < len(compactBinDecodedLength36) && compactBinDecodedLength36[inputLen] != 0 {
+		var buf [maxBinEncodedLen]byte
+		binLen := compactBinDecodedLength36[inputLen]
+		if err := decodePadded(buf[:binLen], src, decodeBase36); err != nil {
+			return zeroID, err
+		}
+		return decodeBinary(buf[:binLen])
+	}
+	if inputLen >= len(binDecodedLength36) || binDecodedLength36[inputLen] == 0 {
+		return zeroID, errIncorrectBase36Length
+	}
+	buf := make([]byte, binDecodedLength36[inputLen])
+	err := decodeBase36(buf, src)
+	if err != nil {
+		return zeroID, err
+	}
+	return decodeBinary(buf)
+}
@@ -0,0 +1,68 @@
+package xxid
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestIDBase36(t *testing.T) {
+	ids := []ID{
+		New(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::1")).New(),
+		NewGenerator().UseHashedIPv4(net.ParseIP("10.1.2.3"), []byte("key")).New(),
+	}
+	for _, id := range ids {
+		encoded := id.Base36()
+		for _, c := range encoded {
+			if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z') {
+				t.Fatalf("invalid base36 character %q in %s", c, encoded)
+			}
+		}
+		for _, src := range [][]byte{encoded, bytes.ToLower(encoded)} {
+			got, err := ParseBase36(src)
+			if err != nil || got != id {
+				t.Fatalf("failed parse base36 %s, err= %v", src, err)
+			}
+		}
+	}
+
+	id1 := New()
+	id2 := New()
+	if bytes.Compare(id1.Base36(), id2.Base36()) >= 0 {
+		t.Fatalf("base36 form is not ordered")
+	}
+
+	for _, src := range []string{
+		"",
+		"ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+		"0000000000000000000000000-",
+	} {
+		if _, err := ParseBase36([]byte(src)); err == nil {
+			t.Fatalf("expect error for invalid base36 %q", src)
+		}
+	}
+}
+
+func TestIDBase36_Compact(t *testing.T) {
+	for _, gen := range []*Generator{
+		NewGenerator().UseCompactLayout(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).UseCompactLayout(),
+		NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseCompactLayout(),
+	} {
+		id := gen.New()
+		b36 := id.Base36()
+		if len(b36) != gen.EncodedLen(EncodingBase36) {
+			t.Fatalf("length not match, got= %v", len(b36))
+		}
+		got, err := ParseBase36(b36)
+		if err != nil || got != id {
+			t.Fatalf("round trip failed, got= %v, err= %v", got.Inspect(), err)
+		}
+		max := MaxID(id.mIDType).Compact()
+		if got, err = ParseBase36(max.Base36()); err != nil || got != max {
+			t.Fatalf("round trip of max ID failed, err= %v", err)
+		}
+	}
+}
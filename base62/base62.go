@@ -0,0 +1,140 @@
+// Package base62 implements the base62 encoding used by xxid, so other
+// payloads can be encoded with the exact same alphabet and ordering.
+//
+// The alphabet is 0-9A-Za-z, the encoded form of inputs of the same
+// length has fixed length and keeps the lexicographic ordering of the
+// inputs. The length of inputs must be a multiple of 4.
+package base62
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/jxskiss/xxid/v2/internal/basex"
+)
+
+// Alphabet is the base62 alphabet, the lexicographic ordering (based on
+// Unicode table) is 0-9A-Za-z.
+const Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	// ErrInvalidLength is returned when decoding an input of which the
+	// length is not the encoded length of any 4-byte-multiple input.
+	ErrInvalidLength = errors.New("base62: invalid length")
+
+	// ErrOverflow is returned when the decoded value does not fit in
+	// the destination.
+	ErrOverflow = errors.New("base62: encoded value overflows")
+)
+
+// InvalidCharacterError describes an invalid character in base62 input.
+type InvalidCharacterError byte
+
+func (e InvalidCharacterError) Error() string {
+	return "base62: invalid character " + strconv.QuoteRune(rune(e))
+}
+
+var dec [128]byte
+
+func init() {
+	for i := range dec {
+		dec[i] = 0xff
+	}
+	for i := 0; i < len(Alphabet); i++ {
+		dec[Alphabet[i]] = byte(i)
+	}
+}
+
+// log2 of 62, 8 bits per byte are encoded in log2(62) bits per character.
+var bitsPerChar = math.Log2(62)
+
+// EncodedLen returns the length of the base62 encoding of an input of
+// n bytes, n must be a multiple of 4, e.g. 22 for 16 bytes.
+func EncodedLen(n int) int {
+	return int(math.Ceil(float64(n*8) / bitsPerChar))
+}
+
+// DecodedLen returns the length of the input of which the encoded length
+// is n, i.e. the inverse of EncodedLen. It returns -1 if n is not the
+// encoded length of any 4-byte-multiple input.
+func DecodedLen(n int) int {
+	m := int(float64(n)*bitsPerChar/8) &^ 3
+	for ; EncodedLen(m) <= n; m += 4 {
+		if EncodedLen(m) == n {
+			return m
+		}
+	}
+	return -1
+}
+
+// Encode encodes src to dst, it writes exactly EncodedLen(len(src))
+// bytes to dst. It panics if the length of src is not a multiple of 4.
+func Encode(dst, src []byte) {
+	if len(src)%4 != 0 {
+		panic("base62: length of src is not a multiple of 4")
+	}
+	basex.Encode(dst[:EncodedLen(len(src))], src, Alphabet)
+}
+
+// AppendEncode appends the base62 encoding of src to dst and returns
+// the extended buffer.
+func AppendEncode(dst, src []byte) []byte {
+	n := EncodedLen(len(src))
+	dst = grow(dst, n)
+	Encode(dst[len(dst)-n:], src)
+	return dst
+}
+
+// EncodeToString returns the base62 encoding of src.
+func EncodeToString(src []byte) string {
+	buf := make([]byte, EncodedLen(len(src)))
+	Encode(buf, src)
+	return string(buf)
+}
+
+// Decode decodes src to dst, the length of dst determines the length of
+// the decoded value and must be a multiple of 4. It returns ErrOverflow
+// if the value does not fit in dst, the most common case is dst being
+// shorter than DecodedLen(len(src)).
+func Decode(dst, src []byte) error {
+	if len(dst)%4 != 0 {
+		return ErrInvalidLength
+	}
+	return basex.Decode(dst, src, 62, &dec, invalidCharacter, ErrOverflow)
+}
+
+// AppendDecode appends the decoded value of src to dst and returns the
+// extended buffer, the decoded length is DecodedLen(len(src)).
+// On error, dst is returned unchanged.
+func AppendDecode(dst, src []byte) ([]byte, error) {
+	n := DecodedLen(len(src))
+	if n < 0 {
+		return dst, ErrInvalidLength
+	}
+	out := grow(dst, n)
+	if err := Decode(out[len(out)-n:], src); err != nil {
+		return dst, err
+	}
+	return out, nil
+}
+
+// DecodeString returns the bytes represented by the base62 string s,
+// the decoded length is DecodedLen(len(s)).
+func DecodeString(s string) ([]byte, error) {
+	return AppendDecode(nil, []byte(s))
+}
+
+func invalidCharacter(c byte) error {
+	return InvalidCharacterError(c)
+}
+
+// grow extends the length of buf by n bytes.
+func grow(buf []byte, n int) []byte {
+	if cap(buf)-len(buf) < n {
+		tmp := make([]byte, len(buf), 2*cap(buf)+n)
+		copy(tmp, buf)
+		buf = tmp
+	}
+	return buf[:len(buf)+n]
+}
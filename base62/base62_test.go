@@ -0,0 +1,109 @@
+package base62
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestEncodedLen(t *testing.T) {
+	for n := 0; n <= 1024; n += 4 {
+		// smallest k that 62^k >= 256^n
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(n*8))
+		want := 0
+		for x := big.NewInt(1); x.Cmp(limit) < 0; want++ {
+			x.Mul(x, big.NewInt(62))
+		}
+		if got := EncodedLen(n); got != want {
+			t.Fatalf("EncodedLen result not match, n= %v, want= %v, got= %v", n, want, got)
+		}
+		if got := DecodedLen(want); got != n {
+			t.Fatalf("DecodedLen result not match, n= %v, want= %v, got= %v", want, n, got)
+		}
+	}
+	for _, x := range []struct{ bin, enc int }{{16, 22}, {20, 27}, {28, 38}} {
+		if got := EncodedLen(x.bin); got != x.enc {
+			t.Fatalf("EncodedLen result not match, n= %v, want= %v, got= %v", x.bin, x.enc, got)
+		}
+	}
+	if got := DecodedLen(23); got != -1 {
+		t.Fatalf("DecodedLen result not match, want= %v, got= %v", -1, got)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for n := 0; n <= 256; n += 4 {
+		for _, src := range [][]byte{
+			make([]byte, n),
+			bytes.Repeat([]byte{0xff}, n),
+			randBytes(n),
+		} {
+			s := EncodeToString(src)
+			if len(s) != EncodedLen(n) {
+				t.Fatalf("encoded length not match, want= %v, got= %v", EncodedLen(n), len(s))
+			}
+			got, err := DecodeString(s)
+			if err != nil {
+				t.Fatalf("failed decode %q, err= %v", s, err)
+			}
+			if !bytes.Equal(got, src) && !(n == 0 && len(got) == 0) {
+				t.Fatalf("decoded result not match, want= %x, got= %x", src, got)
+			}
+		}
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	srcs := make([][]byte, 1000)
+	encoded := make([]string, len(srcs))
+	for i := range srcs {
+		srcs[i] = randBytes(36)
+		encoded[i] = EncodeToString(srcs[i])
+	}
+	sort.Slice(srcs, func(i, j int) bool { return bytes.Compare(srcs[i], srcs[j]) < 0 })
+	sort.Strings(encoded)
+	for i := range srcs {
+		if EncodeToString(srcs[i]) != encoded[i] {
+			t.Fatal("encoding does not keep ordering")
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	src := randBytes(40)
+	buf := AppendEncode([]byte("prefix:"), src)
+	if string(buf[:7]) != "prefix:" || string(buf[7:]) != EncodeToString(src) {
+		t.Fatalf("AppendEncode result not match, got= %q", buf)
+	}
+	out, err := AppendDecode([]byte{1, 2}, buf[7:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out[:2], []byte{1, 2}) || !bytes.Equal(out[2:], src) {
+		t.Fatalf("AppendDecode result not match, got= %x", out)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	dst := make([]byte, 16)
+	if err := Decode(dst, []byte("0000000000000000000-00")); err != InvalidCharacterError('-') {
+		t.Fatalf("error not match, want= %v, got= %v", InvalidCharacterError('-'), err)
+	}
+	if err := Decode(dst, []byte("zzzzzzzzzzzzzzzzzzzzzz")); err != ErrOverflow {
+		t.Fatalf("error not match, want= %v, got= %v", ErrOverflow, err)
+	}
+	if err := Decode(make([]byte, 15), []byte("0")); err != ErrInvalidLength {
+		t.Fatalf("error not match, want= %v, got= %v", ErrInvalidLength, err)
+	}
+	if _, err := DecodeString("abc"); err != ErrInvalidLength {
+		t.Fatalf("error not match, want= %v, got= %v", ErrInvalidLength, err)
+	}
+}
+
+func randBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
@@ -0,0 +1,156 @@
+package base62
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+var errPartialRecord = errors.New("base62: partial record at end of stream")
+
+// Encoder is an io.WriteCloser which encodes the data written to it as
+// fixed-size records, each record is written to the underlying writer
+// in base62 form followed by a newline, e.g. to export binary IDs to a
+// text file.
+type Encoder struct {
+	w    io.Writer
+	size int
+	rec  []byte
+	out  []byte
+	err  error
+}
+
+// NewEncoder returns an Encoder which writes records of size bytes to w.
+// It panics if size is not a positive multiple of 4.
+func NewEncoder(w io.Writer, size int) *Encoder {
+	checkRecordSize(size)
+	return &Encoder{w: w, size: size, rec: make([]byte, 0, size)}
+}
+
+// Write encodes the complete records in p and writes them to the
+// underlying writer, the trailing partial record is kept until the
+// following writes complete it.
+func (e *Encoder) Write(p []byte) (n int, err error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n = len(p)
+	e.out = e.out[:0]
+	if len(e.rec) > 0 {
+		m := copy(e.rec[len(e.rec):e.size], p)
+		e.rec = e.rec[:len(e.rec)+m]
+		p = p[m:]
+		if len(e.rec) < e.size {
+			return n, nil
+		}
+		e.out = e.appendRecord(e.out, e.rec)
+		e.rec = e.rec[:0]
+	}
+	for len(p) >= e.size {
+		e.out = e.appendRecord(e.out, p[:e.size])
+		p = p[e.size:]
+	}
+	e.rec = append(e.rec, p...)
+	if len(e.out) > 0 {
+		if _, e.err = e.w.Write(e.out); e.err != nil {
+			return 0, e.err
+		}
+	}
+	return n, nil
+}
+
+// Close checks that no partial record is left, it does not close the
+// underlying writer.
+func (e *Encoder) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.rec) > 0 {
+		e.err = errPartialRecord
+		return e.err
+	}
+	return nil
+}
+
+func (e *Encoder) appendRecord(out, rec []byte) []byte {
+	out = AppendEncode(out, rec)
+	return append(out, '\n')
+}
+
+// Decoder is an io.Reader which decodes records written by Encoder,
+// i.e. newline separated base62 strings of the same length. Empty lines
+// and trailing carriage returns are ignored.
+type Decoder struct {
+	r    *bufio.Reader
+	size int
+	rec  []byte
+	buf  []byte
+	err  error
+}
+
+// NewDecoder returns a Decoder which reads records of size bytes from r.
+// It panics if size is not a positive multiple of 4.
+func NewDecoder(r io.Reader, size int) *Decoder {
+	checkRecordSize(size)
+	bufSize := 4096
+	if n := EncodedLen(size) + 2; n > bufSize {
+		bufSize = n
+	}
+	return &Decoder{
+		r:    bufio.NewReaderSize(r, bufSize),
+		size: size,
+		rec:  make([]byte, size),
+	}
+}
+
+// Read reads decoded records into p, a record may be split across
+// reads if p is not large enough.
+func (d *Decoder) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(d.buf) == 0 {
+			if d.err != nil {
+				break
+			}
+			d.err = d.next()
+			continue
+		}
+		m := copy(p[n:], d.buf)
+		d.buf = d.buf[m:]
+		n += m
+	}
+	if n > 0 {
+		return n, nil
+	}
+	return 0, d.err
+}
+
+// next decodes the next record into d.buf.
+func (d *Decoder) next() error {
+	for {
+		line, err := d.r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return ErrInvalidLength
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) > 0 {
+			if len(line) != EncodedLen(d.size) {
+				return ErrInvalidLength
+			}
+			if derr := Decode(d.rec, line); derr != nil {
+				return derr
+			}
+			d.buf = d.rec
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func checkRecordSize(size int) {
+	if size <= 0 || size%4 != 0 {
+		panic("base62: record size is not a positive multiple of 4")
+	}
+}
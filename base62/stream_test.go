@@ -0,0 +1,79 @@
+package base62
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	data := randBytes(16 * 100)
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, 16)
+	// write in chunks not aligned to records
+	for p := data; len(p) > 0; {
+		n := 7
+		if n > len(p) {
+			n = len(p)
+		}
+		if _, err := enc.Write(p[:n]); err != nil {
+			t.Fatal(err)
+		}
+		p = p[n:]
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("number of lines not match, want= %v, got= %v", 100, len(lines))
+	}
+	if lines[3] != EncodeToString(data[48:64]) {
+		t.Fatalf("line not match, want= %v, got= %v", EncodeToString(data[48:64]), lines[3])
+	}
+
+	got, err := ioutil.ReadAll(NewDecoder(&buf, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatal("decoded data not match")
+	}
+}
+
+func TestDecoderLenient(t *testing.T) {
+	a, b := randBytes(20), randBytes(20)
+	input := EncodeToString(a) + "\r\n\n" + EncodeToString(b)
+	got, err := ioutil.ReadAll(NewDecoder(strings.NewReader(input), 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, append(a, b...)) {
+		t.Fatal("decoded data not match")
+	}
+}
+
+func TestStreamErrors(t *testing.T) {
+	enc := NewEncoder(ioutil.Discard, 16)
+	enc.Write(make([]byte, 20))
+	if err := enc.Close(); err != errPartialRecord {
+		t.Fatalf("error not match, want= %v, got= %v", errPartialRecord, err)
+	}
+
+	dec := NewDecoder(strings.NewReader("abc\n"), 16)
+	if _, err := dec.Read(make([]byte, 16)); err != ErrInvalidLength {
+		t.Fatalf("error not match, want= %v, got= %v", ErrInvalidLength, err)
+	}
+
+	dec = NewDecoder(strings.NewReader(EncodeToString(make([]byte, 16))+"\n"), 16)
+	buf := make([]byte, 16)
+	if n, err := io.ReadFull(dec, buf); n != 16 || err != nil {
+		t.Fatalf("failed read record, n= %v, err= %v", n, err)
+	}
+	if _, err := dec.Read(buf); err != io.EOF {
+		t.Fatalf("error not match, want= %v, got= %v", io.EOF, err)
+	}
+}
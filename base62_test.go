@@ -67,3 +67,16 @@ func Test_encodeBase62_decodeBase62(t *testing.T) {
 		}
 	}
 }
+
+func Test_decodeBase62_invalid(t *testing.T) {
+	for _, src := range []string{
+		"zzzzzzzzzzzzzzzzzzzzzz",
+		"zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz",
+		"000000000000000000000\xb0",
+		"000000000000000000000-",
+	} {
+		if _, err := ParseBase62([]byte(src)); err == nil {
+			t.Fatalf("expect error for invalid base62 %q", src)
+		}
+	}
+}
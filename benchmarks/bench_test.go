@@ -0,0 +1,126 @@
+// +build xxidbench
+
+package benchmarks
+
+import (
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jxskiss/xxid/v2"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/xid"
+	"github.com/segmentio/ksuid"
+)
+
+func BenchmarkNew_xxid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = xxid.New()
+	}
+}
+
+func BenchmarkNew_uuidV4(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = uuid.New()
+	}
+}
+
+func BenchmarkNew_xid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = xid.New()
+	}
+}
+
+func BenchmarkNew_ksuid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = ksuid.New()
+	}
+}
+
+func BenchmarkNew_ulid(b *testing.B) {
+	entropy := ulid.Monotonic(rand.Reader, 0)
+	for i := 0; i < b.N; i++ {
+		_ = ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
+	}
+}
+
+func BenchmarkEncode_xxid(b *testing.B) {
+	id := xxid.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.Base62()
+	}
+}
+
+func BenchmarkEncode_uuid(b *testing.B) {
+	id := uuid.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.String()
+	}
+}
+
+func BenchmarkEncode_xid(b *testing.B) {
+	id := xid.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.String()
+	}
+}
+
+func BenchmarkEncode_ksuid(b *testing.B) {
+	id := ksuid.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.String()
+	}
+}
+
+func BenchmarkEncode_ulid(b *testing.B) {
+	id := ulid.Make()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = id.String()
+	}
+}
+
+func BenchmarkDecode_xxid(b *testing.B) {
+	src := xxid.New().Base62()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = xxid.ParseBase62(src)
+	}
+}
+
+func BenchmarkDecode_uuid(b *testing.B) {
+	src := uuid.New().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = uuid.Parse(src)
+	}
+}
+
+func BenchmarkDecode_xid(b *testing.B) {
+	src := xid.New().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = xid.FromString(src)
+	}
+}
+
+func BenchmarkDecode_ksuid(b *testing.B) {
+	src := ksuid.New().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ksuid.Parse(src)
+	}
+}
+
+func BenchmarkDecode_ulid(b *testing.B) {
+	src := ulid.Make().String()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ulid.Parse(src)
+	}
+}
@@ -0,0 +1,10 @@
+// Package benchmarks compares xxid with other unique ID packages, i.e.
+// google/uuid, rs/xid, segmentio/ksuid and oklog/ulid, on generation,
+// encoding and decoding.
+//
+// It is a separate module to keep the dependencies out of xxid, the
+// benchmarks are behind the "xxidbench" build tag:
+//
+//	cd benchmarks
+//	go test -tags xxidbench -bench . -benchmem
+package benchmarks
@@ -0,0 +1,13 @@
+module github.com/jxskiss/xxid/v2/benchmarks
+
+go 1.13
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jxskiss/xxid/v2 v2.0.0
+	github.com/oklog/ulid/v2 v2.1.2
+	github.com/rs/xid v1.6.0
+	github.com/segmentio/ksuid v1.0.4
+)
+
+replace github.com/jxskiss/xxid/v2 => ../
@@ -0,0 +1,89 @@
+package xxid
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// parseCaches holds the LRU caches used by ParseBase62 and ParseString,
+// the base62 form and string form are cached separately since the same
+// text may be valid in both forms.
+type parseCaches struct {
+	base62 *lruCache
+	str    *lruCache
+}
+
+var parseCachePtr unsafe.Pointer // *parseCaches
+
+// SetParseCacheSize enables a bounded LRU cache of successfully parsed
+// IDs for ParseBase62 and ParseString, keyed by the encoded form, each
+// form caches at most size IDs. A size <= 0 disables the cache, which is
+// the default.
+//
+// It helps services which parse the same handful of hot IDs repeatedly,
+// e.g. session or tenant IDs in a gateway. Calling it drops all cached
+// IDs. Note that IDs cached by ParseString are not affected by later
+// changes of time.Local.
+func SetParseCacheSize(size int) {
+	var caches *parseCaches
+	if size > 0 {
+		caches = &parseCaches{
+			base62: newLRUCache(size),
+			str:    newLRUCache(size),
+		}
+	}
+	atomic.StorePointer(&parseCachePtr, unsafe.Pointer(caches))
+}
+
+func getParseCaches() *parseCaches {
+	return (*parseCaches)(atomic.LoadPointer(&parseCachePtr))
+}
+
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key string
+	id  ID
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+func (c *lruCache) get(key []byte) (ID, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[string(key)]; ok {
+		c.ll.MoveToFront(elem)
+		return elem.Value.(*lruEntry).id, true
+	}
+	return zeroID, false
+}
+
+func (c *lruCache) add(key []byte, id ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.items[string(key)]; ok {
+		c.ll.MoveToFront(elem)
+		elem.Value.(*lruEntry).id = id
+		return
+	}
+	entry := &lruEntry{key: string(key), id: id}
+	c.items[entry.key] = c.ll.PushFront(entry)
+	if c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,48 @@
+package xxid
+
+import "testing"
+
+func TestSetParseCacheSize(t *testing.T) {
+	SetParseCacheSize(2)
+	defer SetParseCacheSize(0)
+
+	ids := []ID{New(), New(), New()}
+	for _, id := range ids {
+		got, err := ParseBase62(id.Base62())
+		if err != nil || got != id {
+			t.Fatalf("failed parse base62 with cache, err= %v", err)
+		}
+		got, err = ParseString(id.String())
+		if err != nil || got != id {
+			t.Fatalf("failed parse string with cache, err= %v", err)
+		}
+	}
+
+	caches := getParseCaches()
+	if n := caches.base62.ll.Len(); n != 2 {
+		t.Fatalf("base62 cache should be bounded, got %d entries", n)
+	}
+	if _, ok := caches.base62.get(ids[0].Base62()); ok {
+		t.Fatalf("least recently used entry should be evicted")
+	}
+	if got, ok := caches.str.get([]byte(ids[2].String())); !ok || got != ids[2] {
+		t.Fatalf("recently used entry should be cached")
+	}
+
+	if _, err := ParseBase62([]byte("invalid")); err == nil {
+		t.Fatalf("expect error for invalid input")
+	}
+	if n := caches.base62.ll.Len(); n != 2 {
+		t.Fatalf("failed parses should not be cached")
+	}
+}
+
+func BenchmarkParseBase62_Cached(b *testing.B) {
+	SetParseCacheSize(128)
+	defer SetParseCacheSize(0)
+	src := New().Base62()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseBase62(src)
+	}
+}
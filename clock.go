@@ -0,0 +1,78 @@
+package xxid
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// coarseClock caches the current millisecond, which is updated by a
+// background ticker.
+type coarseClock struct {
+	msec int64
+	stop chan struct{}
+}
+
+var (
+	coarseClockMu  sync.Mutex
+	coarseClockPtr unsafe.Pointer // *coarseClock
+)
+
+// SetCoarseClock enables a coarse clock which caches the current
+// millisecond and is updated by a background ticker at the given
+// interval, New and generators read the cached value atomically instead
+// of calling time.Now. An interval <= 0 stops the ticker and disables
+// the coarse clock, which is the default.
+//
+// It reduces the overhead of time.Now in processes generating hundreds
+// of thousands of IDs per second, at the cost of a background goroutine
+// and timestamps lagging behind the wall clock by up to the interval,
+// or longer if the ticker lags, e.g. the goroutine is not scheduled in
+// time. The uniqueness and ordering guarantees are not affected, when
+// the counter values of the cached millisecond are exhausted, or the
+// cached value is behind the last issued ID, the wall clock is read
+// instead.
+func SetCoarseClock(interval time.Duration) {
+	coarseClockMu.Lock()
+	defer coarseClockMu.Unlock()
+	if old := getCoarseClock(); old != nil {
+		close(old.stop)
+	}
+	var c *coarseClock
+	if interval > 0 {
+		c = &coarseClock{msec: wallClockMsec(), stop: make(chan struct{})}
+		go c.run(interval)
+	}
+	atomic.StorePointer(&coarseClockPtr, unsafe.Pointer(c))
+}
+
+func getCoarseClock() *coarseClock {
+	return (*coarseClock)(atomic.LoadPointer(&coarseClockPtr))
+}
+
+func (c *coarseClock) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			atomic.StoreInt64(&c.msec, wallClockMsec())
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// nowMsec returns the current time in milliseconds, coarse is true if
+// the value is read from the coarse clock.
+func nowMsec() (msec int64, coarse bool) {
+	if c := getCoarseClock(); c != nil {
+		return atomic.LoadInt64(&c.msec), true
+	}
+	return wallClockMsec(), false
+}
+
+func wallClockMsec() int64 {
+	return time.Now().UnixNano() / 1e6
+}
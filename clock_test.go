@@ -0,0 +1,47 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetCoarseClock(t *testing.T) {
+	SetCoarseClock(time.Millisecond)
+	defer SetCoarseClock(0)
+
+	if _, coarse := nowMsec(); !coarse {
+		t.Fatalf("coarse clock is not enabled")
+	}
+	gen := NewGenerator().UseSequenceMode(IsolatedSequence)
+	prev := gen.New()
+	for i := 0; i < 200000; i++ {
+		id := gen.New()
+		if id.Short() <= prev.Short() {
+			t.Fatalf("IDs not ordered: %v %v", prev, id)
+		}
+		prev = id
+	}
+	if d := time.Since(prev.Time()); d < -time.Second || d > time.Second {
+		t.Fatalf("timestamp drifts from wall clock: %v", d)
+	}
+
+	// a lagging ticker falls back to the wall clock once the cached
+	// value is behind the last issued ID
+	SetCoarseClock(0)
+	gen = NewGenerator().UseSequenceMode(IsolatedSequence)
+	prev = gen.New()
+	SetCoarseClock(time.Hour)
+	getCoarseClock().msec -= 10000
+	id := gen.New()
+	if id.Short() <= prev.Short() || id.Time().Before(prev.Time()) {
+		t.Fatalf("lagging coarse clock is not compensated: %v %v", prev, id)
+	}
+	if stats := gen.Stats(); stats.ClockRegressions != 0 {
+		t.Fatalf("lagging coarse clock counted as regression: %+v", stats)
+	}
+
+	SetCoarseClock(0)
+	if _, coarse := nowMsec(); coarse {
+		t.Fatalf("coarse clock is not disabled")
+	}
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/jxskiss/xxid/v2"
+	"github.com/jxskiss/xxid/v2/base62"
+)
+
+// codegenData is the input of the codegen templates, all of which is
+// derived from the layout tables and test vectors of the library.
+type codegenData struct {
+	Lang           string
+	Alphabet       string
+	TypeField      xxid.LayoutField
+	MachineIDField string
+	Layouts        []xxid.TypeLayout
+	CompactLayouts []xxid.TypeLayout
+	Vectors        []xxid.TestVector
+	CompactVectors []xxid.TestVector
+}
+
+var codegenTemplates = map[string]string{
+	"python":     pythonCodec,
+	"javascript": javascriptCodec,
+}
+
+func codegenCmd(args []string) {
+	fs := flag.NewFlagSet("codegen", flag.ExitOnError)
+	var (
+		lang   = fs.String("lang", "", "target language: python or javascript")
+		output = fs.String("o", "", "output file, default to stdout")
+	)
+	fs.Parse(args)
+
+	if _, ok := codegenTemplates[*lang]; !ok {
+		fatalf("unknown language: %q", *lang)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	if err := generateCodec(w, *lang); err != nil {
+		fatalf("%v", err)
+	}
+	if err := w.Flush(); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+// generateCodec executes the codegen template of lang and writes the
+// generated codec to w.
+func generateCodec(w io.Writer, lang string) error {
+	text, ok := codegenTemplates[lang]
+	if !ok {
+		return fmt.Errorf("unknown language: %q", lang)
+	}
+	tmpl, err := template.New(lang).Funcs(template.FuncMap{
+		"int": func(t xxid.MachineIDType) int { return int(t) },
+	}).Parse(text)
+	if err != nil {
+		return err
+	}
+
+	layouts := xxid.Layout()
+	data := codegenData{
+		Lang:           lang,
+		Alphabet:       base62.Alphabet,
+		MachineIDField: xxid.FieldMachineID,
+		Layouts:        layouts,
+		CompactLayouts: xxid.CompactLayout(),
+		Vectors:        xxid.TestVectors(),
+	}
+	data.CompactVectors = compactVectors(data.Vectors)
+	for _, f := range layouts[0].Fields {
+		if f.Name == xxid.FieldMachineIDType {
+			data.TypeField = f
+		}
+	}
+	return tmpl.Execute(w, data)
+}
+
+// compactVectors converts test vectors to the compact layout, which has
+// no flag, see xxid.Generator.UseCompactLayout.
+func compactVectors(vectors []xxid.TestVector) []xxid.TestVector {
+	out := make([]xxid.TestVector, 0, len(vectors))
+	for _, v := range vectors {
+		bin, _ := hex.DecodeString(v.Binary)
+		id, err := xxid.ParseBinary(bin)
+		if err != nil {
+			fatalf("invalid test vector %s: %v", v.Binary, err)
+		}
+		id = id.Compact()
+		v.RawFlag, v.Flag = 0, 0
+		v.Binary = hex.EncodeToString(id.Binary())
+		v.Base62 = string(id.Base62())
+		v.String = ""
+		v.UUID = ""
+		out = append(out, v)
+	}
+	return out
+}
+
+const pythonCodec = `# Code generated by "xxid codegen -lang {{.Lang}}"; DO NOT EDIT.
+#
+# Reference codec of xxid IDs, derived from the layout tables of
+# github.com/jxskiss/xxid/v2. An ID is represented as a dict of its
+# fields, the machine ID is hex encoded, the flag is the raw value whose
+# most significant bit tells whether the flag is set. IDs in the compact
+# layout have no flag.
+
+ALPHABET = "{{.Alphabet}}"
+
+# LAYOUTS maps machine ID types to (name, binary length, base62 length,
+# fields), each field is (name, bit offset, bits).
+LAYOUTS = {
+{{- range .Layouts}}
+    {{int .MachineIDType}}: ("{{.Name}}", {{.BinaryLen}}, {{.Base62Len}}, [
+{{- range $i, $f := .Fields}}{{if $i}}, {{end}}("{{$f.Name}}", {{$f.Offset}}, {{$f.Bits}}){{end}}]),
+{{- end}}
+}
+
+# COMPACT_LAYOUTS are the layouts of IDs in the compact layout, like
+# LAYOUTS.
+COMPACT_LAYOUTS = {
+{{- range .CompactLayouts}}
+    {{int .MachineIDType}}: ("{{.Name}}", {{.BinaryLen}}, {{.Base62Len}}, [
+{{- range $i, $f := .Fields}}{{if $i}}, {{end}}("{{$f.Name}}", {{$f.Offset}}, {{$f.Bits}}){{end}}]),
+{{- end}}
+}
+
+_TYPE_FIELD = "{{.TypeField.Name}}"
+_TYPE_OFFSET, _TYPE_BITS = {{.TypeField.Offset}}, {{.TypeField.Bits}}
+_MACHINE_ID_FIELD = "{{.MachineIDField}}"
+_ALL_LAYOUTS = list(LAYOUTS.values()) + list(COMPACT_LAYOUTS.values())
+_BASE62_LEN = {l[1]: l[2] for l in _ALL_LAYOUTS}
+_BINARY_LEN = {l[2]: l[1] for l in _ALL_LAYOUTS}
+
+
+def decode_binary(b):
+    """Decodes an ID in binary form to a dict of fields."""
+    b = bytes(b)
+    head = (_TYPE_OFFSET + _TYPE_BITS + 7) // 8
+    if len(b) < head:
+        raise ValueError("xxid: invalid binary length")
+    typ = int.from_bytes(b[:head], "big") >> (head * 8 - _TYPE_OFFSET - _TYPE_BITS)
+    typ &= (1 << _TYPE_BITS) - 1
+    if typ in LAYOUTS and len(b) == LAYOUTS[typ][1]:
+        layout = LAYOUTS[typ]
+    elif typ in COMPACT_LAYOUTS and len(b) == COMPACT_LAYOUTS[typ][1]:
+        layout = COMPACT_LAYOUTS[typ]
+    else:
+        raise ValueError("xxid: invalid binary length")
+    n = int.from_bytes(b, "big")
+    total = len(b) * 8
+    fields = {}
+    for name, offset, bits in layout[3]:
+        v = (n >> (total - offset - bits)) & ((1 << bits) - 1)
+        if name == _MACHINE_ID_FIELD:
+            v = v.to_bytes(bits // 8, "big").hex()
+        fields[name] = v
+    return fields
+
+
+def encode_binary(fields):
+    """Encodes a dict of fields to an ID in binary form, the compact
+    layout is used if there is no flag."""
+    layouts = LAYOUTS if "flag" in fields else COMPACT_LAYOUTS
+    typ = fields[_TYPE_FIELD]
+    if typ not in layouts:
+        raise ValueError("xxid: unknown machine ID type")
+    size = layouts[typ][1]
+    n = 0
+    for name, offset, bits in layouts[typ][3]:
+        v = fields[name]
+        if name == _MACHINE_ID_FIELD:
+            if len(v) != bits // 4:
+                raise ValueError("xxid: incorrect machine ID length")
+            v = int(v, 16)
+        if v < 0 or v >> bits:
+            raise ValueError("xxid: field %s out of range" % name)
+        n |= v << (size * 8 - offset - bits)
+    return n.to_bytes(size, "big")
+
+
+def encode_base62(b):
+    """Encodes an ID in binary form to base62 form."""
+    if len(b) not in _BASE62_LEN:
+        raise ValueError("xxid: invalid binary length")
+    n = int.from_bytes(bytes(b), "big")
+    out = []
+    while n:
+        n, r = divmod(n, 62)
+        out.append(ALPHABET[r])
+    return "".join(reversed(out)).rjust(_BASE62_LEN[len(b)], ALPHABET[0])
+
+
+def decode_base62(s):
+    """Decodes an ID in base62 form to binary form."""
+    if len(s) not in _BINARY_LEN:
+        raise ValueError("xxid: invalid base62 length")
+    size = _BINARY_LEN[len(s)]
+    n = 0
+    for c in s:
+        i = ALPHABET.find(c)
+        if i < 0:
+            raise ValueError("xxid: invalid base62 character %r" % c)
+        n = n * 62 + i
+    if n >> (size * 8):
+        raise ValueError("xxid: base62 value overflows")
+    return n.to_bytes(size, "big")
+
+
+def parse(s):
+    """Parses an ID in base62 form to a dict of fields."""
+    return decode_binary(decode_base62(s))
+
+
+def format(fields):
+    """Formats a dict of fields to an ID in base62 form."""
+    return encode_base62(encode_binary(fields))
+
+
+# VECTORS are the canonical test vectors, as (fields, binary, base62).
+VECTORS = [
+{{- range .Vectors}}
+    ({"timestamp": {{.TimeMsec}}, "machineIDType": {{int .MachineIDType}}, "counter": {{.Counter}}, "machineID": "{{.MachineID}}", "pidOrPort": {{.PidOrPort}}, "flag": {{.RawFlag}}},
+     "{{.Binary}}", "{{.Base62}}"),
+{{- end}}
+]
+
+# COMPACT_VECTORS are the test vectors in the compact layout.
+COMPACT_VECTORS = [
+{{- range .CompactVectors}}
+    ({"timestamp": {{.TimeMsec}}, "machineIDType": {{int .MachineIDType}}, "counter": {{.Counter}}, "machineID": "{{.MachineID}}", "pidOrPort": {{.PidOrPort}}},
+     "{{.Binary}}", "{{.Base62}}"),
+{{- end}}
+]
+
+
+def self_test():
+    """Checks the codec against the canonical test vectors."""
+    for fields, binary, b62 in VECTORS + COMPACT_VECTORS:
+        b = bytes.fromhex(binary)
+        assert encode_binary(fields) == b, (fields, binary)
+        assert decode_binary(b) == fields, (fields, binary)
+        assert format(fields) == b62, (fields, b62)
+        assert parse(b62) == fields, (fields, b62)
+
+
+if __name__ == "__main__":
+    self_test()
+    print("ok")
+`
+
+const javascriptCodec = `// Code generated by "xxid codegen -lang {{.Lang}}"; DO NOT EDIT.
+//
+// Reference codec of xxid IDs, derived from the layout tables of
+// github.com/jxskiss/xxid/v2. An ID is represented as an object of its
+// fields, the machine ID is hex encoded, the flag is the raw value whose
+// most significant bit tells whether the flag is set, IDs in the compact
+// layout have no flag. Binary forms are Uint8Arrays, BigInt is required.
+
+export const ALPHABET = "{{.Alphabet}}";
+
+// LAYOUTS maps machine ID types to their layouts, offsets and widths of
+// fields are in bits.
+export const LAYOUTS = {
+{{- range .Layouts}}
+  {{int .MachineIDType}}: { name: "{{.Name}}", binaryLen: {{.BinaryLen}}, base62Len: {{.Base62Len}}, fields: [
+{{- range $i, $f := .Fields}}{{if $i}}, {{end}}["{{$f.Name}}", {{$f.Offset}}, {{$f.Bits}}]{{end}}] },
+{{- end}}
+};
+
+// COMPACT_LAYOUTS are the layouts of IDs in the compact layout, like
+// LAYOUTS.
+export const COMPACT_LAYOUTS = {
+{{- range .CompactLayouts}}
+  {{int .MachineIDType}}: { name: "{{.Name}}", binaryLen: {{.BinaryLen}}, base62Len: {{.Base62Len}}, fields: [
+{{- range $i, $f := .Fields}}{{if $i}}, {{end}}["{{$f.Name}}", {{$f.Offset}}, {{$f.Bits}}]{{end}}] },
+{{- end}}
+};
+
+const TYPE_FIELD = "{{.TypeField.Name}}";
+const TYPE_OFFSET = {{.TypeField.Offset}};
+const TYPE_BITS = {{.TypeField.Bits}};
+const MACHINE_ID_FIELD = "{{.MachineIDField}}";
+const BASE62_LEN = {};
+const BINARY_LEN = {};
+for (const l of [...Object.values(LAYOUTS), ...Object.values(COMPACT_LAYOUTS)]) {
+  BASE62_LEN[l.binaryLen] = l.base62Len;
+  BINARY_LEN[l.base62Len] = l.binaryLen;
+}
+
+function bytesToBigInt(b) {
+  let n = 0n;
+  for (const x of b) {
+    n = (n << 8n) | BigInt(x);
+  }
+  return n;
+}
+
+function bigIntToBytes(n, size) {
+  const b = new Uint8Array(size);
+  for (let i = size - 1; i >= 0; i--) {
+    b[i] = Number(n & 0xffn);
+    n >>= 8n;
+  }
+  return b;
+}
+
+// decodeBinary decodes an ID in binary form to an object of fields.
+export function decodeBinary(b) {
+  const head = Math.ceil((TYPE_OFFSET + TYPE_BITS) / 8);
+  if (b.length < head) {
+    throw new Error("xxid: invalid binary length");
+  }
+  let typ = bytesToBigInt(b.subarray(0, head)) >> BigInt(head * 8 - TYPE_OFFSET - TYPE_BITS);
+  typ = Number(typ & ((1n << BigInt(TYPE_BITS)) - 1n));
+  let layout = LAYOUTS[typ];
+  if (layout !== undefined && b.length !== layout.binaryLen) {
+    layout = COMPACT_LAYOUTS[typ];
+  }
+  if (layout === undefined || b.length !== layout.binaryLen) {
+    throw new Error("xxid: invalid binary length");
+  }
+  const n = bytesToBigInt(b);
+  const total = b.length * 8;
+  const fields = {};
+  for (const [name, offset, bits] of layout.fields) {
+    const v = (n >> BigInt(total - offset - bits)) & ((1n << BigInt(bits)) - 1n);
+    fields[name] = name === MACHINE_ID_FIELD ? v.toString(16).padStart(bits / 4, "0") : Number(v);
+  }
+  return fields;
+}
+
+// encodeBinary encodes an object of fields to an ID in binary form, the
+// compact layout is used if there is no flag.
+export function encodeBinary(fields) {
+  const layouts = "flag" in fields ? LAYOUTS : COMPACT_LAYOUTS;
+  const layout = layouts[fields[TYPE_FIELD]];
+  if (layout === undefined) {
+    throw new Error("xxid: unknown machine ID type");
+  }
+  const total = layout.binaryLen * 8;
+  let n = 0n;
+  for (const [name, offset, bits] of layout.fields) {
+    let v = fields[name];
+    if (name === MACHINE_ID_FIELD) {
+      if (v.length !== bits / 4 || !/^[0-9a-fA-F]*$/.test(v)) {
+        throw new Error("xxid: incorrect machine ID length");
+      }
+      v = BigInt("0x" + v);
+    } else {
+      v = BigInt(v);
+    }
+    if (v < 0n || v >> BigInt(bits) !== 0n) {
+      throw new Error("xxid: field " + name + " out of range");
+    }
+    n |= v << BigInt(total - offset - bits);
+  }
+  return bigIntToBytes(n, layout.binaryLen);
+}
+
+// encodeBase62 encodes an ID in binary form to base62 form.
+export function encodeBase62(b) {
+  const size = BASE62_LEN[b.length];
+  if (size === undefined) {
+    throw new Error("xxid: invalid binary length");
+  }
+  let n = bytesToBigInt(b);
+  let out = "";
+  while (n > 0n) {
+    out = ALPHABET[Number(n % 62n)] + out;
+    n /= 62n;
+  }
+  return out.padStart(size, ALPHABET[0]);
+}
+
+// decodeBase62 decodes an ID in base62 form to binary form.
+export function decodeBase62(s) {
+  const size = BINARY_LEN[s.length];
+  if (size === undefined) {
+    throw new Error("xxid: invalid base62 length");
+  }
+  let n = 0n;
+  for (const c of s) {
+    const i = ALPHABET.indexOf(c);
+    if (i < 0) {
+      throw new Error("xxid: invalid base62 character " + JSON.stringify(c));
+    }
+    n = n * 62n + BigInt(i);
+  }
+  if (n >> BigInt(size * 8) !== 0n) {
+    throw new Error("xxid: base62 value overflows");
+  }
+  return bigIntToBytes(n, size);
+}
+
+// parse parses an ID in base62 form to an object of fields.
+export function parse(s) {
+  return decodeBinary(decodeBase62(s));
+}
+
+// format formats an object of fields to an ID in base62 form.
+export function format(fields) {
+  return encodeBase62(encodeBinary(fields));
+}
+
+// VECTORS are the canonical test vectors, as [fields, binary, base62].
+export const VECTORS = [
+{{- range .Vectors}}
+  [{ timestamp: {{.TimeMsec}}, machineIDType: {{int .MachineIDType}}, counter: {{.Counter}}, machineID: "{{.MachineID}}", pidOrPort: {{.PidOrPort}}, flag: {{.RawFlag}} },
+    "{{.Binary}}", "{{.Base62}}"],
+{{- end}}
+];
+
+// COMPACT_VECTORS are the test vectors in the compact layout.
+export const COMPACT_VECTORS = [
+{{- range .CompactVectors}}
+  [{ timestamp: {{.TimeMsec}}, machineIDType: {{int .MachineIDType}}, counter: {{.Counter}}, machineID: "{{.MachineID}}", pidOrPort: {{.PidOrPort}} },
+    "{{.Binary}}", "{{.Base62}}"],
+{{- end}}
+];
+
+function toHex(b) {
+  return Array.from(b, (x) => x.toString(16).padStart(2, "0")).join("");
+}
+
+// selfTest checks the codec against the canonical test vectors.
+export function selfTest() {
+  const check = (ok, msg) => {
+    if (!ok) {
+      throw new Error("xxid: self test failed: " + msg);
+    }
+  };
+  for (const [fields, binary, b62] of [...VECTORS, ...COMPACT_VECTORS]) {
+    const b = encodeBinary(fields);
+    check(toHex(b) === binary, binary);
+    check(JSON.stringify(decodeBinary(b)) === JSON.stringify(fields), binary);
+    check(format(fields) === b62, b62);
+    check(JSON.stringify(parse(b62)) === JSON.stringify(fields), b62);
+  }
+}
+`
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestGenerateCodec(t *testing.T) {
+	vectors := xxid.TestVectors()
+	compact := compactVectors(vectors)
+	for lang := range codegenTemplates {
+		var buf bytes.Buffer
+		if err := generateCodec(&buf, lang); err != nil {
+			t.Fatalf("failed generate %v codec, err= %v", lang, err)
+		}
+		code := buf.String()
+		for _, v := range append(vectors, compact...) {
+			for _, want := range []string{
+				strconv.Quote(v.Binary),
+				strconv.Quote(v.Base62),
+				strconv.Quote(v.MachineID),
+			} {
+				if !strings.Contains(code, want) {
+					t.Fatalf("%v codec does not contain vector %v", lang, want)
+				}
+			}
+		}
+	}
+	if err := generateCodec(ioutil.Discard, "cobol"); err == nil {
+		t.Fatalf("expect error for unknown language")
+	}
+}
+
+// TestGenerateCodec_selfTest runs the self test of the generated codecs
+// against the test vectors, it is skipped if the interpreter is not
+// installed.
+func TestGenerateCodec_selfTest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxid-codegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, x := range []struct {
+		lang string
+		file string
+		cmd  func(name string) *exec.Cmd
+	}{
+		{"python", "xxid_codec.py", func(name string) *exec.Cmd {
+			return exec.Command("python3", name)
+		}},
+		{"javascript", "xxid_codec.mjs", func(name string) *exec.Cmd {
+			return exec.Command("node", "--input-type=module", "-e",
+				"import { selfTest } from "+strconv.Quote(name)+"; selfTest(); console.log(\"ok\");")
+		}},
+	} {
+		var buf bytes.Buffer
+		if err := generateCodec(&buf, x.lang); err != nil {
+			t.Fatalf("failed generate %v codec, err= %v", x.lang, err)
+		}
+		name := filepath.Join(dir, x.file)
+		if err := ioutil.WriteFile(name, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := x.cmd(name)
+		if _, err := exec.LookPath(cmd.Path); err != nil {
+			t.Logf("skip %v self test: %v", x.lang, err)
+			continue
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil || strings.TrimSpace(string(out)) != "ok" {
+			t.Fatalf("%v self test failed, err= %v, output= %s", x.lang, err, out)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package main
+
+// Database drivers used by the migrate subcommand are registered by
+// importing them here, e.g.
+//
+//	import _ "github.com/go-sql-driver/mysql"
+//	import _ "github.com/lib/pq"
+//
+// No driver is imported by default to keep the module free of
+// third-party dependencies, build the command with the drivers needed.
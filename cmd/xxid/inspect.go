@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func inspectCmd(args []string) {
+	if len(args) == 0 {
+		args = readLines(os.Stdin)
+	}
+	failed := false
+	for i, s := range args {
+		opts := xxid.ParseOptions{TrimSpace: true, TrimQuotes: true}
+		id, err := opts.Parse(s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "xxid: %q: %v\n", s, err)
+			failed = true
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		t := id.Time()
+		fmt.Printf("id:      %s\n", s)
+		fmt.Printf("time:    %s\n", t.Format(time.RFC3339Nano))
+		fmt.Printf("utc:     %s\n", t.UTC().Format(time.RFC3339Nano))
+		fmt.Printf("age:     %s\n", xxid.FormatAge(time.Since(t)))
+		fmt.Printf("inspect: %s\n", id.Inspect())
+	}
+	if failed {
+		os.Exit(2)
+	}
+}
+
+func readLines(f *os.File) []string {
+	var lines []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := strings.TrimSpace(sc.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+// Command xxid generates IDs, prints canonical test vectors, inspects
+// and validates IDs, migrates database columns to xxid and generates
+// reference codecs for other languages.
+//
+// Usage:
+//
+//	xxid [-n count] [-format base62|string|binary|uuid]
+//	xxid -vectors
+//	xxid inspect id... (IDs are read from stdin if not given)
+//	xxid scan [-format base62|string|uuid|any] [-field n -sep ,] [-repair -o file] file...
+//	xxid codegen -lang python|javascript [-o file]
+//	xxid migrate -driver name -dsn dsn -kind xid|uuidv1|ulid -table t -key col [-target col] [-batch n] [-mapping file]
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			migrateCmd(os.Args[2:])
+			return
+		case "inspect":
+			inspectCmd(os.Args[2:])
+			return
+		case "scan":
+			scanCmd(os.Args[2:])
+			return
+		case "codegen":
+			codegenCmd(os.Args[2:])
+			return
+		}
+	}
+
+	var (
+		count   = flag.Int("n", 1, "number of IDs to generate")
+		format  = flag.String("format", "base62", "output format: base62, string, binary or uuid")
+		vectors = flag.Bool("vectors", false, "print canonical test vectors as JSON and exit")
+	)
+	flag.Parse()
+
+	if *vectors {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(xxid.TestVectors()); err != nil {
+			fatalf("%v", err)
+		}
+		return
+	}
+
+	for i := 0; i < *count; i++ {
+		id := xxid.New()
+		switch *format {
+		case "base62":
+			fmt.Println(string(id.Base62()))
+		case "string":
+			fmt.Println(id.String())
+		case "binary":
+			fmt.Println(hex.EncodeToString(id.Binary()))
+		case "uuid":
+			fmt.Println(id.UUID())
+		default:
+			fatalf("unknown format: %q", *format)
+		}
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "xxid: "+format+"\n", args...)
+	os.Exit(1)
+}
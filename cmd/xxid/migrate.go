@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jxskiss/xxid/v2/xxidmigrate"
+)
+
+func migrateCmd(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	var (
+		driver  = fs.String("driver", "", "database/sql driver name, see drivers.go")
+		dsn     = fs.String("dsn", "", "data source name")
+		kind    = fs.String("kind", "", "kind of source IDs: xid, uuidv1 or ulid")
+		table   = fs.String("table", "", "table to migrate")
+		key     = fs.String("key", "", "column which holds the source IDs")
+		target  = fs.String("target", "", "column to write converted IDs to, the table is not changed if empty")
+		dollar  = fs.Bool("dollar", false, "use $n placeholders (e.g. PostgreSQL) instead of ?")
+		batch   = fs.Int("batch", xxidmigrate.DefaultBatchSize, "number of rows updated in one transaction")
+		mapping = fs.String("mapping", "", "file to write \"source,xxid\" lines to, \"-\" for stdout")
+	)
+	fs.Parse(args)
+
+	if err := checkDriver(*driver); err != nil {
+		fatalf("%v", err)
+	}
+	db, err := sql.Open(*driver, *dsn)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	defer db.Close()
+
+	var w io.Writer
+	switch *mapping {
+	case "":
+	case "-":
+		w = os.Stdout
+	default:
+		f, err := os.Create(*mapping)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	opts := xxidmigrate.Options{
+		Kind:              xxidmigrate.Kind(*kind),
+		Table:             *table,
+		KeyColumn:         *key,
+		TargetColumn:      *target,
+		DollarPlaceholder: *dollar,
+		BatchSize:         *batch,
+	}
+	n, err := xxidmigrate.Migrate(context.Background(), db, opts, w)
+	if err != nil {
+		fatalf("migrated %d rows: %v", n, err)
+	}
+	fmt.Fprintf(os.Stderr, "xxid: migrated %d rows\n", n)
+}
+
+// checkDriver reports an error if the driver is not registered, which
+// lists the registered ones, since no driver is built in by default.
+func checkDriver(name string) error {
+	drivers := sql.Drivers()
+	for _, d := range drivers {
+		if d == name {
+			return nil
+		}
+	}
+	if len(drivers) == 0 {
+		return fmt.Errorf("unknown driver %q, no driver is registered, see drivers.go", name)
+	}
+	return fmt.Errorf("unknown driver %q, registered drivers: %s", name, strings.Join(drivers, ", "))
+}
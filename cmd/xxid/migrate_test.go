@@ -0,0 +1,27 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type nopDriver struct{}
+
+func (nopDriver) Open(name string) (driver.Conn, error) { return nil, errors.New("not supported") }
+
+func init() {
+	sql.Register("xxid-nop", nopDriver{})
+}
+
+func TestCheckDriver(t *testing.T) {
+	if err := checkDriver("xxid-nop"); err != nil {
+		t.Fatalf("registered driver not accepted, err= %v", err)
+	}
+	err := checkDriver("mysql")
+	if err == nil || !strings.Contains(err.Error(), "xxid-nop") {
+		t.Fatalf("expect error listing registered drivers, got= %v", err)
+	}
+}
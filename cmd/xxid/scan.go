@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+// scanStats counts the entries checked by the scan subcommand.
+type scanStats struct {
+	total     int
+	valid     int
+	repaired  int
+	malformed int
+}
+
+type scanner struct {
+	format  string
+	field   int
+	sep     string
+	minTime time.Time
+	maxTime time.Time
+	repair  bool
+	out     *bufio.Writer
+	stats   scanStats
+}
+
+func scanCmd(args []string) {
+	fs := flag.NewFlagSet("scan", flag.ExitOnError)
+	var (
+		format    = fs.String("format", "base62", "expected format of IDs: base62, string, uuid or any")
+		field     = fs.Int("field", 0, "1-based field index of IDs in delimited lines, 0 means the whole line")
+		sep       = fs.String("sep", ",", "field separator used with -field")
+		minTime   = fs.String("min-time", "2000-01-01", "IDs before this date (YYYY-MM-DD) are reported as impossible")
+		maxFuture = fs.Duration("max-future", 24*time.Hour, "IDs later than now plus this duration are reported as impossible")
+		repair    = fs.Bool("repair", false, "repair malformed entries where possible and write all lines to -o, unrepairable lines unchanged")
+		output    = fs.String("o", "-", "file to write repaired lines to with -repair, \"-\" for stdout")
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: xxid scan [flags] file... (\"-\" for stdin)")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	minT, err := time.Parse("2006-01-02", *minTime)
+	if err != nil {
+		fatalf("invalid -min-time: %v", err)
+	}
+	s := &scanner{
+		format:  *format,
+		field:   *field,
+		sep:     *sep,
+		minTime: minT,
+		maxTime: time.Now().Add(*maxFuture),
+		repair:  *repair,
+	}
+	var outFile *os.File
+	if s.repair {
+		w := os.Stdout
+		if *output != "-" {
+			f, err := os.Create(*output)
+			if err != nil {
+				fatalf("%v", err)
+			}
+			outFile, w = f, f
+		}
+		s.out = bufio.NewWriter(w)
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if err := s.scanFile(name); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	if s.out != nil {
+		if err := s.out.Flush(); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	if outFile != nil {
+		if err := outFile.Close(); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "xxid: scanned %d entries, %d valid, %d repaired, %d malformed\n",
+		s.stats.total, s.stats.valid, s.stats.repaired, s.stats.malformed)
+	if s.stats.malformed > 0 {
+		os.Exit(2)
+	}
+}
+
+func (s *scanner) scanFile(name string) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for lines.Scan() {
+		lineNo++
+		line := lines.Text()
+		fields := []string{line}
+		idx := 0
+		if s.field > 0 {
+			fields = strings.Split(line, s.sep)
+			idx = s.field - 1
+		}
+		s.stats.total++
+		if idx >= len(fields) {
+			s.stats.malformed++
+			s.report(name, lineNo, "missing field", line)
+			s.write(line)
+			continue
+		}
+
+		value := fields[idx]
+		err := s.check(value)
+		if err == nil {
+			s.stats.valid++
+			s.write(line)
+			continue
+		}
+		if s.repair {
+			if fixed, ok := s.fix(value); ok {
+				s.stats.repaired++
+				fields[idx] = fixed
+				s.write(strings.Join(fields, s.sep))
+				continue
+			}
+		}
+		// Lines which cannot be repaired are written through unchanged,
+		// so that no data is lost, the exit status reports them.
+		s.stats.malformed++
+		s.report(name, lineNo, err.Error(), value)
+		s.write(line)
+	}
+	return lines.Err()
+}
+
+// check parses value in the expected format and checks the timestamp.
+func (s *scanner) check(value string) error {
+	var id xxid.ID
+	var err error
+	switch s.format {
+	case "base62":
+		id, err = xxid.ParseBase62String(value)
+	case "string":
+		id, err = xxid.ParseString(value)
+	case "uuid":
+		id, err = xxid.ParseUUID(value)
+	case "any":
+		id, err = xxid.Parse(value)
+	default:
+		fatalf("unknown format: %q", s.format)
+	}
+	if err != nil {
+		return err
+	}
+	if t := id.Time(); t.Before(s.minTime) || t.After(s.maxTime) {
+		return fmt.Errorf("impossible timestamp %v", t.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// fix tries to recover an ID from noisy value, e.g. with surrounding
+// quotes or white space or in another format, and re-encodes it in the
+// expected format.
+func (s *scanner) fix(value string) (string, bool) {
+	opts := xxid.ParseOptions{TrimSpace: true, URLDecode: true, TrimQuotes: true}
+	id, err := opts.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	if t := id.Time(); t.Before(s.minTime) || t.After(s.maxTime) {
+		return "", false
+	}
+	switch s.format {
+	case "string":
+		return id.String(), true
+	case "uuid":
+		if u := id.UUID(); u != "" {
+			return u, true
+		}
+		return "", false
+	}
+	return string(id.Base62()), true
+}
+
+func (s *scanner) write(line string) {
+	if s.out != nil {
+		s.out.WriteString(line)
+		s.out.WriteByte('\n')
+	}
+}
+
+func (s *scanner) report(name string, lineNo int, reason, value string) {
+	fmt.Fprintf(os.Stderr, "%s:%d: %s: %q\n", name, lineNo, reason, value)
+}
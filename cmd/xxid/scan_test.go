@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestScanRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxid-scan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	id := xxid.New()
+	b62 := string(id.Base62())
+	input := strings.Join([]string{
+		"1," + b62,
+		"2, " + b62 + " ",
+		"3,not-an-id",
+		"4",
+	}, "\n") + "\n"
+	name := filepath.Join(dir, "ids.csv")
+	if err = ioutil.WriteFile(name, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	s := &scanner{
+		format:  "base62",
+		field:   2,
+		sep:     ",",
+		maxTime: time.Now().Add(time.Hour),
+		repair:  true,
+		out:     bufio.NewWriter(&buf),
+	}
+	if err = s.scanFile(name); err != nil {
+		t.Fatalf("scanFile failed, err= %v", err)
+	}
+	s.out.Flush()
+
+	want := "1," + b62 + "\n2," + b62 + "\n3,not-an-id\n4\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+	if s.stats != (scanStats{total: 4, valid: 1, repaired: 1, malformed: 2}) {
+		t.Fatalf("unexpected stats: %+v", s.stats)
+	}
+}
@@ -0,0 +1,85 @@
+package xxid
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrIdentityCollision is returned by Generator.CheckCollision when the
+// identity of the generator is claimed by another live process.
+var ErrIdentityCollision = errors.New("xxid: machine ID and pid are claimed by another process")
+
+// IdentityRegistry records identities claimed by live processes, it is
+// usually backed by a shared store, e.g. etcd, Redis or a database
+// table with a TTL.
+type IdentityRegistry interface {
+	// Claim claims identity for owner. If identity is already claimed
+	// by a live process, it returns the owner of the existing claim,
+	// else it records the claim and returns owner.
+	Claim(ctx context.Context, identity, owner string) (string, error)
+}
+
+var (
+	processOwnerOnce sync.Once
+	processOwner     string
+)
+
+// ProcessOwner returns a random token which identifies the current
+// process, it is used as the owner of identities claimed by
+// Generator.CheckCollision.
+func ProcessOwner() string {
+	processOwnerOnce.Do(func() {
+		var b [16]byte
+		if cryptoRandRead(b[:]) != nil {
+			// uniqueness is all that matters here
+			beEnc.PutUint64(b[:8], uint64(fastrand())<<32|uint64(fastrand()))
+			beEnc.PutUint64(b[8:], uint64(time.Now().UnixNano()))
+		}
+		processOwner = hex.EncodeToString(b[:])
+	})
+	return processOwner
+}
+
+// Identity returns the machine ID type, machine ID and pid or port of
+// the generator as a string, e.g. "1:218b67c8:171". It returns "" if
+// the generator is configured by UseEphemeralMachineID or
+// UsePrivacyMode, which have no fixed identity.
+func (g *Generator) Identity() string {
+	cfg := g.config()
+	if cfg.ephemeralMachineID {
+		return ""
+	}
+	buf := make([]byte, 0, 48)
+	buf = strconv.AppendInt(buf, int64(cfg.mIDType), 10)
+	buf = append(buf, ':')
+	buf = append(buf, hex.EncodeToString(cfg.machineID[:machineIdLength[cfg.mIDType]])...)
+	buf = append(buf, ':')
+	buf = strconv.AppendUint(buf, uint64(cfg.pidOrPort), 10)
+	return b2s(buf)
+}
+
+// CheckCollision registers the identity of the generator with registry,
+// and reports ErrIdentityCollision if another live process claims the
+// same identity, e.g. a cloned VM sharing the same machine-id. It is
+// intended to be called at program start, before generating any IDs.
+//
+// It does nothing if the generator has no fixed identity, see Identity.
+func (g *Generator) CheckCollision(ctx context.Context, registry IdentityRegistry) error {
+	identity := g.Identity()
+	if identity == "" {
+		return nil
+	}
+	owner := ProcessOwner()
+	got, err := registry.Claim(ctx, identity, owner)
+	if err != nil {
+		return err
+	}
+	if got != owner {
+		return ErrIdentityCollision
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package xxid
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type mapRegistry struct {
+	mu     sync.Mutex
+	claims map[string]string
+}
+
+func (r *mapRegistry) Claim(ctx context.Context, identity, owner string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.claims[identity]; ok {
+		return existing, nil
+	}
+	r.claims[identity] = owner
+	return owner, nil
+}
+
+func TestGenerator_CheckCollision(t *testing.T) {
+	ctx := context.Background()
+	registry := &mapRegistry{claims: make(map[string]string)}
+	gen := NewGenerator().UseMachineID([]byte{0x21, 0x8b, 0x67, 0xc8}).UsePort(171)
+	if got := gen.Identity(); got != "4:218b67c8:171" {
+		t.Fatalf("identity not match, got= %v", got)
+	}
+
+	if err := gen.CheckCollision(ctx, registry); err != nil {
+		t.Fatalf("first claim should succeed, err= %v", err)
+	}
+	if err := gen.CheckCollision(ctx, registry); err != nil {
+		t.Fatalf("claim by the same process should succeed, err= %v", err)
+	}
+
+	registry.claims["4:218b67c8:172"] = "another process"
+	if err := gen.UsePort(172).CheckCollision(ctx, registry); err != ErrIdentityCollision {
+		t.Fatalf("expect collision, err= %v", err)
+	}
+
+	if err := NewGenerator().UseEphemeralMachineID().CheckCollision(ctx, registry); err != nil {
+		t.Fatalf("generator without fixed identity should not collide, err= %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package xxid
+
+// IDColumns stores a batch of IDs in a columnar (struct-of-arrays)
+// layout, each field of the IDs is stored in a separate slice, which
+// allows vectorized filtering and cheap export to columnar formats
+// like Apache Arrow for analytics workloads.
+//
+// All slices must be of the same length, the i-th elements of the
+// slices form the i-th ID.
+type IDColumns struct {
+	TimeMsec      []int64
+	MachineIDType []MachineIDType
+	MachineID     [][16]byte
+	PidOrPort     []uint16
+	Counter       []uint16
+
+	// RawFlag holds the flag value with the high bit indicating whether
+	// the flag was set by user, see TestVector.
+	RawFlag []uint16
+
+	// Compact holds whether the IDs are in the compact layout, see
+	// Generator.UseCompactLayout.
+	Compact []bool
+}
+
+// ToColumns converts ids to an IDColumns.
+func ToColumns(ids []ID) *IDColumns {
+	c := &IDColumns{
+		TimeMsec:      make([]int64, 0, len(ids)),
+		MachineIDType: make([]MachineIDType, 0, len(ids)),
+		MachineID:     make([][16]byte, 0, len(ids)),
+		PidOrPort:     make([]uint16, 0, len(ids)),
+		Counter:       make([]uint16, 0, len(ids)),
+		RawFlag:       make([]uint16, 0, len(ids)),
+		Compact:       make([]bool, 0, len(ids)),
+	}
+	for _, id := range ids {
+		c.Append(id)
+	}
+	return c
+}
+
+// Len returns the number of IDs in c.
+func (c *IDColumns) Len() int {
+	return len(c.TimeMsec)
+}
+
+// Append appends id to the end of c.
+func (c *IDColumns) Append(id ID) {
+	c.TimeMsec = append(c.TimeMsec, id.timeMsec)
+	c.MachineIDType = append(c.MachineIDType, id.mIDType)
+	c.MachineID = append(c.MachineID, id.machineID)
+	c.PidOrPort = append(c.PidOrPort, id.pidOrPort)
+	c.Counter = append(c.Counter, id.counter)
+	c.RawFlag = append(c.RawFlag, id.flag)
+	c.Compact = append(c.Compact, id.compact)
+}
+
+// At returns the i-th ID in c.
+func (c *IDColumns) At(i int) ID {
+	return ID{
+		timeMsec:  c.TimeMsec[i],
+		pidOrPort: c.PidOrPort[i],
+		counter:   c.Counter[i],
+		flag:      c.RawFlag[i],
+		mIDType:   c.MachineIDType[i],
+		machineID: c.MachineID[i],
+		compact:   c.Compact[i],
+	}
+}
+
+// IDs converts c back to a slice of IDs.
+func (c *IDColumns) IDs() []ID {
+	ids := make([]ID, c.Len())
+	for i := range ids {
+		ids[i] = c.At(i)
+	}
+	return ids
+}
@@ -0,0 +1,31 @@
+package xxid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDColumns(t *testing.T) {
+	ids := []ID{New(), New().SetFlag(123), NewGenerator().UseMachineID([]byte("12345678")).New()}
+	cols := ToColumns(ids)
+	if cols.Len() != len(ids) {
+		t.Fatalf("length not match, got= %v", cols.Len())
+	}
+	if cols.TimeMsec[1] != ids[1].timeMsec || cols.MachineIDType[2] != Specified8 {
+		t.Fatalf("column values not match")
+	}
+	if !reflect.DeepEqual(cols.IDs(), ids) {
+		t.Fatalf("IDs converted back not match")
+	}
+	if cols.At(1).Flag() != 123 {
+		t.Fatalf("flag not match")
+	}
+}
+
+func TestIDColumns_Compact(t *testing.T) {
+	ids := []ID{New(), NewGenerator().UseCompactLayout().New()}
+	got := ToColumns(ids).IDs()
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("round trip failed, got= %v", got)
+	}
+}
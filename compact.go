@@ -0,0 +1,130 @@
+package xxid
+
+// Lengths of the encoded forms of IDs in the compact layout, which
+// omits the 2 bytes flag, see Generator.UseCompactLayout.
+var (
+	compactBinLength = [...]int{14, 14, 14, 26, 14, 18, 26, 14}
+	compactB62Length = [...]int{19, 19, 19, 35, 19, 25, 35, 19}
+	compactStrLength = [...]int{34, 34, 34, 58, 34, 42, 58, 34}
+
+	compactBinDecodedLength = [...]int{19: 14, 25: 18, 35: 26}
+)
+
+const (
+	minCompactBinLen    = 14
+	minCompactBase62Len = 19
+	minCompactStringLen = 34
+)
+
+// UseCompactLayout sets the generator to generate IDs in the compact
+// layout, which omits the flag entirely, for applications which never
+// use the flag. The binary form is 14, 18 or 26 bytes and the base62
+// form is 19, 25 or 35 characters according to the machine ID type,
+// the string form omits the 4 characters of flag.
+//
+// The compact forms are distinguished from the full forms by length,
+// all parsers accept both. Compact IDs are still ordered by time, but
+// they are not ordered with IDs in the full layout by the encoded forms.
+// It overrides UseFlag and UsePrincipalHash. Compact IDs can not be
+// represented as UUID, ID.UUID returns "" for them, other encodings and
+// conversions keep the layout. Database columns for compact IDs should
+// be created by CompactColumnDDL.
+func (g *Generator) UseCompactLayout() *Generator {
+	g.checkReloaded()
+	g.compact = true
+	return g
+}
+
+// WithCompactLayout is the error-returning counterpart of
+// Generator.UseCompactLayout.
+func WithCompactLayout() Option {
+	return func(g *Generator) error {
+		g.UseCompactLayout()
+		return nil
+	}
+}
+
+// IsCompact reports whether the ID is in the compact layout, which has
+// no flag, see Generator.UseCompactLayout.
+func (id ID) IsCompact() bool {
+	return id.compact
+}
+
+// Compact returns the ID in the compact layout, the flag is discarded.
+func (id ID) Compact() ID {
+	id.flag = 0
+	id.compact = true
+	return id
+}
+
+// binaryLen returns the length of the ID's binary form.
+func (id ID) binaryLen() int {
+	if id.compact {
+		return compactBinLength[id.mIDType]
+	}
+	return binEncodedLength[id.mIDType]
+}
+
+// base62Len returns the length of the ID's base62 form.
+func (id ID) base62Len() int {
+	if id.compact {
+		return compactB62Length[id.mIDType]
+	}
+	return b62EncodedLength[id.mIDType]
+}
+
+// stringLen returns the length of the ID's string form.
+func (id ID) stringLen() int {
+	if id.compact {
+		return compactStrLength[id.mIDType]
+	}
+	return strEncodedLength[id.mIDType]
+}
+
+// putBase62 writes the base62 form of the ID to out, the length of out
+// must be id.base62Len().
+func (id ID) putBase62(out []byte) {
+	var buf [maxBinEncodedLen]byte
+	n := binEncodedLength[id.mIDType]
+	id.putBinary(buf[:n])
+	if !id.compact {
+		encodeBase62(out, buf[:n])
+		return
+	}
+	encodePadded(out, buf[:n-2], encodeBase62)
+}
+
+// parseCompactBase62 parses an ID in the compact layout from its base62
+// form, binLen is the length of the compact binary form.
+func parseCompactBase62(src []byte, binLen int) (ID, error) {
+	var buf [maxBinEncodedLen]byte
+	if err := decodePadded(buf[:binLen], src, decodeBase62); err != nil {
+		return zeroID, err
+	}
+	return decodeBinary(buf[:binLen])
+}
+
+// encodePadded encodes bin, whose length is 2 less than a multiple of 4,
+// e.g. the binary form in the compact layout, by encode which requires
+// the length of its input to be a multiple of 4. bin is encoded with 2
+// leading zero bytes, the length of out must be enough to hold bin.
+func encodePadded(out, bin []byte, encode func(dst, src []byte)) {
+	var buf [maxBinEncodedLen]byte
+	copy(buf[2:], bin)
+	encode(out, buf[:len(bin)+2])
+}
+
+// decodePadded decodes src encoded by encodePadded to dst, the length
+// of dst is the length of bin passed to encodePadded.
+func decodePadded(dst, src []byte, decode func(dst, src []byte) error) error {
+	var buf [maxBinEncodedLen]byte
+	n := len(dst) + 2
+	if err := decode(buf[:n], src); err != nil {
+		return err
+	}
+	if buf[0] != 0 || buf[1] != 0 {
+		return errEncodedValueOverflow
+	}
+	copy(dst, buf[2:n])
+	return nil
+}
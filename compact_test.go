@@ -0,0 +1,110 @@
+package xxid
+
+import (
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCompactLayout(t *testing.T) {
+	gens := []*Generator{
+		NewGenerator().UseCompactLayout(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).UseCompactLayout(),
+		NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseFlag(3).UseCompactLayout(),
+	}
+	lengths := [][3]int{{14, 19, 34}, {18, 25, 42}, {26, 35, 58}}
+	for i, gen := range gens {
+		id := gen.New()
+		if !id.IsCompact() || id.HasFlag() {
+			t.Fatalf("generated ID is not compact: %v", id.Inspect())
+		}
+		want := lengths[i]
+		if len(id.Binary()) != want[0] || len(id.Base62()) != want[1] || len(id.String()) != want[2] {
+			t.Fatalf("incorrect lengths: %d %d %d", len(id.Binary()), len(id.Base62()), len(id.String()))
+		}
+		if gen.EncodedLen(EncodingBase62) != want[1] || CompactLayout()[id.mIDType].Base62Len != want[1] {
+			t.Fatalf("incorrect encoded length")
+		}
+
+		check := func(name string, got ID, err error) {
+			if err != nil || got != id {
+				t.Fatalf("%s result not match, want= %v, got= %v, err= %v", name, id.Inspect(), got.Inspect(), err)
+			}
+		}
+		got, err := ParseBinary(id.Binary())
+		check("ParseBinary", got, err)
+		got, err = ParseBase62(id.Base62())
+		check("ParseBase62", got, err)
+		got, err = ParseString(id.String())
+		check("ParseString", got, err)
+		got, err = Parse(string(id.Base62()))
+		check("Parse", got, err)
+		got, err = ParseURN(id.URN())
+		check("ParseURN", got, err)
+
+		got = ID{}
+		data, _ := json.Marshal(id)
+		err = json.Unmarshal(data, &got)
+		check("json", got, err)
+		for _, v := range []interface{}{id.Binary(), string(id.Base62()), id.String()} {
+			got = ID{}
+			err = got.Scan(v)
+			check("Scan", got, err)
+		}
+
+		short, err := gen.FromShort(id.Short())
+		check("FromShort", short, err)
+
+		ids := []ID{MinID(id.mIDType).Compact(), MaxID(id.mIDType).Compact()}
+		for _, x := range ids {
+			got, err = ParseBase62(x.Base62())
+			if err != nil || got != x {
+				t.Fatalf("bound round trip failed: %v, %v", x.Inspect(), err)
+			}
+		}
+	}
+
+	if id := gens[0].New().SetFlag(5); id.IsCompact() || id.Flag() != 5 {
+		t.Fatalf("SetFlag should return an ID in the full layout")
+	}
+	if _, err := ParseBase62String("zzzzzzzzzzzzzzzzzzz"); err == nil {
+		t.Fatalf("expect overflow error")
+	}
+
+	var b62s []string
+	for i := 0; i < 100; i++ {
+		b62s = append(b62s, string(gens[1].New().Base62()))
+	}
+	if !sort.StringsAreSorted(b62s) {
+		t.Fatalf("compact base62 forms are not sorted")
+	}
+}
+
+func TestExtractFirst_Compact(t *testing.T) {
+	id := NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).UseCompactLayout().New()
+	got, ok := ExtractFirst("request " + string(id.Base62()) + " failed")
+	if !ok || got != id {
+		t.Fatalf("ExtractFirst = %v, %v", got, ok)
+	}
+}
+
+func TestCompactLayout_CRCAndCursor(t *testing.T) {
+	id := NewGenerator().UseCompactLayout().New()
+	got, err := ParseBinaryWithCRC(id.BinaryWithCRC())
+	if err != nil || got != id || len(id.BinaryWithCRC()) != 18 {
+		t.Fatalf("ParseBinaryWithCRC = %v, %v", got, err)
+	}
+	c, err := DecodeCursor(EncodeCursor(Cursor{ID: id, Limit: 10}, nil), nil)
+	if err != nil || c.ID != id {
+		t.Fatalf("DecodeCursor = %v, %v", c.ID, err)
+	}
+}
+
+func TestCompactLayout_Inspect(t *testing.T) {
+	got := NewGenerator().UseCompactLayout().New().Inspect()
+	if !strings.HasSuffix(got, " layout=compact") || strings.Contains(got, "flag=") {
+		t.Fatalf("Inspect = %q", got)
+	}
+}
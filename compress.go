@@ -0,0 +1,196 @@
+package xxid
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errInvalidCompressedIDs = errors.New("xxid: compressed IDs are invalid")
+
+// compactTypeBit is set in the machine ID type byte of a dictionary
+// entry for IDs in the compact layout.
+const compactTypeBit = 0x80
+
+// CompressIDs encodes ids into a compact block, it is intended to store
+// large amount of IDs in event logs.
+//
+// The timestamps are delta-encoded relative to the first ID in the block
+// as varints, and the machine ID type, machine ID, pid or port and
+// layout, which are usually constant in a block, are written only once and referenced
+// by index. An ID generated by a typical generator takes 6 to 8 bytes
+// when the block is large enough, compared to 16 bytes of binary form.
+//
+// The order of ids is kept, it works best if the IDs are roughly sorted.
+func CompressIDs(ids []ID) []byte {
+	out := make([]byte, 0, 16+len(ids)*6)
+	out = appendUvarint(out, uint64(len(ids)))
+	if len(ids) == 0 {
+		return out
+	}
+
+	prevTime := ids[0].timeMsec
+	out = appendVarint(out, prevTime)
+	dict := machineDict{}
+	for i := range ids {
+		id := &ids[i]
+		out = appendVarint(out, id.timeMsec-prevTime)
+		out = appendUvarint(out, uint64(id.counter))
+		out = dict.append(out, id)
+		out = appendUvarint(out, uint64(id.flag))
+		prevTime = id.timeMsec
+	}
+	return out
+}
+
+// DecompressIDs decodes IDs from a block returned by CompressIDs.
+func DecompressIDs(src []byte) ([]ID, error) {
+	r := varintReader{buf: src}
+	n := r.uvarint()
+	if r.err != nil || n > uint64(len(src)) {
+		return nil, errInvalidCompressedIDs
+	}
+	ids := make([]ID, 0, n)
+	if n == 0 {
+		return ids, nil
+	}
+
+	prevTime := r.varint()
+	dict := machineDict{}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		var id ID
+		id.timeMsec = prevTime + r.varint()
+		id.counter = r.uint16()
+		dict.read(&r, &id)
+		id.flag = r.uint16()
+		prevTime = id.timeMsec
+		ids = append(ids, id)
+	}
+	if r.err != nil || len(r.buf) != 0 {
+		return nil, errInvalidCompressedIDs
+	}
+	return ids, nil
+}
+
+// machineDict deduplicates the machine ID type, machine ID, pid or port
+// and layout of IDs in a block. Each ID references an entry by index, an index
+// equal to the number of known entries introduces a new entry, which
+// follows the index inline.
+type machineDict struct {
+	entries []machineEntry
+	index   map[machineEntry]int
+}
+
+type machineEntry struct {
+	mIDType   MachineIDType
+	pidOrPort uint16
+	machineID [16]byte
+	compact   bool
+}
+
+func (d *machineDict) append(out []byte, id *ID) []byte {
+	entry := machineEntry{id.mIDType, id.pidOrPort, id.machineID, id.compact}
+	if i, ok := d.index[entry]; ok {
+		return appendUvarint(out, uint64(i))
+	}
+	if d.index == nil {
+		d.index = make(map[machineEntry]int)
+	}
+	d.index[entry] = len(d.entries)
+	out = appendUvarint(out, uint64(len(d.entries)))
+	typ := byte(id.mIDType)
+	if id.compact {
+		typ |= compactTypeBit
+	}
+	out = append(out, typ)
+	out = append(out, id.MachineID()...)
+	out = append(out, byte(id.pidOrPort>>8), byte(id.pidOrPort))
+	d.entries = append(d.entries, entry)
+	return out
+}
+
+func (d *machineDict) read(r *varintReader, id *ID) {
+	idx := r.uvarint()
+	if r.err != nil {
+		return
+	}
+	if idx < uint64(len(d.entries)) {
+		entry := &d.entries[idx]
+		id.mIDType = entry.mIDType
+		id.pidOrPort = entry.pidOrPort
+		id.machineID = entry.machineID
+		id.compact = entry.compact
+		return
+	}
+	if idx != uint64(len(d.entries)) || len(r.buf) == 0 {
+		r.err = errInvalidCompressedIDs
+		return
+	}
+	id.mIDType = MachineIDType(r.buf[0] &^ compactTypeBit)
+	id.compact = r.buf[0]&compactTypeBit != 0
+	if id.mIDType > maxMachineIDType {
+		r.err = errUnknownMachineIDType
+		return
+	}
+	mIDLen := machineIdLength[id.mIDType]
+	if len(r.buf) < 1+mIDLen+2 {
+		r.err = errInvalidCompressedIDs
+		return
+	}
+	copy(id.machineID[:], r.buf[1:1+mIDLen])
+	id.pidOrPort = beEnc.Uint16(r.buf[1+mIDLen:])
+	r.buf = r.buf[1+mIDLen+2:]
+	d.entries = append(d.entries, machineEntry{id.mIDType, id.pidOrPort, id.machineID, id.compact})
+}
+
+// varintReader reads varints from buf, the first error is kept in err
+// and subsequent reads return zero values.
+type varintReader struct {
+	buf []byte
+	err error
+}
+
+func (r *varintReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	x, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = errInvalidCompressedIDs
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return x
+}
+
+func (r *varintReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	x, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = errInvalidCompressedIDs
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return x
+}
+
+func (r *varintReader) uint16() uint16 {
+	x := r.uvarint()
+	if x > 0xffff && r.err == nil {
+		r.err = errInvalidCompressedIDs
+	}
+	return uint16(x)
+}
+
+func appendUvarint(out []byte, x uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], x)
+	return append(out, tmp[:n]...)
+}
+
+func appendVarint(out []byte, x int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], x)
+	return append(out, tmp[:n]...)
+}
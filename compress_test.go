@@ -0,0 +1,53 @@
+package xxid
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestCompressIDs(t *testing.T) {
+	gen := NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")).UsePort(8080)
+	var ids []ID
+	for i := 0; i < 1000; i++ {
+		switch i % 3 {
+		case 0:
+			ids = append(ids, New())
+		case 1:
+			ids = append(ids, gen.New())
+		case 2:
+			ids = append(ids, New().SetFlag(uint16(i)))
+		}
+	}
+
+	block := CompressIDs(ids)
+	if len(block) > len(ids)*10 {
+		t.Fatalf("compressed block is too large, got %d bytes", len(block))
+	}
+	got, err := DecompressIDs(block)
+	if err != nil {
+		t.Fatalf("failed decompress IDs, err= %v", err)
+	}
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("decompressed IDs not match")
+	}
+
+	if got, err := DecompressIDs(CompressIDs(nil)); err != nil || len(got) != 0 {
+		t.Fatalf("failed round trip of empty block, err= %v", err)
+	}
+	for _, n := range []int{1, len(block) / 2, len(block) - 1} {
+		if _, err := DecompressIDs(block[:n]); err == nil {
+			t.Fatalf("expect error for truncated block of %d bytes", n)
+		}
+	}
+}
+
+func TestCompressIDs_Compact(t *testing.T) {
+	gen := NewGenerator().UseMachineID([]byte{1, 2, 3, 4})
+	id := gen.New()
+	ids := []ID{id, id.Compact(), NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseCompactLayout().New()}
+	got, err := DecompressIDs(CompressIDs(ids))
+	if err != nil || !reflect.DeepEqual(got, ids) {
+		t.Fatalf("round trip failed, got= %v, err= %v", got, err)
+	}
+}
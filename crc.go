@@ -0,0 +1,43 @@
+package xxid
+
+import (
+	"errors"
+	"hash/crc32"
+)
+
+var errChecksumMismatch = errors.New("xxid: checksum of binary form mismatches")
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// crcLen is the length of the checksum appended by BinaryWithCRC.
+const crcLen = 4
+
+// BinaryWithCRC returns the ID's binary form followed by a big-endian
+// CRC32C (Castagnoli) checksum of it, the returned bytes may be of
+// length 20, 24, or 32 according to the machine ID type.
+//
+// It is intended for IDs persisted in raw storage, e.g. Kafka headers
+// or custom write-ahead logs, ParseBinaryWithCRC detects bit rot and
+// truncation instead of decoding into a wrong but valid ID.
+func (id ID) BinaryWithCRC() []byte {
+	// the compact layout omits the trailing flag, which is overwritten
+	// by the checksum
+	n := id.binaryLen()
+	out := make([]byte, binEncodedLength[id.mIDType]+crcLen)
+	id.putBinary(out)
+	beEnc.PutUint32(out[n:], crc32.Checksum(out[:n], castagnoliTable))
+	return out[:n+crcLen]
+}
+
+// ParseBinaryWithCRC parses an ID from bytes returned by BinaryWithCRC,
+// it returns an error if the checksum mismatches.
+func ParseBinaryWithCRC(src []byte) (ID, error) {
+	if len(src) < minCompactBinLen+crcLen {
+		return zeroID, errIncorrectBinaryLength
+	}
+	n := len(src) - crcLen
+	if crc32.Checksum(src[:n], castagnoliTable) != beEnc.Uint32(src[n:]) {
+		return zeroID, errChecksumMismatch
+	}
+	return decodeBinary(src[:n])
+}
@@ -0,0 +1,36 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIDBinaryWithCRC(t *testing.T) {
+	for _, id := range []ID{
+		New(),
+		NewGenerator().UseMachineID([]byte("12345678")).New(),
+		NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")).New(),
+	} {
+		buf := id.BinaryWithCRC()
+		if len(buf) != len(id.Binary())+4 {
+			t.Fatalf("unexpected length %d", len(buf))
+		}
+		got, err := ParseBinaryWithCRC(buf)
+		if err != nil || got != id {
+			t.Fatalf("round trip failed, got= %v, err= %v", got, err)
+		}
+
+		for i := 0; i < len(buf)*8; i++ {
+			corrupted := append([]byte(nil), buf...)
+			corrupted[i/8] ^= 1 << uint(i%8)
+			if _, err := ParseBinaryWithCRC(corrupted); err == nil {
+				t.Fatalf("bit flip at %d not detected", i)
+			}
+		}
+		for n := 0; n < len(buf); n++ {
+			if _, err := ParseBinaryWithCRC(buf[:n]); err == nil {
+				t.Fatalf("truncation to %d bytes not detected", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package xxid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	cursorVersion  = 1
+	cursorBackward = 1 << 0
+	cursorSigned   = 1 << 1
+	cursorMACLen   = 16
+)
+
+var (
+	errInvalidCursor   = errors.New("xxid: cursor is invalid")
+	errCursorSignature = errors.New("xxid: cursor signature mismatch")
+)
+
+// Cursor is a keyset pagination cursor, it points to the last ID of
+// a page, the next page starts after (or before if Backward) the ID.
+type Cursor struct {
+	ID       ID
+	Backward bool
+	Limit    int
+}
+
+// EncodeCursor packs c into an opaque URL-safe token.
+//
+// If key is not empty, the token is signed with HMAC-SHA256, thus
+// clients can not forge cursors, e.g. to jump to arbitrary IDs or raise
+// the limit.
+func EncodeCursor(c Cursor, key []byte) string {
+	buf := make([]byte, 0, 2+4+maxBinEncodedLen+cursorMACLen)
+	flags := byte(0)
+	if c.Backward {
+		flags |= cursorBackward
+	}
+	if len(key) > 0 {
+		flags |= cursorSigned
+	}
+	buf = append(buf, cursorVersion, flags)
+	buf = appendUvarint(buf, uint64(c.Limit))
+	buf = append(buf, c.ID.Binary()...)
+	if len(key) > 0 {
+		buf = append(buf, cursorMAC(key, buf)...)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodeCursor unpacks a token returned by EncodeCursor.
+//
+// If key is not empty, the token must be signed with the same key,
+// else the signature, if any, is not verified.
+func DecodeCursor(token string, key []byte) (Cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) < 2 || buf[0] != cursorVersion {
+		return Cursor{}, errInvalidCursor
+	}
+	flags := buf[1]
+	if flags&cursorSigned != 0 {
+		if len(buf) < 2+cursorMACLen {
+			return Cursor{}, errInvalidCursor
+		}
+		mac := buf[len(buf)-cursorMACLen:]
+		buf = buf[:len(buf)-cursorMACLen]
+		if len(key) > 0 && !hmac.Equal(mac, cursorMAC(key, buf)) {
+			return Cursor{}, errCursorSignature
+		}
+	} else if len(key) > 0 {
+		return Cursor{}, errCursorSignature
+	}
+
+	r := varintReader{buf: buf[2:]}
+	limit := r.uvarint()
+	if r.err != nil || limit > 1<<31-1 {
+		return Cursor{}, errInvalidCursor
+	}
+	id, err := decodeBinary(r.buf)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	return Cursor{
+		ID:       id,
+		Backward: flags&cursorBackward != 0,
+		Limit:    int(limit),
+	}, nil
+}
+
+func cursorMAC(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)[:cursorMACLen]
+}
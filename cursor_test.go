@@ -0,0 +1,33 @@
+package xxid
+
+import "testing"
+
+func TestCursor(t *testing.T) {
+	key := []byte("secret")
+	c := Cursor{ID: New(), Backward: true, Limit: 50}
+	for _, k := range [][]byte{nil, key} {
+		token := EncodeCursor(c, k)
+		got, err := DecodeCursor(token, k)
+		if err != nil || got != c {
+			t.Fatalf("cursor round trip not match, err= %v", err)
+		}
+	}
+
+	signed := EncodeCursor(c, key)
+	if _, err := DecodeCursor(signed, []byte("other")); err == nil {
+		t.Fatalf("expect error for wrong key")
+	}
+	if _, err := DecodeCursor(EncodeCursor(c, nil), key); err == nil {
+		t.Fatalf("expect error for unsigned cursor when key is given")
+	}
+	tampered := []byte(signed)
+	tampered[5] ^= 1
+	if _, err := DecodeCursor(string(tampered), key); err == nil {
+		t.Fatalf("expect error for tampered cursor")
+	}
+	for _, bad := range []string{"", "!!!", "AQ"} {
+		if _, err := DecodeCursor(bad, nil); err == nil {
+			t.Fatalf("expect error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package xxid
+
+// EncodeSortedDeltas encodes ids sorted in ascending order into a compact
+// form, it is intended for index files and changefeeds which ship large
+// sorted ID lists.
+//
+// Besides deduplicating the machine information like CompressIDs, the
+// timestamps and counters are encoded as deltas to the previous ID,
+// which are tiny for IDs generated sequentially.
+// It panics if the timestamps of ids are not in ascending order.
+func EncodeSortedDeltas(ids []ID) []byte {
+	out := make([]byte, 0, 16+len(ids)*5)
+	out = appendUvarint(out, uint64(len(ids)))
+	if len(ids) == 0 {
+		return out
+	}
+
+	prevTime, prevCounter := ids[0].timeMsec, ids[0].counter
+	out = appendVarint(out, prevTime)
+	out = appendUvarint(out, uint64(prevCounter))
+	dict := machineDict{}
+	for i := range ids {
+		id := &ids[i]
+		if id.timeMsec < prevTime {
+			panic("xxid: IDs are not sorted")
+		}
+		out = appendUvarint(out, uint64(id.timeMsec-prevTime))
+		out = appendUvarint(out, uint64(id.counter-prevCounter))
+		out = dict.append(out, id)
+		out = appendUvarint(out, uint64(id.flag))
+		prevTime, prevCounter = id.timeMsec, id.counter
+	}
+	return out
+}
+
+// DecodeSortedDeltas decodes IDs from data returned by EncodeSortedDeltas.
+func DecodeSortedDeltas(src []byte) ([]ID, error) {
+	r := varintReader{buf: src}
+	n := r.uvarint()
+	if r.err != nil || n > uint64(len(src)) {
+		return nil, errInvalidCompressedIDs
+	}
+	ids := make([]ID, 0, n)
+	if n == 0 {
+		return ids, nil
+	}
+
+	prevTime, prevCounter := r.varint(), r.uint16()
+	dict := machineDict{}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		var id ID
+		id.timeMsec = prevTime + int64(r.uvarint())
+		id.counter = prevCounter + r.uint16()
+		dict.read(&r, &id)
+		id.flag = r.uint16()
+		prevTime, prevCounter = id.timeMsec, id.counter
+		ids = append(ids, id)
+	}
+	if r.err != nil || len(r.buf) != 0 {
+		return nil, errInvalidCompressedIDs
+	}
+	return ids, nil
+}
@@ -0,0 +1,46 @@
+package xxid
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeSortedDeltas(t *testing.T) {
+	var ids []ID
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, New())
+	}
+
+	data := EncodeSortedDeltas(ids)
+	if len(data) >= len(CompressIDs(ids)) {
+		t.Fatalf("sorted deltas should be smaller than compressed IDs")
+	}
+	got, err := DecodeSortedDeltas(data)
+	if err != nil {
+		t.Fatalf("failed decode sorted deltas, err= %v", err)
+	}
+	if !reflect.DeepEqual(got, ids) {
+		t.Fatalf("decoded IDs not match")
+	}
+
+	if _, err := DecodeSortedDeltas(data[:len(data)-1]); err == nil {
+		t.Fatalf("expect error for truncated data")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expect panic for unsorted IDs")
+		}
+	}()
+	EncodeSortedDeltas([]ID{ids[0], NewWithTime(ids[0].Time().Add(-time.Second))})
+}
+
+func TestEncodeSortedDeltas_Compact(t *testing.T) {
+	gen := NewGenerator().UseCompactLayout()
+	ids := []ID{gen.New(), gen.New(), New()}
+	got, err := DecodeSortedDeltas(EncodeSortedDeltas(ids))
+	if err != nil || !reflect.DeepEqual(got, ids) {
+		t.Fatalf("round trip failed, got= %v, err= %v", got, err)
+	}
+}
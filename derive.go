@@ -0,0 +1,34 @@
+package xxid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// Derive returns a deterministic child ID of parent for the given name,
+// idempotent retries of a sub-operation get the same sub-ID by deriving
+// it from the ID of the operation, without storing the sub-ID.
+//
+// The child ID has the parent's timestamp, flag and layout, its machine
+// ID type is Random, the machine ID, pid or port and counter are taken from the
+// HMAC-SHA256 of name keyed by the binary form of parent. Children with
+// different names, or of different parents, differ with overwhelming
+// probability, and they are sorted together with IDs generated in the
+// same millisecond as parent.
+func Derive(parent ID, name string) ID {
+	mac := hmac.New(sha256.New, parent.Binary())
+	mac.Write(s2b(name))
+	var sum [sha256.Size]byte
+	mac.Sum(sum[:0])
+
+	child := ID{
+		timeMsec:  parent.timeMsec,
+		pidOrPort: beEnc.Uint16(sum[4:6]),
+		counter:   beEnc.Uint16(sum[6:8]),
+		flag:      parent.flag,
+		mIDType:   Random,
+		compact:   parent.compact,
+	}
+	copy(child.machineID[:4], sum[:4])
+	return child
+}
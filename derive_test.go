@@ -0,0 +1,43 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	parent := NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseFlag(12).New()
+	a := Derive(parent, "charge")
+	if a != Derive(parent, "charge") {
+		t.Fatalf("Derive is not deterministic")
+	}
+	if a.Time() != parent.Time() || a.Flag() != 12 || a.MachineIDType() != Random {
+		t.Fatalf("derived ID not match: %v", a.Inspect())
+	}
+	if b := Derive(parent, "refund"); b == a {
+		t.Fatalf("derived IDs of different names are equal")
+	}
+	if c := Derive(NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseFlag(12).New(), "charge"); c == a {
+		t.Fatalf("derived IDs of different parents are equal")
+	}
+	// the derivation is part of the wire contract, it must be stable
+	fixed := TestVectors()[0]
+	p, _ := ParseBase62([]byte(fixed.Base62))
+	if got := string(Derive(p, "x").Base62()); got != "0MTmSIz6XI6OGKxvafXLYZ" {
+		t.Fatalf("derived ID changed: %v", got)
+	}
+}
+
+func TestDerive_Compact(t *testing.T) {
+	parent := NewGenerator().UseCompactLayout().New()
+	full := parent
+	full.compact = false
+	child := Derive(parent, "step")
+	if !child.IsCompact() || child == Derive(full, "step") {
+		t.Fatalf("Derive lost the compact layout, got= %v", child.Inspect())
+	}
+	got, err := ParseBase62(child.Base62())
+	if err != nil || got != child {
+		t.Fatalf("round trip failed, got= %v, err= %v", got.Inspect(), err)
+	}
+}
@@ -0,0 +1,102 @@
+package xxid
+
+import (
+	"errors"
+	"sync"
+)
+
+// Sources of the default generator's machine ID, reported by Diagnostics.
+const (
+	// SourcePlatform indicates the machine ID is read from the platform
+	// specific host identifier, e.g. /etc/machine-id.
+	SourcePlatform = "platform"
+
+	// SourceHostname indicates the machine ID is the hash of hostname.
+	SourceHostname = "hostname"
+
+	// SourceRandom indicates the machine ID is random bytes.
+	SourceRandom = "random"
+
+	// SourceFallback indicates the machine ID is provided by a fallback
+	// set by SetMachineIDFallback.
+	SourceFallback = "fallback"
+)
+
+// A MachineIDFallback decides what to do when InitDefault can not read
+// the host identifier, cause is the reason of the failure.
+//
+// It may return a machine ID of 4, 8 or 16 bytes to be used as a user
+// specified machine ID, or nil to use a random machine ID, or an error
+// to make InitDefault fail.
+type MachineIDFallback func(cause error) ([]byte, error)
+
+var (
+	// FallbackRandom uses a random machine ID, which is the behavior of
+	// the package initialization.
+	FallbackRandom MachineIDFallback = func(cause error) ([]byte, error) {
+		return nil, nil
+	}
+
+	// FallbackError makes InitDefault return the cause, it is the
+	// default fallback of InitDefault.
+	FallbackError MachineIDFallback = func(cause error) ([]byte, error) {
+		return nil, cause
+	}
+)
+
+var diagnostics struct {
+	mu       sync.Mutex
+	fallback MachineIDFallback
+	info     Diagnostics
+}
+
+// SetMachineIDFallback sets the fallback applied by InitDefault when the
+// host identifier can not be read, it may be FallbackRandom,
+// FallbackError or a user callback.
+func SetMachineIDFallback(fallback MachineIDFallback) {
+	if fallback == nil {
+		panic(errors.New("xxid: machine ID fallback must not be nil"))
+	}
+	diagnostics.mu.Lock()
+	diagnostics.fallback = fallback
+	diagnostics.mu.Unlock()
+}
+
+func getMachineIDFallback() MachineIDFallback {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	if diagnostics.fallback == nil {
+		return FallbackError
+	}
+	return diagnostics.fallback
+}
+
+// Diagnostics describes how the default generator was initialized.
+type Diagnostics struct {
+	// MachineIDSource is where the machine ID comes from, it is one of
+	// SourcePlatform, SourceHostname, SourceRandom and SourceFallback.
+	MachineIDSource string
+
+	// MachineIDError is the reason why the host identifier can not be
+	// read, it is nil if MachineIDSource is SourcePlatform or
+	// SourceHostname.
+	MachineIDError error
+}
+
+func setDiagnostics(source string, err error) {
+	diagnostics.mu.Lock()
+	diagnostics.info = Diagnostics{
+		MachineIDSource: source,
+		MachineIDError:  err,
+	}
+	diagnostics.mu.Unlock()
+}
+
+// GetDiagnostics returns diagnostics of the default generator's
+// initialization, e.g. to report in health checks that the machine ID
+// is random, which may cause collisions on cloned VMs.
+func GetDiagnostics() Diagnostics {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	return diagnostics.info
+}
@@ -0,0 +1,78 @@
+package xxid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// saveDefaultGenerator replaces the default generator with a copy, the
+// returned function restores it.
+func saveDefaultGenerator() (restore func()) {
+	saved := defaultGenerator
+	defaultGenerator = &Generator{genConfig: *saved.config(), seq: saved.seq}
+	return func() { defaultGenerator = saved }
+}
+
+func TestInitDefault_fallback(t *testing.T) {
+	defer saveDefaultGenerator()()
+	savedHostID, savedHostname := readHostID, readHostname
+	defer func() {
+		readHostID, readHostname = savedHostID, savedHostname
+		SetMachineIDFallback(FallbackError)
+		InitDefault()
+	}()
+
+	if err := InitDefault(); err != nil {
+		t.Skipf("host identifier is unavailable: %v", err)
+	}
+	if src := GetDiagnostics().MachineIDSource; src != SourcePlatform && src != SourceHostname {
+		t.Fatalf("unexpected machine ID source %q", src)
+	}
+
+	readHostID = func() (string, error) { return "", errors.New("no machine id") }
+	readHostname = func() (string, error) { return "", errors.New("no hostname") }
+
+	if err := InitDefault(); err == nil {
+		t.Fatalf("expect error with FallbackError")
+	}
+
+	SetMachineIDFallback(FallbackRandom)
+	if err := InitDefault(); err != nil {
+		t.Fatalf("FallbackRandom should not fail, err= %v", err)
+	}
+	diag := GetDiagnostics()
+	if diag.MachineIDSource != SourceRandom || diag.MachineIDError == nil ||
+		New().MachineIDType() != Random {
+		t.Fatalf("diagnostics not match, got= %+v", diag)
+	}
+
+	machineID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	SetMachineIDFallback(func(cause error) ([]byte, error) {
+		return machineID, nil
+	})
+	if err := InitDefault(); err != nil {
+		t.Fatalf("callback fallback should not fail, err= %v", err)
+	}
+	if GetDiagnostics().MachineIDSource != SourceFallback ||
+		!bytes.Equal(New().MachineID(), machineID) {
+		t.Fatalf("callback fallback not applied")
+	}
+}
+
+func TestInitDefault_concurrent(t *testing.T) {
+	defer saveDefaultGenerator()()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			New()
+			NewGenerator()
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		InitDefault()
+	}
+	<-done
+}
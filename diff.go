@@ -0,0 +1,96 @@
+package xxid
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+// IDDelta describes the differences between two IDs, see Delta.
+type IDDelta struct {
+	// Time is the time of b minus the time of a.
+	Time time.Duration
+
+	// Counter is the counter of b minus the counter of a.
+	Counter int
+
+	SameMachine bool // machine ID type and machine ID
+	SamePid     bool // pid or port
+	SameFlag    bool
+
+	// Order is the result of CompareIDs(a, b), and OrderedBy is the
+	// first field which differs and decides the order, e.g. "time" or
+	// "counter", it is empty if a and b are equal.
+	Order     int
+	OrderedBy string
+}
+
+// Delta returns the differences between a and b, it is intended for
+// debugging, e.g. why two IDs sort in some order.
+func Delta(a, b ID) IDDelta {
+	d := IDDelta{
+		Time:        b.Time().Sub(a.Time()),
+		Counter:     int(b.counter) - int(a.counter),
+		SameMachine: a.mIDType == b.mIDType && a.machineID == b.machineID,
+		SamePid:     a.pidOrPort == b.pidOrPort,
+		SameFlag:    a.flag == b.flag,
+		Order:       CompareIDs(a, b),
+	}
+	switch {
+	case a.timeMsec != b.timeMsec:
+		d.OrderedBy = "time"
+	case a.mIDType != b.mIDType:
+		d.OrderedBy = "machineIDType"
+	case a.counter != b.counter:
+		d.OrderedBy = "counter"
+	case !bytes.Equal(a.MachineID(), b.MachineID()):
+		d.OrderedBy = "machineID"
+	case a.pidOrPort != b.pidOrPort:
+		d.OrderedBy = "pid"
+	case a.flag != b.flag:
+		d.OrderedBy = "flag"
+	case a.compact != b.compact:
+		d.OrderedBy = "layout"
+	}
+	return d
+}
+
+// String returns a printable summary of the delta.
+//
+// Example:
+//
+//	a<b by time, time=+3ms counter=+2 machine=same pid=same flag=different
+func (d IDDelta) String() string {
+	buf := make([]byte, 0, 96)
+	switch d.Order {
+	case -1:
+		buf = append(buf, "a<b by "...)
+	case 1:
+		buf = append(buf, "a>b by "...)
+	default:
+		buf = append(buf, "a==b"...)
+	}
+	buf = append(buf, d.OrderedBy...)
+	buf = append(buf, ", time="...)
+	if d.Time >= 0 {
+		buf = append(buf, '+')
+	}
+	buf = append(buf, d.Time.String()...)
+	buf = append(buf, " counter="...)
+	if d.Counter >= 0 {
+		buf = append(buf, '+')
+	}
+	buf = strconv.AppendInt(buf, int64(d.Counter), 10)
+	buf = appendSame(buf, " machine=", d.SameMachine)
+	buf = appendSame(buf, " pid=", d.SamePid)
+	buf = appendSame(buf, " flag=", d.SameFlag)
+	return b2s(buf)
+}
+
+func appendSame(buf []byte, name string, same bool) []byte {
+	buf = append(buf, name...)
+	if same {
+		return append(buf, "same"...)
+	}
+	return append(buf, "different"...)
+}
@@ -0,0 +1,41 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDelta(t *testing.T) {
+	ts := time.Unix(1637371300, 0)
+	a := NewWithTime(ts).SetFlag(1)
+	b := a
+	b.timeMsec += 3
+	b.counter += 2
+	b.flag = 2 | flagMask
+
+	d := Delta(a, b)
+	if d.Time != 3*time.Millisecond || d.Counter != 2 || d.Order != -1 || d.OrderedBy != "time" {
+		t.Fatalf("delta not match, got= %+v", d)
+	}
+	want := "a<b by time, time=+3ms counter=+2 machine=same pid=same flag=different"
+	if got := d.String(); got != want {
+		t.Fatalf("delta summary not match\nwant= %v\ngot=  %v", want, got)
+	}
+
+	d = Delta(b, a)
+	if d.Order != 1 || d.Time != -3*time.Millisecond || d.Counter != -2 {
+		t.Fatalf("reversed delta not match, got= %+v", d)
+	}
+	if d = Delta(a, a); d.Order != 0 || d.OrderedBy != "" || d.String()[:4] != "a==b" {
+		t.Fatalf("delta of equal IDs not match, got= %v", d)
+	}
+}
+
+func TestDelta_Compact(t *testing.T) {
+	a := NewGenerator().UseCompactLayout().New()
+	b := a
+	b.compact = false
+	if d := Delta(a, b); d.Order != -1 || d.OrderedBy != "layout" {
+		t.Fatalf("unexpected delta: %v", d)
+	}
+}
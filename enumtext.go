@@ -0,0 +1,157 @@
+package xxid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// String returns the name of the machine ID type, e.g. "IPv4", as used
+// by ID.Inspect.
+func (t MachineIDType) String() string {
+	if t > maxMachineIDType {
+		return "MachineIDType(" + strconv.Itoa(int(t)) + ")"
+	}
+	return machineIDTypeNames[t]
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, the
+// text form is the lower case name, e.g. "ipv4" or "hostid".
+func (t MachineIDType) MarshalText() ([]byte, error) {
+	if t > maxMachineIDType {
+		return nil, errUnknownMachineIDType
+	}
+	return []byte(strings.ToLower(machineIDTypeNames[t])), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Names are matched case-insensitively, decimal numbers are also
+// accepted for configurations which use the numeric values.
+func (t *MachineIDType) UnmarshalText(text []byte) error {
+	i, ok := parseEnumText(text, machineIDTypeNames[:])
+	if !ok {
+		return fmt.Errorf("xxid: unknown machine ID type %q", text)
+	}
+	*t = MachineIDType(i)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, it accepts
+// a name as JSON string, and a number for payloads which use the
+// numeric values. JSON null is a no-op.
+func (t *MachineIDType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, t.UnmarshalText)
+}
+
+var sqlStorageNames = [...]string{
+	StoreBase62: "base62",
+	StoreBinary: "binary",
+	StoreShort:  "short",
+}
+
+// String returns the name of the storage, e.g. "base62".
+func (s SQLStorage) String() string {
+	if s < StoreBase62 || s > StoreShort {
+		return "SQLStorage(" + strconv.Itoa(int(s)) + ")"
+	}
+	return sqlStorageNames[s]
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s SQLStorage) MarshalText() ([]byte, error) {
+	if s < StoreBase62 || s > StoreShort {
+		return nil, fmt.Errorf("xxid: unknown SQL storage %d", s)
+	}
+	return []byte(sqlStorageNames[s]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Names are matched case-insensitively, decimal numbers are also
+// accepted.
+func (s *SQLStorage) UnmarshalText(text []byte) error {
+	i, ok := parseEnumText(text, sqlStorageNames[:])
+	if !ok {
+		return fmt.Errorf("xxid: unknown SQL storage %q", text)
+	}
+	*s = SQLStorage(i)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, it accepts
+// a name as JSON string, or a number. JSON null is a no-op.
+func (s *SQLStorage) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, s.UnmarshalText)
+}
+
+var sequenceModeNames = [...]string{
+	SharedSequence:   "shared",
+	IsolatedSequence: "isolated",
+}
+
+// String returns the name of the sequence mode, e.g. "shared".
+func (m SequenceMode) String() string {
+	if m < SharedSequence || m > IsolatedSequence {
+		return "SequenceMode(" + strconv.Itoa(int(m)) + ")"
+	}
+	return sequenceModeNames[m]
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (m SequenceMode) MarshalText() ([]byte, error) {
+	if m < SharedSequence || m > IsolatedSequence {
+		return nil, fmt.Errorf("xxid: unknown sequence mode %d", m)
+	}
+	return []byte(sequenceModeNames[m]), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Names are matched case-insensitively, decimal numbers are also
+// accepted.
+func (m *SequenceMode) UnmarshalText(text []byte) error {
+	i, ok := parseEnumText(text, sequenceModeNames[:])
+	if !ok {
+		return fmt.Errorf("xxid: unknown sequence mode %q", text)
+	}
+	*m = SequenceMode(i)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, it accepts
+// a name as JSON string, or a number. JSON null is a no-op.
+func (m *SequenceMode) UnmarshalJSON(data []byte) error {
+	return unmarshalEnumJSON(data, m.UnmarshalText)
+}
+
+// parseEnumText returns the index of text in names, or the value of
+// text as a decimal number less than len(names).
+func parseEnumText(text []byte, names []string) (int, bool) {
+	s := string(text)
+	for i, name := range names {
+		if strings.EqualFold(s, name) {
+			return i, true
+		}
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || n >= uint64(len(names)) {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// unmarshalEnumJSON decodes a JSON string or number by unmarshalText.
+func unmarshalEnumJSON(data []byte, unmarshalText func([]byte) error) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		data = []byte(s)
+	}
+	return unmarshalText(data)
+}
@@ -0,0 +1,95 @@
+package xxid
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMachineIDType_Text(t *testing.T) {
+	for typ := Random; typ <= maxMachineIDType; typ++ {
+		text, err := typ.MarshalText()
+		if err != nil || string(text) != strings.ToLower(typ.String()) {
+			t.Fatalf("MarshalText(%v) = %q, %v", typ, text, err)
+		}
+		for _, s := range []string{string(text), typ.String(), strings.ToUpper(typ.String())} {
+			var got MachineIDType
+			if err = got.UnmarshalText([]byte(s)); err != nil || got != typ {
+				t.Fatalf("UnmarshalText(%q) = %v, %v", s, got, err)
+			}
+		}
+	}
+	if IPv4.String() != "IPv4" || MachineIDType(9).String() != "MachineIDType(9)" {
+		t.Fatalf("String not match")
+	}
+	if _, err := MachineIDType(9).MarshalText(); err == nil {
+		t.Fatalf("expect error for unknown type")
+	}
+
+	var cfg struct {
+		Type MachineIDType `json:"type"`
+	}
+	for _, data := range []string{`{"type":"ipv4"}`, `{"type":"IPv4"}`, `{"type":2}`} {
+		cfg.Type = Random
+		if err := json.Unmarshal([]byte(data), &cfg); err != nil || cfg.Type != IPv4 {
+			t.Fatalf("json.Unmarshal(%s) = %v, %v", data, cfg.Type, err)
+		}
+	}
+	for _, data := range []string{`{"type":"ipv5"}`, `{"type":8}`, `{"type":true}`} {
+		if err := json.Unmarshal([]byte(data), &cfg); err == nil {
+			t.Fatalf("expect error for %s", data)
+		}
+	}
+	cfg.Type = HostID
+	if err := json.Unmarshal([]byte(`{"type":null}`), &cfg); err != nil || cfg.Type != HostID {
+		t.Fatalf("null should be a no-op, got %v, %v", cfg.Type, err)
+	}
+	out, _ := json.Marshal(cfg)
+	if string(out) != `{"type":"hostid"}` {
+		t.Fatalf("json.Marshal = %s", out)
+	}
+}
+
+func TestConfigEnums_Text(t *testing.T) {
+	var cfg struct {
+		Storage SQLStorage   `json:"storage"`
+		Mode    SequenceMode `json:"mode"`
+	}
+	if err := json.Unmarshal([]byte(`{"storage":"Binary","mode":"isolated"}`), &cfg); err != nil ||
+		cfg.Storage != StoreBinary || cfg.Mode != IsolatedSequence {
+		t.Fatalf("json.Unmarshal = %+v, %v", cfg, err)
+	}
+	out, _ := json.Marshal(cfg)
+	if string(out) != `{"storage":"binary","mode":"isolated"}` {
+		t.Fatalf("json.Marshal = %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"storage":2,"mode":0}`), &cfg); err != nil ||
+		cfg.Storage != StoreShort || cfg.Mode != SharedSequence {
+		t.Fatalf("json.Unmarshal numbers = %+v, %v", cfg, err)
+	}
+	if err := cfg.Storage.UnmarshalText([]byte("text")); err == nil {
+		t.Fatalf("expect error for unknown storage")
+	}
+	if err := cfg.Mode.UnmarshalText([]byte("2")); err == nil {
+		t.Fatalf("expect error for unknown sequence mode")
+	}
+	if StoreShort.String() != "short" || SequenceMode(5).String() != "SequenceMode(5)" {
+		t.Fatalf("String not match")
+	}
+}
+
+func TestTestVector_JSONStable(t *testing.T) {
+	v := TestVectors()[8]
+	out, err := json.Marshal(v)
+	if err != nil || !strings.Contains(string(out), `"timeMsec":1637371300634,"machineIDType":2,"machineID":"0a090807"`) {
+		t.Fatalf("json.Marshal = %s, %v", out, err)
+	}
+	var got TestVector
+	if err = json.Unmarshal(out, &got); err != nil || got != v {
+		t.Fatalf("json.Unmarshal = %+v, %v", got, err)
+	}
+	out, _ = json.Marshal(Layout()[3])
+	if !strings.HasPrefix(string(out), `{"machineIDType":3,"name":"IPv6"`) {
+		t.Fatalf("json.Marshal layout = %s", out)
+	}
+}
@@ -0,0 +1,101 @@
+package xxid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+)
+
+var errInvalidExternalToken = errors.New("xxid: external token is invalid")
+
+// externalRounds is the number of Feistel rounds, four rounds with a
+// pseudo-random round function make a strong pseudo-random permutation.
+const externalRounds = 4
+
+// External returns an opaque token of the ID for public APIs, it is a
+// keyed permutation of the ID's binary form encoded in base62, which can
+// be converted back by Internal with the same secret.
+//
+// Databases can keep the time-ordered IDs as keys while public APIs only
+// expose the tokens, the tokens of IDs generated in sequence are not
+// ordered and reveal neither the time nor the machine ID, and can not be
+// linked to each other without the secret. The length of the token is
+// fixed for a machine ID type and layout, which is the length of the
+// base62 form.
+//
+// Note that the token is not authenticated, Internal with a wrong secret
+// or a forged token returns an error or an unrelated ID.
+func (id ID) External(secret []byte) string {
+	buf := id.Binary()
+	feistel(buf, secret, false)
+	if id.compact {
+		out := make([]byte, compactB62Length[id.mIDType])
+		encodePadded(out, buf, encodeBase62)
+		return b2s(out)
+	}
+	out := make([]byte, b62EncodedLength[id.mIDType])
+	encodeBase62(out, buf)
+	return b2s(out)
+}
+
+// Internal recovers the ID from a token returned by ID.External.
+func Internal(token string, secret []byte) (ID, error) {
+	var buf []byte
+	n := len(token)
+	switch {
+	case n < len(compactBinDecodedLength) && compactBinDecodedLength[n] != 0:
+		buf = make([]byte, compactBinDecodedLength[n])
+		if err := decodePadded(buf, s2b(token), decodeBase62); err != nil {
+			return zeroID, errInvalidExternalToken
+		}
+	case n < len(binDecodedLength) && binDecodedLength[n] != 0:
+		buf = make([]byte, binDecodedLength[n])
+		if err := decodeBase62(buf, s2b(token)); err != nil {
+			return zeroID, errInvalidExternalToken
+		}
+	default:
+		return zeroID, errInvalidExternalToken
+	}
+	feistel(buf, secret, true)
+	id, err := decodeBinary(buf)
+	if err != nil {
+		return zeroID, errInvalidExternalToken
+	}
+	return id, nil
+}
+
+// feistel permutes buf in place with a balanced Feistel network whose
+// round function is HMAC-SHA256 keyed by secret, the length of buf is
+// even and not larger than 64 bytes.
+func feistel(buf, secret []byte, inverse bool) {
+	half := len(buf) / 2
+	left, right := buf[:half], buf[half:]
+	mac := hmac.New(sha256.New, secret)
+	var sum [sha256.Size]byte
+	round := func(i int, dst, src []byte) {
+		mac.Reset()
+		mac.Write([]byte{byte(i)})
+		mac.Write(src)
+		f := mac.Sum(sum[:0])
+		for j := range dst {
+			dst[j] ^= f[j]
+		}
+	}
+	if !inverse {
+		for i := 0; i < externalRounds; i++ {
+			if i%2 == 0 {
+				round(i, left, right)
+			} else {
+				round(i, right, left)
+			}
+		}
+	} else {
+		for i := externalRounds - 1; i >= 0; i-- {
+			if i%2 == 0 {
+				round(i, left, right)
+			} else {
+				round(i, right, left)
+			}
+		}
+	}
+}
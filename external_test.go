@@ -0,0 +1,61 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIDExternal(t *testing.T) {
+	secret := []byte("secret")
+	gens := []*Generator{
+		NewGenerator(),
+		NewGenerator().UseMachineID([]byte("12345678")),
+		NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")),
+	}
+	for _, gen := range gens {
+		id1, id2 := gen.New(), gen.New()
+		tok1, tok2 := id1.External(secret), id2.External(secret)
+		if len(tok1) != len(id1.Base62()) || tok1 == string(id1.Base62()) {
+			t.Fatalf("token should differ from base62 form, got= %v", tok1)
+		}
+		if tok1[:6] == tok2[:6] {
+			t.Fatalf("tokens should not share the time prefix, got= %v, %v", tok1, tok2)
+		}
+		for _, x := range []struct {
+			tok string
+			id  ID
+		}{{tok1, id1}, {tok2, id2}} {
+			got, err := Internal(x.tok, secret)
+			if err != nil || got != x.id {
+				t.Fatalf("Internal result not match, want= %v, got= %v, err= %v", x.id, got, err)
+			}
+		}
+		if got, err := Internal(tok1, []byte("other")); err == nil && got == id1 {
+			t.Fatalf("Internal with wrong secret should not recover the ID")
+		}
+	}
+
+	for _, tok := range []string{"", "abc", "0000000000000000000-00"} {
+		if _, err := Internal(tok, secret); err == nil {
+			t.Fatalf("expect error for invalid token %q", tok)
+		}
+	}
+}
+
+func TestIDExternal_Compact(t *testing.T) {
+	secret := []byte("secret")
+	for _, gen := range []*Generator{
+		NewGenerator().UseCompactLayout(),
+		NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseCompactLayout(),
+	} {
+		id := gen.New()
+		token := id.External(secret)
+		if len(token) != gen.EncodedLen(EncodingBase62) {
+			t.Fatalf("token length not match, want= %v, got= %v", gen.EncodedLen(EncodingBase62), len(token))
+		}
+		got, err := Internal(token, secret)
+		if err != nil || got != id {
+			t.Fatalf("round trip failed, got= %v, err= %v", got.Inspect(), err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package xxid
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+)
+
+// fastrandState is the state of a splitmix64 generator, it replaces
+// runtime.fastrand which is not accessible via linkname in newer Go
+// releases, TinyGo and WebAssembly targets.
+var fastrandState = seedFastrand()
+
+func seedFastrand() uint64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		return uint64(time.Now().UnixNano())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// fastrand returns a pseudo-random uint32, it is safe for concurrent use
+// but not cryptographically secure.
+func fastrand() uint32 {
+	z := atomic.AddUint64(&fastrandState, 0x9e3779b97f4a7c15)
+	return uint32(mix64(z) >> 32)
+}
+
+// mix64 is the finalizer of splitmix64.
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
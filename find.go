@@ -0,0 +1,115 @@
+package xxid
+
+import "time"
+
+// minFindTime is the earliest timestamp of IDs accepted by FindAll.
+var minFindTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano() / 1e6
+
+// maxFindFuture is how far in the future timestamps of IDs accepted by
+// FindAll may be.
+const maxFindFuture = 24 * time.Hour
+
+// FindAll returns the IDs found in free-form text s, e.g. log lines,
+// stack traces or support tickets, in the order they appear.
+//
+// The text is split into words of letters, digits and '-', a word is
+// recognized as an ID if it is in base62 form, string form, base36 form,
+// UUID text or proquint, the parts of words separated by '-' are also
+// checked, thus an ID in URN or prefixed like "req-<id>" is found.
+// Since almost any alphanumeric word of the right length is a valid
+// base62 form, to reduce false positives, IDs whose timestamp is before
+// year 2000 or more than 24 hours in the future are ignored.
+func FindAll(s string) []ID {
+	var out []ID
+	findIDs(s, func(id ID) bool {
+		out = append(out, id)
+		return true
+	})
+	return out
+}
+
+// ExtractFirst returns the first ID found in free-form text s,
+// see FindAll for details.
+func ExtractFirst(s string) (ID, bool) {
+	var out ID
+	var found bool
+	findIDs(s, func(id ID) bool {
+		out, found = id, true
+		return false
+	})
+	return out, found
+}
+
+// findIDs calls fn for each ID found in s until fn returns false.
+func findIDs(s string, fn func(ID) bool) {
+	maxTime := time.Now().Add(maxFindFuture).UnixNano() / 1e6
+	accept := func(id ID, err error) bool {
+		return err == nil && id.timeMsec >= minFindTime && id.timeMsec <= maxTime
+	}
+	for i := 0; i < len(s); {
+		if !isWordChar(s[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(s) && isWordChar(s[j]) {
+			j++
+		}
+		word := s[i:j]
+		i = j
+
+		if id, err := ParseProquint(word); accept(id, err) {
+			if !fn(id) {
+				return
+			}
+			continue
+		}
+		if len(word) == uuidEncodedLen {
+			if id, err := ParseUUID(word); accept(id, err) {
+				if !fn(id) {
+					return
+				}
+				continue
+			}
+		}
+		for len(word) > 0 {
+			k := 0
+			for k < len(word) && word[k] != '-' {
+				k++
+			}
+			if id, ok := findInPart(word[:k], accept); ok {
+				if !fn(id) {
+					return
+				}
+			}
+			if k == len(word) {
+				break
+			}
+			word = word[k+1:]
+		}
+	}
+}
+
+// findInPart parses an ID from part in base62 form, string form or
+// base36 form, which are mostly distinguished by length, the ID is
+// returned if it is accepted.
+func findInPart(part string, accept func(ID, error) bool) (ID, bool) {
+	n := len(part)
+	if n < minCompactBase62Len {
+		return zeroID, false
+	}
+	if n < len(binDecodedLength36) && binDecodedLength36[n] != 0 ||
+		n < len(compactBinDecodedLength36) && compactBinDecodedLength36[n] != 0 {
+		// base36 forms may be of the same length as base62 forms in
+		// the other layout, e.g. 22 or 25 characters
+		if id, err := ParseBase36(s2b(part)); accept(id, err) {
+			return id, true
+		}
+	}
+	id, err := parseText(s2b(part))
+	return id, accept(id, err)
+}
+
+func isWordChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '-'
+}
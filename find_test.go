@@ -0,0 +1,59 @@
+package xxid
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFindAll(t *testing.T) {
+	gen := NewGenerator()
+	id1, id2 := gen.New(), gen.New()
+	id3 := NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")).New()
+	id4 := gen.New()
+	id5 := gen.New()
+	id6 := gen.New()
+
+	text := fmt.Sprintf("2024/01/02 ERROR req=%s failed: upstream %q (trace urn:xxid:%s)\n"+
+		"\tat handler.go:42 req-%s, uuid=%s proquint %s.\nREF:%s",
+		id1.Base62(), id2.String(), id3.Base62(), id4.Base62(), id5.UUID(), id6.Proquint(), id1.Base36())
+	got := FindAll(text)
+	want := []ID{id1, id2, id3, id4, id5, id6, id1}
+	if len(got) != len(want) {
+		t.Fatalf("number of found IDs not match, want= %v, got= %v", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("found ID not match, want= %v, got= %v", want[i], got[i])
+		}
+	}
+
+	first, ok := ExtractFirst(text)
+	if !ok || first != id1 {
+		t.Fatalf("ExtractFirst result not match, want= %v, got= %v, %v", id1, first, ok)
+	}
+}
+
+func TestFindAllFalsePositives(t *testing.T) {
+	future := NewWithTime(time.Now().Add(48 * time.Hour))
+	old := NewWithTime(time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
+	text := "nothing here, ABCDEFGHIJKLMNOPQRSTUV zzzzzzzzzzzzzzzzzzzzzz " +
+		string(future.Base62()) + " " + string(old.Base62())
+	if got := FindAll(text); len(got) != 0 {
+		t.Fatalf("expect no ID found, got= %v", got)
+	}
+	if _, ok := ExtractFirst(""); ok {
+		t.Fatal("ExtractFirst should not find ID in empty text")
+	}
+}
+
+func TestFindAll_Compact(t *testing.T) {
+	gen := NewGenerator().UseCompactLayout()
+	id1, id2, id3 := gen.New(), gen.New(), gen.New()
+	text := fmt.Sprintf("req=%s ref=%s proquint %s", id1.Base62(), id2.Base36(), id3.Proquint())
+	got := FindAll(text)
+	if len(got) != 3 || got[0] != id1 || got[1] != id2 || got[2] != id3 {
+		t.Fatalf("found IDs not match, want= %v, got= %v", []ID{id1, id2, id3}, got)
+	}
+}
@@ -0,0 +1,99 @@
+package xxid
+
+import "errors"
+
+// firebasePushChars is the modified base64 alphabet used by Firebase
+// push IDs, it is ordered by ASCII, thus lexicographic ordering of push
+// IDs follows their generation time.
+const firebasePushChars = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+const firebasePushIDLen = 20
+
+var errInvalidFirebasePushID = errors.New("xxid: Firebase push ID is invalid")
+
+// FirebasePushID formats the ID as a Firebase push ID, which is 20
+// characters of modified base64, consists of 48 bits of milliseconds
+// timestamp and 72 bits of randomness.
+//
+// The randomness part is filled with the first 72 bits following the
+// timestamp of the binary form, i.e. counter and machine ID, thus the
+// sort order of IDs is retained. The conversion is lossless only for IDs
+// returned by ParseFirebasePushID, for other IDs the remaining bits of
+// the machine ID, pid or port and flag are dropped, and so is the
+// compact layout, IDs in both layouts give the same push ID.
+func (id ID) FirebasePushID() string {
+	buf := id.encodeBinary()
+	out := make([]byte, firebasePushIDLen)
+	ts := id.timeMsec
+	for i := 7; i >= 0; i-- {
+		out[i] = firebasePushChars[ts&63]
+		ts >>= 6
+	}
+	// 72 bits, 9 bytes encoded by 12 characters
+	payload := buf[6:15]
+	for i := 0; i < 3; i++ {
+		x := uint32(payload[i*3])<<16 | uint32(payload[i*3+1])<<8 | uint32(payload[i*3+2])
+		dst := out[8+i*4:]
+		dst[0] = firebasePushChars[x>>18&63]
+		dst[1] = firebasePushChars[x>>12&63]
+		dst[2] = firebasePushChars[x>>6&63]
+		dst[3] = firebasePushChars[x&63]
+	}
+	return b2s(out)
+}
+
+// ParseFirebasePushID parses an ID from a Firebase push ID.
+//
+// The machine ID type of the returned ID is Specified8, the 72 bits of
+// randomness are stored in the counter and the first 7 bytes of the
+// machine ID, pid and flag are zero.
+func ParseFirebasePushID(s string) (ID, error) {
+	if len(s) != firebasePushIDLen {
+		return zeroID, errInvalidFirebasePushID
+	}
+	var vals [firebasePushIDLen]byte
+	for i := 0; i < len(s); i++ {
+		v := decodeFirebasePushChar(s[i])
+		if v == 0xff {
+			return zeroID, errInvalidFirebasePushID
+		}
+		vals[i] = v
+	}
+
+	var ts int64
+	for i := 0; i < 8; i++ {
+		ts = ts<<6 | int64(vals[i])
+	}
+	var payload [9]byte
+	for i := 0; i < 3; i++ {
+		src := vals[8+i*4:]
+		x := uint32(src[0])<<18 | uint32(src[1])<<12 | uint32(src[2])<<6 | uint32(src[3])
+		payload[i*3] = byte(x >> 16)
+		payload[i*3+1] = byte(x >> 8)
+		payload[i*3+2] = byte(x)
+	}
+
+	id := ID{
+		timeMsec: ts,
+		counter:  beEnc.Uint16(payload[:2]),
+		mIDType:  Specified8,
+	}
+	copy(id.machineID[:7], payload[2:])
+	return id, nil
+}
+
+func decodeFirebasePushChar(c byte) byte {
+	switch {
+	case c == '-':
+		return 0
+	case c >= '0' && c <= '9':
+		return c - '0' + 1
+	case c >= 'A' && c <= 'Z':
+		return c - 'A' + 11
+	case c == '_':
+		return 37
+	case c >= 'a' && c <= 'z':
+		return c - 'a' + 38
+	}
+	return 0xff
+}
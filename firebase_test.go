@@ -0,0 +1,58 @@
+package xxid
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestFirebasePushID(t *testing.T) {
+	for i := 0; i < len(firebasePushChars); i++ {
+		if decodeFirebasePushChar(firebasePushChars[i]) != byte(i) {
+			t.Fatalf("push ID character %q not match", firebasePushChars[i])
+		}
+	}
+
+	// A push ID generated by the Firebase JavaScript SDK.
+	const pushID = "-MfGn6qg8jBVWEGV4sHm"
+	id, err := ParseFirebasePushID(pushID)
+	if err != nil {
+		t.Fatalf("failed parse push ID, err= %v", err)
+	}
+	if id.Time().Year() != 2021 {
+		t.Fatalf("push ID time not match, got= %v", id.Time())
+	}
+	if got := id.FirebasePushID(); got != pushID {
+		t.Fatalf("push ID round trip not match, got= %v", got)
+	}
+	parsed, err := ParseBase62(id.Base62())
+	if err != nil || parsed != id {
+		t.Fatalf("failed parse converted ID, err= %v", err)
+	}
+
+	var pushIDs []string
+	for i := 0; i < 100; i++ {
+		pushIDs = append(pushIDs, New().FirebasePushID())
+	}
+	if !sort.StringsAreSorted(pushIDs) {
+		t.Fatalf("push IDs should retain sort order")
+	}
+
+	for _, bad := range []string{"", "-MfGn6qg8jBVWEGV4sH", "-MfGn6qg8jBVWEGV4sH="} {
+		if _, err := ParseFirebasePushID(bad); err == nil {
+			t.Fatalf("expect error for %q", bad)
+		}
+	}
+}
+
+func TestFirebasePushID_Compact(t *testing.T) {
+	id := NewGenerator().UseCompactLayout().New()
+	full := id
+	full.compact = false
+	if id.FirebasePushID() != full.FirebasePushID() {
+		t.Fatalf("push IDs of the compact and full layout differ")
+	}
+	got, err := ParseFirebasePushID(id.FirebasePushID())
+	if err != nil || got.IsCompact() || got.Time() != id.Time() {
+		t.Fatalf("ParseFirebasePushID = %v, err= %v", got.Inspect(), err)
+	}
+}
@@ -0,0 +1,139 @@
+package xxid
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+var flagRegistry struct {
+	mu     sync.RWMutex
+	values map[string]uint16
+	names  map[uint16]string
+}
+
+// RegisterFlag registers a symbolic name for a flag value, e.g. when the
+// flag is used as IDC, region or service code, it makes IDs can be
+// logged and filtered by name instead of magic numbers.
+//
+// It panics if value is larger than 15 bits, or if name or value is
+// already registered with a different counterpart.
+func RegisterFlag(name string, value uint16) {
+	if value&flagMask != 0 {
+		panic(fmt.Sprintf("xxid: flag value %d overflows 15 bits", value))
+	}
+	flagRegistry.mu.Lock()
+	defer flagRegistry.mu.Unlock()
+	if err := registerFlag(name, value); err != nil {
+		panic(err)
+	}
+}
+
+func registerFlag(name string, value uint16) error {
+	if v, ok := flagRegistry.values[name]; ok && v != value {
+		return fmt.Errorf("xxid: flag name %q already registered with value %d", name, v)
+	}
+	if n, ok := flagRegistry.names[value]; ok && n != name {
+		return fmt.Errorf("xxid: flag value %d already registered with name %q", value, n)
+	}
+	if flagRegistry.values == nil {
+		flagRegistry.values = make(map[string]uint16)
+		flagRegistry.names = make(map[uint16]string)
+	}
+	flagRegistry.values[name] = value
+	flagRegistry.names[value] = name
+	return nil
+}
+
+// LookupFlag returns the flag value registered with name.
+func LookupFlag(name string) (value uint16, ok bool) {
+	flagRegistry.mu.RLock()
+	value, ok = flagRegistry.values[name]
+	flagRegistry.mu.RUnlock()
+	return
+}
+
+// FlagName returns the registered name of the ID's flag value.
+// It returns "" if the flag is not set or the value is not registered.
+func (id ID) FlagName() string {
+	if id.flag&flagMask == 0 {
+		return ""
+	}
+	flagRegistry.mu.RLock()
+	name := flagRegistry.names[id.Flag()]
+	flagRegistry.mu.RUnlock()
+	return name
+}
+
+// MarshalFlagRegistry encodes all registered flag names as a JSON object
+// which maps names to values, the result can be shared with other
+// processes and tools, and loaded by LoadFlagRegistry.
+func MarshalFlagRegistry() ([]byte, error) {
+	flagRegistry.mu.RLock()
+	defer flagRegistry.mu.RUnlock()
+	return json.Marshal(flagRegistry.values)
+}
+
+// LoadFlagRegistry registers flag names from a JSON object which maps
+// names to values, as returned by MarshalFlagRegistry.
+// If any name or value conflicts with the registered ones, an error is
+// returned and none of the names are registered.
+func LoadFlagRegistry(data []byte) error {
+	var m map[string]uint16
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	flagRegistry.mu.Lock()
+	defer flagRegistry.mu.Unlock()
+	seen := make(map[uint16]string, len(m))
+	for name, value := range m {
+		if value&flagMask != 0 {
+			return fmt.Errorf("xxid: flag value %d overflows 15 bits", value)
+		}
+		if n, ok := seen[value]; ok {
+			return fmt.Errorf("xxid: flag value %d has duplicate names %q and %q", value, n, name)
+		}
+		seen[value] = name
+		if v, ok := flagRegistry.values[name]; ok && v != value {
+			return fmt.Errorf("xxid: flag name %q already registered with value %d", name, v)
+		}
+		if n, ok := flagRegistry.names[value]; ok && n != name {
+			return fmt.Errorf("xxid: flag value %d already registered with name %q", value, n)
+		}
+	}
+	for name, value := range m {
+		registerFlag(name, value)
+	}
+	return nil
+}
+
+// RegisterServiceCode registers name for a service code, which is stored
+// in the flag of IDs, so that organizations can standardize which flag
+// values mean which service. Service codes share the names and values
+// with RegisterFlag, MarshalFlagRegistry exports both.
+//
+// It panics like RegisterFlag.
+func RegisterServiceCode(name string, code uint16) {
+	RegisterFlag(name, code)
+}
+
+// UseServiceCode sets the generator to use the service code registered
+// with name as flag. It panics if name is not registered.
+func (g *Generator) UseServiceCode(name string) *Generator {
+	code, ok := LookupFlag(name)
+	if !ok {
+		panic(fmt.Sprintf("xxid: service code %q is not registered", name))
+	}
+	return g.UseFlag(code)
+}
+
+// ServiceCode returns the registered service name and code of the ID's
+// flag. It returns false if the flag is not set or the value is not
+// registered.
+func (id ID) ServiceCode() (name string, code uint16, ok bool) {
+	name = id.FlagName()
+	if name == "" {
+		return "", 0, false
+	}
+	return name, id.Flag(), true
+}
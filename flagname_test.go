@@ -0,0 +1,109 @@
+package xxid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// saveFlagRegistry saves the flag registry, the returned function
+// restores it, thus tests don't leak registered flags to others.
+func saveFlagRegistry() (restore func()) {
+	flagRegistry.mu.Lock()
+	values := make(map[string]uint16, len(flagRegistry.values))
+	for name, v := range flagRegistry.values {
+		values[name] = v
+	}
+	flagRegistry.mu.Unlock()
+	return func() {
+		flagRegistry.mu.Lock()
+		flagRegistry.values = make(map[string]uint16, len(values))
+		flagRegistry.names = make(map[uint16]string, len(values))
+		for name, v := range values {
+			flagRegistry.values[name] = v
+			flagRegistry.names[v] = name
+		}
+		flagRegistry.mu.Unlock()
+	}
+}
+
+func TestFlagRegistry(t *testing.T) {
+	defer saveFlagRegistry()()
+
+	RegisterFlag("test-us-east-1", 1001)
+	RegisterFlag("test-us-east-1", 1001) // idempotent
+
+	id := NewGenerator().UseFlag(1001).New()
+	if got := id.FlagName(); got != "test-us-east-1" {
+		t.Fatalf("FlagName not match, got= %q", got)
+	}
+	if got := NewGenerator().UseFlag(1002).New().FlagName(); got != "" {
+		t.Fatalf("FlagName of unregistered value should be empty, got= %q", got)
+	}
+	if v, ok := LookupFlag("test-us-east-1"); !ok || v != 1001 {
+		t.Fatalf("LookupFlag result not match, got= %v %v", v, ok)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expect panic for conflicting name")
+			}
+		}()
+		RegisterFlag("test-us-east-1", 1002)
+	}()
+
+	data, err := MarshalFlagRegistry()
+	if err != nil {
+		t.Fatalf("failed marshal flag registry, err= %v", err)
+	}
+	var m map[string]uint16
+	if err = json.Unmarshal(data, &m); err != nil || m["test-us-east-1"] != 1001 {
+		t.Fatalf("marshaled flag registry not match, got= %s", data)
+	}
+
+	err = LoadFlagRegistry([]byte(`{"test-eu-west-1": 1003, "test-ap-east-1": 1004}`))
+	if err != nil {
+		t.Fatalf("failed load flag registry, err= %v", err)
+	}
+	if v, _ := LookupFlag("test-ap-east-1"); v != 1004 {
+		t.Fatalf("loaded flag not match")
+	}
+	err = LoadFlagRegistry([]byte(`{"test-x": 1005, "test-eu-west-1": 1006}`))
+	if err == nil {
+		t.Fatalf("expect error for conflicting registry")
+	}
+	if _, ok := LookupFlag("test-x"); ok {
+		t.Fatalf("conflicting registry should not be loaded partially")
+	}
+}
+
+func TestSaveFlagRegistry(t *testing.T) {
+	restore := saveFlagRegistry()
+	RegisterFlag("test-restored", 1010)
+	restore()
+	if _, ok := LookupFlag("test-restored"); ok {
+		t.Fatalf("flag registry not restored")
+	}
+	defer saveFlagRegistry()()
+	RegisterFlag("test-restored", 1011) // no conflict after restoring
+}
+
+func TestServiceCode(t *testing.T) {
+	defer saveFlagRegistry()()
+
+	RegisterServiceCode("svc-billing", 4201)
+	id := NewGenerator().UseServiceCode("svc-billing").New()
+	if name, code, ok := id.ServiceCode(); !ok || name != "svc-billing" || code != 4201 {
+		t.Fatalf("service code not match, got= %v, %v, %v", name, code, ok)
+	}
+	if _, _, ok := New().ServiceCode(); ok {
+		t.Fatalf("ID without flag should have no service code")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expect panic for unregistered service code")
+		}
+	}()
+	NewGenerator().UseServiceCode("svc-unknown")
+}
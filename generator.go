@@ -1,41 +1,274 @@
 package xxid
 
 import (
+	"bytes"
+	"context"
 	"crypto/md5"
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
 	"hash/crc32"
+	"hash/fnv"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
 
 	"github.com/jxskiss/xxid/v2/machineid"
 )
 
 var (
 	defaultGenerator *Generator
-	counter          uint32
+
+	defaultInitMu  sync.Mutex // guards defaultInitErr and serializes InitDefault
+	defaultInitErr error
 )
 
 func init() {
-	machineID, mIDType := readMachineID()
+	machineID, mIDType, source, err := readMachineID()
 	pid := readProcessID()
-	counter = runtime_fastrand()
-	defaultGenerator = &Generator{
-		mIDType:   mIDType,
-		pidOrPort: pid,
-	}
+	globalSeq.counter = fastrand()
+	defaultGenerator = &Generator{seq: &globalSeq}
+	defaultGenerator.mIDType = mIDType
+	defaultGenerator.pidOrPort = pid
 	copy(defaultGenerator.machineID[:4], machineID[:])
+	defaultInitErr = err
+	setDiagnostics(source, err)
+}
+
+// InitDefault reads the machine ID from the host operating system again
+// and reinitializes the default generator, if the host identifier can
+// not be read, the fallback set by SetMachineIDFallback is applied,
+// which by default returns an error.
+//
+// The package initialization never fails, if the host identifier can
+// not be read, a random machine ID is used silently. Hardened
+// environments can call InitDefault at program start to detect and
+// handle such failures. On failure, the default generator is unchanged.
+//
+// The machine ID of the default generator is replaced by Reload, thus
+// it is safe to be called concurrently with generating IDs, but IDs
+// generated before it returns may have the previous machine ID.
+func InitDefault() error {
+	defaultInitMu.Lock()
+	defer defaultInitMu.Unlock()
+
+	machineID, mIDType, source, err := readMachineID()
+	if err == nil {
+		defaultGenerator.Reload(func(g *Generator) {
+			g.setMachineID(mIDType, machineID[:])
+		})
+		defaultInitErr = nil
+		setDiagnostics(source, nil)
+		return nil
+	}
+
+	fallback := getMachineIDFallback()
+	id, fbErr := fallback(err)
+	if fbErr != nil {
+		return fbErr
+	}
+	if id == nil {
+		// FallbackRandom, keep the random machine ID read above.
+		defaultGenerator.Reload(func(g *Generator) {
+			g.setMachineID(mIDType, machineID[:])
+		})
+		defaultInitErr = nil
+		setDiagnostics(source, err)
+		return nil
+	}
+	switch len(id) {
+	case 4, 8, 16:
+	default:
+		return errUnsupportedMachineIDLength
+	}
+	defaultGenerator.Reload(func(g *Generator) {
+		g.UseMachineID(id)
+	})
+	defaultInitErr = nil
+	setDiagnostics(SourceFallback, err)
+	return nil
+}
+
+func getDefaultInitErr() error {
+	defaultInitMu.Lock()
+	defer defaultInitMu.Unlock()
+	return defaultInitErr
+}
+
+// An Option configures a Generator created by NewGeneratorE.
+type Option func(g *Generator) error
+
+// WithMachineID is the error-returning counterpart of
+// Generator.UseMachineID.
+func WithMachineID(id []byte) Option {
+	return func(g *Generator) error {
+		switch len(id) {
+		case 4, 8, 16:
+			g.UseMachineID(id)
+			return nil
+		}
+		return errUnsupportedMachineIDLength
+	}
+}
+
+// WithIPv4 is the error-returning counterpart of Generator.UseIPv4.
+func WithIPv4(ip net.IP) Option {
+	return func(g *Generator) error {
+		if ip.To4() == nil {
+			return errors.New("xxid: invalid IPv4 address")
+		}
+		g.UseIPv4(ip)
+		return nil
+	}
+}
+
+// WithHashedIPv4 is the error-returning counterpart of
+// Generator.UseHashedIPv4.
+func WithHashedIPv4(ip net.IP, key []byte) Option {
+	return func(g *Generator) error {
+		if ip.To4() == nil {
+			return errors.New("xxid: invalid IPv4 address")
+		}
+		g.UseHashedIPv4(ip, key)
+		return nil
+	}
+}
+
+// WithIPv6 is the error-returning counterpart of Generator.UseIPv6.
+func WithIPv6(ip net.IP) Option {
+	return func(g *Generator) error {
+		if len(ip) != net.IPv6len {
+			return errors.New("xxid: invalid IPv6 address")
+		}
+		g.UseIPv6(ip)
+		return nil
+	}
+}
+
+// WithPort is the counterpart of Generator.UsePort.
+func WithPort(port uint16) Option {
+	return func(g *Generator) error {
+		g.UsePort(port)
+		return nil
+	}
+}
+
+// WithoutPid is the counterpart of Generator.UseNoPid.
+func WithoutPid() Option {
+	return func(g *Generator) error {
+		g.UseNoPid()
+		return nil
+	}
+}
+
+// WithSequenceMode is the error-returning counterpart of
+// Generator.UseSequenceMode.
+func WithSequenceMode(mode SequenceMode) Option {
+	return func(g *Generator) error {
+		if mode != SharedSequence && mode != IsolatedSequence {
+			return errors.New("xxid: unknown sequence mode")
+		}
+		g.UseSequenceMode(mode)
+		return nil
+	}
+}
+
+// WithEphemeralMachineID is the counterpart of
+// Generator.UseEphemeralMachineID.
+func WithEphemeralMachineID() Option {
+	return func(g *Generator) error {
+		g.UseEphemeralMachineID()
+		return nil
+	}
+}
+
+// WithPrivacyMode is the counterpart of Generator.UsePrivacyMode.
+func WithPrivacyMode() Option {
+	return func(g *Generator) error {
+		g.UsePrivacyMode()
+		return nil
+	}
+}
+
+// WithFlag is the error-returning counterpart of Generator.UseFlag,
+// it returns an error if the flag overflows 15 bits.
+func WithFlag(flag uint16) Option {
+	return func(g *Generator) error {
+		if flag&flagMask != 0 {
+			return errors.New("xxid: flag overflows 15 bits")
+		}
+		g.UseFlag(flag)
+		return nil
+	}
+}
+
+// NewGeneratorE is like NewGenerator, but it applies opts and reports
+// failures as errors instead of panicking.
+//
+// If the host identifier can not be read by the package initialization
+// or InitDefault, and opts don't specify a machine ID, it returns an
+// error, instead of silently using a random machine ID. If opts specify
+// WithEphemeralMachineID or WithPrivacyMode, it returns an error if the
+// system's secure random number generator does not work.
+func NewGeneratorE(opts ...Option) (*Generator, error) {
+	gen := NewGenerator()
+	for _, opt := range opts {
+		if err := opt(gen); err != nil {
+			return nil, err
+		}
+	}
+	if gen.ephemeralMachineID {
+		// Fail early instead of panicking when generating IDs.
+		var probe [8]byte
+		if err := cryptoRandRead(probe[:]); err != nil {
+			return nil, err
+		}
+	} else if err := getDefaultInitErr(); err != nil && gen.mIDType == Random {
+		return nil, err
+	}
+	return gen, nil
 }
 
 // A Generator holds some machine information which is used to generate
 // unique IDs. Some information can be configured by user.
+//
+// The Use* methods are not safe to be called concurrently with
+// generating IDs, they are intended to be used to set up a generator,
+// use Reload to change the configuration of a running generator.
 type Generator struct {
+	genConfig
+
+	seq *sequencer
+
+	reloadMu sync.Mutex
+	reloaded unsafe.Pointer // *genConfig, set by Reload
+
+	atMu       sync.Mutex
+	lastAt     int64 // time and counter of the last ID issued by NewAt
+	lastAtLive bool  // whether the last ID issued by NewAt is ordered with New
+
+	timeGuard *timeGuard // set by UseTimeGuard
+
+	hlcMaxOffset time.Duration // set by UseHybridClock
+}
+
+// genConfig holds the configurable information of a Generator.
+type genConfig struct {
 	mIDType   MachineIDType
 	machineID [16]byte
 	pidOrPort uint16
 	flag      uint16
+
+	ephemeralMachineID bool
+	privacyMode        bool
+	compact            bool
+	principalHash      func(ctx context.Context) uint16
 }
 
 // NewGenerator makes a new generator initialized with same machineID and
@@ -44,15 +277,73 @@ type Generator struct {
 //
 // For general purpose without configuring machine ID, IP, port or flag,
 // New and NewWithTime are recommended in most cases.
+//
+// The generator shares the time and counter state with the default
+// generator and other generators in the process, see UseSequenceMode.
 func NewGenerator() *Generator {
-	gen := &Generator{
-		mIDType:   defaultGenerator.mIDType,
-		machineID: defaultGenerator.machineID,
-		pidOrPort: defaultGenerator.pidOrPort,
-	}
+	cfg := defaultGenerator.config()
+	gen := &Generator{seq: &globalSeq}
+	gen.mIDType = cfg.mIDType
+	gen.machineID = cfg.machineID
+	gen.pidOrPort = cfg.pidOrPort
 	return gen
 }
 
+// Reload atomically replaces the configuration of a running generator.
+// fn is called with a copy of the generator's current configuration,
+// it can change the configuration by calling the Use* methods, e.g.
+//
+//	gen.Reload(func(g *xxid.Generator) {
+//		g.UseFlag(newFlag)
+//	})
+//
+// The time and counter state are not affected, IDs generated after
+// reloading are still guaranteed to be unique and ordered.
+// It is safe to be called concurrently with generating IDs, thus can be
+// used by config watchers to change a generator without restarting the
+// process.
+//
+// Once Reload is called, the Use* methods which change the configuration
+// panic if called on the generator directly, they must be called in fn.
+// The sequence mode, time guard and hybrid clock are not part of the
+// configuration, Reload panics if fn changes them.
+func (g *Generator) Reload(fn func(g *Generator)) {
+	g.reloadMu.Lock()
+	defer g.reloadMu.Unlock()
+	tmp := &Generator{
+		genConfig:    *g.config(),
+		seq:          g.seq,
+		timeGuard:    g.timeGuard,
+		hlcMaxOffset: g.hlcMaxOffset,
+	}
+	fn(tmp)
+	if tmp.seq != g.seq || tmp.timeGuard != g.timeGuard || tmp.hlcMaxOffset != g.hlcMaxOffset {
+		panic(errReloadNonConfig)
+	}
+	cfg := tmp.genConfig
+	atomic.StorePointer(&g.reloaded, unsafe.Pointer(&cfg))
+}
+
+var (
+	errReloadedGenerator = errors.New("xxid: generator is reloaded, change the configuration by Reload")
+	errReloadNonConfig   = errors.New("xxid: sequence mode, time guard and hybrid clock can not be changed by Reload")
+)
+
+func (g *Generator) config() *genConfig {
+	if p := atomic.LoadPointer(&g.reloaded); p != nil {
+		return (*genConfig)(p)
+	}
+	return &g.genConfig
+}
+
+// checkReloaded panics if Reload has been called, the configuration
+// changed by the Use* methods would not take effect.
+func (g *Generator) checkReloaded() {
+	if atomic.LoadPointer(&g.reloaded) != nil {
+		panic(errReloadedGenerator)
+	}
+}
+
 // UseMachineID changes the machine ID of the generator to user
 // specified bytes.
 //
@@ -60,92 +351,312 @@ func NewGenerator() *Generator {
 // the corresponding MachineIDType will be Specified4, Specified8
 // or Specified16.
 func (g *Generator) UseMachineID(id []byte) *Generator {
+	g.checkReloaded()
 	switch len(id) {
 	case 4:
-		g.mIDType = Specified4
-		copy(g.machineID[:4], id)
+		g.setMachineID(Specified4, id)
 	case 8:
-		g.mIDType = Specified8
-		copy(g.machineID[:8], id)
+		g.setMachineID(Specified8, id)
 	case 16:
-		g.mIDType = Specified16
-		copy(g.machineID[:16], id)
+		g.setMachineID(Specified16, id)
 	default:
 		panic(errUnsupportedMachineIDLength)
 	}
 	return g
 }
 
+// UseMachineIDFile sets the generator to use the content of the given
+// file as machine ID, e.g. identity material mounted at nonstandard
+// paths like /etc/pod-identity. Leading and trailing white space of
+// the content is trimmed.
+//
+// If verbatim is false, the content is hashed like the host identifier
+// and the machine ID type will be HostID. If verbatim is true, the
+// content is used as is like UseMachineID, it must be 4, 8 or 16 bytes.
+// It panics if the file can not be read or the length is unsupported,
+// see WithMachineIDFile for the error-returning counterpart.
+func (g *Generator) UseMachineIDFile(path string, verbatim bool) *Generator {
+	g.checkReloaded()
+	if err := WithMachineIDFile(path, verbatim)(g); err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// WithMachineIDFile is the error-returning counterpart of
+// Generator.UseMachineIDFile.
+func WithMachineIDFile(path string, verbatim bool) Option {
+	return func(g *Generator) error {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("xxid: failed to read machine ID file: %v", err)
+		}
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			return fmt.Errorf("xxid: machine ID file %s is empty", path)
+		}
+		if verbatim {
+			return WithMachineID(b)(g)
+		}
+		sum := md5.Sum(b)
+		g.setMachineID(HostID, sum[:4])
+		return nil
+	}
+}
+
 // UseIPv4 sets the generator to use the given IP v4 as machine ID.
 func (g *Generator) UseIPv4(ip net.IP) *Generator {
-	g.mIDType = IPv4
-	copy(g.machineID[:4], ip.To4())
+	g.checkReloaded()
+	g.setMachineID(IPv4, ip.To4())
+	return g
+}
+
+// UseHashedIPv4 sets the generator to use a keyed hash of the given
+// IP v4 as machine ID, the machine ID type will be HashedIPv4.
+//
+// The hash is the first 4 bytes of HMAC-SHA256 of the address with key,
+// thus the raw address is not written into IDs, while IDs are still
+// attributable to hosts by those knowing the key, see ID.MatchHashedIPv4.
+func (g *Generator) UseHashedIPv4(ip net.IP, key []byte) *Generator {
+	g.checkReloaded()
+	h := hashIPv4(ip, key)
+	g.setMachineID(HashedIPv4, h[:])
 	return g
 }
 
 // UseIPv6 sets the generator to use the given IP v6 as machine ID.
 func (g *Generator) UseIPv6(ip net.IP) *Generator {
-	g.mIDType = IPv6
-	copy(g.machineID[:16], ip.To16())
+	g.checkReloaded()
+	g.setMachineID(IPv6, ip.To16())
+	return g
+}
+
+// setMachineID sets the machine ID and turns off the modes which
+// randomize machine ID, the unused bytes of the machine ID are cleared,
+// thus generated IDs are equal to the parsed ones.
+func (g *Generator) setMachineID(typ MachineIDType, id []byte) {
+	g.mIDType = typ
+	g.machineID = [16]byte{}
+	copy(g.machineID[:machineIdLength[typ]], id)
+	g.ephemeralMachineID = false
+	g.privacyMode = false
+}
+
+// UseEphemeralMachineID sets the generator to fill the machine ID of
+// each generated ID with fresh random bytes, the machine ID type will
+// be Random.
+//
+// This trades machine traceability for unlinkability, successive IDs
+// generated by the generator can not be linked to each other or to
+// the host by the machine ID.
+//
+// The random bytes are read from crypto/rand, generating IDs panics if
+// it fails, use NewGeneratorE with WithEphemeralMachineID to check it
+// when setting up the generator.
+func (g *Generator) UseEphemeralMachineID() *Generator {
+	g.checkReloaded()
+	g.mIDType = Random
+	g.machineID = [16]byte{}
+	g.ephemeralMachineID = true
+	return g
+}
+
+// UsePrivacyMode sets the generator to fill the machine ID, pid or port
+// and flag of each generated ID with fresh random bytes, the machine ID
+// type will be Random, and the flag is reported as not set.
+//
+// It is intended for IDs exposed to end users, where leaking internal
+// IP addresses, hosts and processes is unacceptable. Uniqueness of IDs
+// is still ensured by the time and counter. It overrides UsePort,
+// UseFlag and UsePrincipalHash.
+func (g *Generator) UsePrivacyMode() *Generator {
+	g.checkReloaded()
+	g.UseEphemeralMachineID()
+	g.privacyMode = true
 	return g
 }
 
 // UsePort sets the generator to use the given port number.
 func (g *Generator) UsePort(port uint16) *Generator {
+	g.checkReloaded()
 	if port > 0 {
 		g.pidOrPort = port
 	}
 	return g
 }
 
+// UseNoPid sets the generator to zero the pid or port of generated IDs,
+// for deployments running a single process per host, where the pid is
+// noise, and for identifiers exposed externally where process IDs are
+// unwanted. ID.LookupPid and ID.LookupPort of such IDs return false.
+func (g *Generator) UseNoPid() *Generator {
+	g.checkReloaded()
+	g.pidOrPort = 0
+	return g
+}
+
 // UseFlag sets the generator to use the given flag.
 //
 // Note that only 15 bits are allowed for flag, if the highest bit is set,
 // it will be discarded.
 func (g *Generator) UseFlag(flag uint16) *Generator {
+	g.checkReloaded()
 	g.flag = flag | flagMask
 	return g
 }
 
+// UsePrincipalHash sets the generator to fold a hash of the acting
+// principal (user or service) into the flag of IDs generated by
+// NewContext, so that records can be attributed to an actor directly
+// from the ID. fn receives the context passed to NewContext, it may use
+// PrincipalHash to compute the hash.
+//
+// Note that only 15 bits are allowed for flag, the highest bit of the
+// value returned by fn is discarded. It overrides the flag specified
+// by UseFlag for IDs generated by NewContext.
+func (g *Generator) UsePrincipalHash(fn func(ctx context.Context) uint16) *Generator {
+	g.checkReloaded()
+	g.principalHash = fn
+	return g
+}
+
+// PrincipalHash returns a 15 bits hash of principal, it is intended to
+// be used with Generator.UsePrincipalHash, and to compare with ID.Flag
+// when reviewing IDs.
+func PrincipalHash(principal string) uint16 {
+	h := fnv.New32a()
+	h.Write([]byte(principal))
+	x := h.Sum32()
+	return uint16(x^x>>16) & ^uint16(flagMask)
+}
+
 // New generates a unique ID.
 func (g *Generator) New() ID {
-	timeMsec, incr := readTimeAndCounter()
-	return newID(g, timeMsec, incr)
+	timeMsec, incr := g.seq.next()
+	return newID(g.config(), timeMsec, incr)
 }
 
-// NewWithTime generates an ID with the given time.
+// NewContext generates a unique ID, if the generator is configured by
+// UsePrincipalHash, the flag is set to the hash of the principal
+// carried by ctx.
+func (g *Generator) NewContext(ctx context.Context) ID {
+	cfg := g.config()
+	timeMsec, incr := g.seq.next()
+	id := newID(cfg, timeMsec, incr)
+	if cfg.principalHash != nil && !cfg.privacyMode && !cfg.compact {
+		id.flag = cfg.principalHash(ctx) | flagMask
+	}
+	return id
+}
+
+// NewWithTime generates an ID with the given time. If the generator is
+// configured by UseTimeGuard, the ID is protected from duplicates with
+// IDs generated with the same time, else it's the same as
+// NewWithTimeUnsafe.
 func (g *Generator) NewWithTime(t time.Time) ID {
 	timeMsec := t.UnixNano() / 1e6
-	incr := incrCounter()
-	return newID(g, timeMsec, incr)
+	incr := g.seq.incrCounter()
+	if g.timeGuard != nil {
+		timeMsec, incr = g.timeGuard.issue(timeMsec, incr)
+	}
+	return newID(g.config(), timeMsec, incr)
+}
+
+// NewAt generates an ID with the given time like NewWithTime, but routes
+// through a monotonic guard of the generator: if the time and counter
+// is not after the last ID issued by NewAt, it is moved forward right
+// after that one.
+//
+// It gives backfill jobs historical timestamps while keeping IDs issued
+// by NewAt unique and increasing. IDs which are not before the latest
+// ID generated by New are reserved from the same time and counter state
+// as New, thus they never collide with live traffic, note that a time
+// in the future moves IDs generated by New forward as well. IDs with
+// historical times take fresh values of the counter shared with New,
+// like NewWithTime.
+func (g *Generator) NewAt(t time.Time) ID {
+	timeMsec := t.UnixNano() / 1e6
+
+	g.atMu.Lock()
+	tac, live := g.seq.nextAt(timeMsec, g.lastAt, g.lastAtLive)
+	g.lastAt, g.lastAtLive = tac, live
+	g.atMu.Unlock()
+	return newID(g.config(), tac>>16, uint16(tac))
 }
 
+// FromShort rebuilds an ID from a value returned by ID.Short, the
+// machine ID, pid or port and flag are filled from the generator, thus
+// compact foreign keys can be expanded back to full IDs generated by
+// the same generator. The flag is not randomized, it is zero unless the
+// generator is configured by UseFlag.
+//
+// It returns an error if short is negative, or if the generator has no
+// fixed machine ID, see UseEphemeralMachineID and UsePrivacyMode.
+func (g *Generator) FromShort(short int64) (ID, error) {
+	cfg := g.config()
+	if short < 0 {
+		return zeroID, errInvalidShort
+	}
+	if cfg.ephemeralMachineID {
+		return zeroID, errNoFixedMachineID
+	}
+	return fromShort(cfg, short), nil
+}
+
+// readHostID, readHostname and cryptoRandReader are variables to be
+// replaced in tests.
+var (
+	readHostID       = machineid.ID
+	readHostname     = os.Hostname
+	cryptoRandReader = cryptorand.Reader
+)
+
 // readMachineID reads machine ID from the host operating system.
 // If it fails to get machine ID from the host, it returns a random value.
-func readMachineID() ([4]byte, MachineIDType) {
+// The returned source tells where the machine ID comes from, and the
+// error tells why the host identifier can not be read.
+func readMachineID() ([4]byte, MachineIDType, string, error) {
 	var id [4]byte
-	hid, err := machineid.ID()
+	source := SourcePlatform
+	hid, err := readHostID()
 	if err != nil || len(hid) == 0 {
-		hid, err = os.Hostname()
+		source = SourceHostname
+		hid, err = readHostname()
 	}
 	if err == nil && len(hid) != 0 {
 		hw := md5.New()
 		hw.Write([]byte(hid))
 		copy(id[:], hw.Sum(nil))
-		return id, HostID
+		return id, HostID, source, nil
+	}
+	if err == nil {
+		err = errors.New("empty host identifier")
 	}
+	err = errors.New("xxid: failed to read machine ID: " + err.Error())
 
 	// Fallback to rand number if machine id can't be gathered.
-	x := runtime_fastrand()
+	x := fastrand()
 	id[0] = byte(x >> 24)
 	id[1] = byte(x >> 16)
 	id[2] = byte(x >> 8)
 	id[3] = byte(x)
-	return id, Random
+	return id, Random, SourceRandom, err
 }
 
+// readProcessID returns a non-zero 16 bits value derived from the pid,
+// zero is reserved for generators configured by UseNoPid.
 func readProcessID() uint16 {
 	pid := uint16(os.Getpid())
+	if runtime.GOOS == "linux" {
+		pid = mixCpuset(pid)
+	}
+	if pid == 0 {
+		pid = 1
+	}
+	return pid
+}
+
+func mixCpuset(pid uint16) uint16 {
 	// If /proc/self/cpuset exists and is not /, we can assume that we are in a
 	// form of container and use the content of cpuset xor-ed with the PID in
 	// order to get a reasonable machine global unique PID.
@@ -156,34 +667,25 @@ func readProcessID() uint16 {
 	return pid
 }
 
-func randFlag() uint16 {
-	return uint16(runtime_fastrand() >> 17)
+// cryptoRandRead fills b with cryptographically secure random bytes,
+// it returns an error if the system's secure random number generator
+// fails.
+func cryptoRandRead(b []byte) error {
+	if _, err := io.ReadFull(cryptoRandReader, b); err != nil {
+		return errors.New("xxid: failed to read random bytes: " + err.Error())
+	}
+	return nil
 }
 
-func incrCounter() uint16 {
-	return uint16(atomic.AddUint32(&counter, 1))
+// mustCryptoRandRead is like cryptoRandRead but panics on failure, it
+// is used when generating IDs, where crypto/rand has been checked by
+// NewGeneratorE if the caller cares.
+func mustCryptoRandRead(b []byte) {
+	if err := cryptoRandRead(b); err != nil {
+		panic(err)
+	}
 }
 
-var (
-	incrMu         sync.Mutex
-	timeAndCounter int64
-)
-
-// readTimeAndCounter guarantees that the combination of the returned
-// time and counter will never be duplicate inside a process, even the
-// clock has been turned back or leap second happens.
-func readTimeAndCounter() (timeMsec int64, counter uint16) {
-	t := time.Now().UnixNano() / 1e6
-	c := incrCounter()
-	tac := t<<16 | int64(c) // time and counter
-
-	incrMu.Lock()
-	prev := timeAndCounter
-	if tac <= prev {
-		tac = prev + 1
-		t, c = tac>>16, uint16(tac)
-	}
-	timeAndCounter = tac
-	incrMu.Unlock()
-	return t, c
+func randFlag() uint16 {
+	return uint16(fastrand() >> 17)
 }
@@ -2,8 +2,15 @@ package xxid
 
 import (
 	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGenerator(t *testing.T) {
@@ -50,8 +57,336 @@ func TestGenerator(t *testing.T) {
 	}
 }
 
+func TestGenerator_UseEphemeralMachineID(t *testing.T) {
+	gen := NewGenerator().UseEphemeralMachineID()
+	id1, id2 := gen.New(), gen.New()
+	if id1.MachineIDType() != Random || id2.MachineIDType() != Random {
+		t.Fatalf("ephemeral machine ID type should be Random")
+	}
+	if bytes.Equal(id1.MachineID(), id2.MachineID()) {
+		t.Fatalf("ephemeral machine IDs should be different")
+	}
+
+	machineID := []byte{1, 2, 3, 4}
+	gen.UseMachineID(machineID)
+	if !bytes.Equal(gen.New().MachineID(), machineID) {
+		t.Fatalf("UseMachineID should disable ephemeral machine ID")
+	}
+}
+
+func TestGenerator_UsePrivacyMode(t *testing.T) {
+	gen := NewGenerator().UsePort(8888).UseFlag(123).UsePrivacyMode()
+	seen := make(map[[3]uint64]bool)
+	for i := 0; i < 10; i++ {
+		id := gen.New()
+		if id.MachineIDType() != Random || id.Flag() != 0 {
+			t.Fatalf("privacy mode should use Random machine ID without flag")
+		}
+		var key [3]uint64
+		key[0] = uint64(beEnc.Uint32(id.MachineID()))
+		key[1] = uint64(id.Pid())
+		key[2] = uint64(id.flag)
+		if seen[key] {
+			t.Fatalf("privacy mode generated duplicate host information")
+		}
+		seen[key] = true
+	}
+}
+
+func TestGenerator_UsePrincipalHash(t *testing.T) {
+	type ctxKey struct{}
+	gen := NewGenerator().UseFlag(1).UsePrincipalHash(func(ctx context.Context) uint16 {
+		user, _ := ctx.Value(ctxKey{}).(string)
+		return PrincipalHash(user)
+	})
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "alice")
+	id := gen.NewContext(ctx)
+	if id.Flag() != PrincipalHash("alice") {
+		t.Fatalf("principal hash not match, got= %v", id.Flag())
+	}
+	if PrincipalHash("alice") == PrincipalHash("bob") {
+		t.Fatalf("principal hash should be different")
+	}
+	if gen.New().Flag() != 1 {
+		t.Fatalf("New should not use principal hash")
+	}
+}
+
+func TestGenerator_Reload(t *testing.T) {
+	gen := NewGenerator().UseFlag(1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		prev := gen.New()
+		for i := 0; i < 10000; i++ {
+			id := gen.New()
+			if id.Short() <= prev.Short() {
+				t.Errorf("IDs not ordered after reloading")
+				return
+			}
+			prev = id
+		}
+	}()
+	for i := 2; i < 100; i++ {
+		flag := uint16(i)
+		gen.Reload(func(g *Generator) {
+			g.UseFlag(flag)
+		})
+	}
+	<-done
+
+	if got := gen.New().Flag(); got != 99 {
+		t.Fatalf("reloaded flag not match, got= %v", got)
+	}
+}
+
+func TestGenerator_Reload_panics(t *testing.T) {
+	expectPanic := func(name string, err error, fn func()) {
+		defer func() {
+			if r := recover(); r != err {
+				t.Fatalf("%v: panic not match, want= %v, got= %v", name, err, r)
+			}
+		}()
+		fn()
+	}
+
+	gen := NewGenerator().UseFlag(1)
+	gen.Reload(func(g *Generator) { g.UseFlag(2) })
+	expectPanic("UseFlag", errReloadedGenerator, func() { gen.UseFlag(3) })
+	expectPanic("UsePort", errReloadedGenerator, func() { gen.UsePort(8888) })
+	expectPanic("UseCompactLayout", errReloadedGenerator, func() { gen.UseCompactLayout() })
+	if got := gen.New().Flag(); got != 2 {
+		t.Fatalf("reloaded flag not match, want= %v, got= %v", 2, got)
+	}
+
+	expectPanic("UseSequenceMode", errReloadNonConfig, func() {
+		gen.Reload(func(g *Generator) { g.UseSequenceMode(IsolatedSequence) })
+	})
+	expectPanic("UseTimeGuard", errReloadNonConfig, func() {
+		gen.Reload(func(g *Generator) { g.UseTimeGuard() })
+	})
+	expectPanic("UseHybridClock", errReloadNonConfig, func() {
+		gen.Reload(func(g *Generator) { g.UseHybridClock(time.Second) })
+	})
+	if gen.SequenceMode() != SharedSequence {
+		t.Fatalf("sequence mode should not be changed by Reload")
+	}
+}
+
+func TestNewGeneratorE(t *testing.T) {
+	gen, err := NewGeneratorE(
+		WithIPv4(net.ParseIP("10.9.8.7")),
+		WithPort(8888),
+		WithFlag(123),
+	)
+	if err != nil {
+		t.Fatalf("failed create generator, err= %v", err)
+	}
+	id := gen.New()
+	if id.IPPortAddr() != "10.9.8.7:8888" || id.Flag() != 123 {
+		t.Fatalf("generator options not applied, got= %v", id.Inspect())
+	}
+
+	for _, opt := range []Option{
+		WithMachineID([]byte{1, 2, 3}),
+		WithIPv4(net.ParseIP("::1")),
+		WithIPv6(net.IP{1, 2, 3, 4}),
+		WithFlag(flagMask),
+	} {
+		if _, err = NewGeneratorE(opt); err == nil {
+			t.Fatalf("expect error for invalid option")
+		}
+	}
+
+	saved := defaultInitErr
+	defer func() { defaultInitErr = saved }()
+	defaultInitErr = errors.New("test error")
+	defer saveDefaultGenerator()()
+	defaultGenerator.mIDType = Random
+	if _, err = NewGeneratorE(); err == nil {
+		t.Fatalf("expect error when machine ID can not be read")
+	}
+	if _, err = NewGeneratorE(WithMachineID([]byte{1, 2, 3, 4})); err != nil {
+		t.Fatalf("specified machine ID should not fail, err= %v", err)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+func TestNewGeneratorE_cryptoRand(t *testing.T) {
+	for _, opt := range []Option{WithEphemeralMachineID(), WithPrivacyMode()} {
+		gen, err := NewGeneratorE(opt)
+		if err != nil {
+			t.Fatalf("failed create generator, err= %v", err)
+		}
+		if id := gen.New(); id.MachineIDType() != Random {
+			t.Fatalf("machine ID type not match, got= %v", id.MachineIDType())
+		}
+	}
+
+	saved := cryptoRandReader
+	defer func() { cryptoRandReader = saved }()
+	cryptoRandReader = failingReader{}
+	for _, opt := range []Option{WithEphemeralMachineID(), WithPrivacyMode()} {
+		if _, err := NewGeneratorE(opt); err == nil {
+			t.Fatalf("expect error when crypto/rand fails")
+		}
+	}
+	if _, err := NewGeneratorE(WithMachineID([]byte{1, 2, 3, 4})); err != nil {
+		t.Fatalf("fixed machine ID should not need crypto/rand, err= %v", err)
+	}
+}
+
+func TestGenerator_UseMachineIDFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xxid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	hashed := filepath.Join(dir, "pod-identity")
+	verbatim := filepath.Join(dir, "verbatim")
+	ioutil.WriteFile(hashed, []byte("pod-12345\n"), 0644)
+	ioutil.WriteFile(verbatim, []byte("abcdefgh"), 0644)
+
+	id := NewGenerator().UseMachineIDFile(hashed, false).New()
+	sum := md5.Sum([]byte("pod-12345"))
+	if id.MachineIDType() != HostID || !bytes.Equal(id.MachineID(), sum[:4]) {
+		t.Fatalf("hashed machine ID file not match, got= %v", id.Inspect())
+	}
+
+	id = NewGenerator().UseMachineIDFile(verbatim, true).New()
+	if id.MachineIDType() != Specified8 || string(id.MachineID()) != "abcdefgh" {
+		t.Fatalf("verbatim machine ID file not match, got= %v", id.Inspect())
+	}
+	if got, _ := ParseBinary(id.Binary()); got != id {
+		t.Fatalf("parsed ID not match")
+	}
+
+	if _, err := NewGeneratorE(WithMachineIDFile(filepath.Join(dir, "missing"), false)); err == nil {
+		t.Fatalf("expect error for missing file")
+	}
+	if _, err := NewGeneratorE(WithMachineIDFile(hashed, true)); err == nil {
+		t.Fatalf("expect error for unsupported length")
+	}
+}
+
 func Benchmark_readTimeAndCounter(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, _ = readTimeAndCounter()
 	}
 }
+
+func TestGenerator_UseHashedIPv4(t *testing.T) {
+	ip := net.ParseIP("10.9.8.7")
+	key := []byte("secret")
+	id := NewGenerator().UseHashedIPv4(ip, key).New()
+	if id.MachineIDType() != HashedIPv4 {
+		t.Fatalf("machine ID type not match, got= %v", id.MachineIDType())
+	}
+	if id.IP() != nil || bytes.Equal(id.MachineID(), ip.To4()) {
+		t.Fatalf("raw IP should not be written into ID")
+	}
+	if !id.MatchHashedIPv4(ip, key) {
+		t.Fatalf("hashed IPv4 not match")
+	}
+	if id.MatchHashedIPv4(ip, []byte("other")) || id.MatchHashedIPv4(net.ParseIP("10.9.8.6"), key) {
+		t.Fatalf("hashed IPv4 should not match with other key or IP")
+	}
+	if NewGenerator().UseMachineID(id.MachineID()).New().MatchHashedIPv4(ip, key) {
+		t.Fatalf("Specified4 ID should not match as hashed IPv4")
+	}
+	parsed, err := ParseBase62(id.Base62())
+	if err != nil || parsed != id {
+		t.Fatalf("failed parse hashed IPv4 ID, err= %v", err)
+	}
+}
+
+func TestGenerator_FromShort(t *testing.T) {
+	gen := NewGenerator().UseMachineID([]byte("12345678")).UsePort(8080).UseFlag(7)
+	id := gen.New()
+	got, err := gen.FromShort(id.Short())
+	if err != nil || got != id {
+		t.Fatalf("FromShort result not match, err= %v", err)
+	}
+
+	first, _ := gen.Reserve(3)
+	for i := int64(0); i < 3; i++ {
+		got, err := gen.FromShort(first + i)
+		if err != nil || got.Short() != first+i || got.Port() != 8080 {
+			t.Fatalf("failed expand reserved short value, err= %v", err)
+		}
+	}
+
+	if _, err := gen.FromShort(-1); err == nil {
+		t.Fatalf("expect error for negative short value")
+	}
+	if _, err := NewGenerator().UsePrivacyMode().FromShort(id.Short()); err == nil {
+		t.Fatalf("expect error for generator without fixed machine ID")
+	}
+}
+
+func TestGenerator_UseNoPid(t *testing.T) {
+	if pid, ok := New().LookupPid(); !ok || pid == 0 {
+		t.Fatalf("default generator should have a non-zero pid")
+	}
+
+	gen, err := NewGeneratorE(WithPort(8080), WithoutPid())
+	if err != nil {
+		t.Fatalf("failed create generator, err= %v", err)
+	}
+	id := gen.New()
+	if _, ok := id.LookupPid(); ok {
+		t.Fatalf("pid should be omitted")
+	}
+	if port, ok := id.LookupPort(); ok || port != 0 {
+		t.Fatalf("port should be omitted")
+	}
+	if _, ok := gen.UsePort(8080).New().LookupPort(); !ok {
+		t.Fatalf("port should be set after UsePort")
+	}
+}
+
+func TestGenerator_NewAt(t *testing.T) {
+	gen := NewGenerator()
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := gen.NewAt(ts)
+	if !first.Time().Equal(ts) {
+		t.Fatalf("time should be honored, got= %v", first.Time())
+	}
+
+	gen.SetCounter(first.Counter())
+	second := gen.NewAt(ts)
+	if second.Short() <= first.Short() {
+		t.Fatalf("ID should be moved forward, first= %v, second= %v", first.Inspect(), second.Inspect())
+	}
+	behind := gen.NewAt(ts.Add(-time.Hour))
+	if behind.Short() <= second.Short() {
+		t.Fatalf("ID behind the last issued should be moved forward")
+	}
+	if later := gen.NewAt(ts.Add(time.Hour)); !later.Time().Equal(ts.Add(time.Hour)) {
+		t.Fatalf("later time should be honored")
+	}
+}
+
+func TestGenerator_NewAt_live(t *testing.T) {
+	for _, gen := range []*Generator{
+		NewGenerator().UseFlag(1).UseSequenceMode(IsolatedSequence),
+		NewGenerator().UseCompactLayout().UseSequenceMode(IsolatedSequence),
+	} {
+		for i := 0; i < 1000; i++ {
+			now := time.Now()
+			first, live := gen.NewAt(now), gen.New()
+			behind := gen.NewAt(now.Add(-time.Hour))
+			if behind == live || CompareIDs(behind, first) <= 0 {
+				t.Fatalf("NewAt not match, first= %v, live= %v, behind= %v",
+					first.Inspect(), live.Inspect(), behind.Inspect())
+			}
+			if next := gen.New(); next == behind || next == first {
+				t.Fatalf("New collides with NewAt, id= %v", next.Inspect())
+			}
+		}
+	}
+}
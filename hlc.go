@@ -0,0 +1,79 @@
+package xxid
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	errHybridClockDisabled = errors.New("xxid: hybrid logical clock is not enabled")
+	errClockOffsetExceeded = errors.New("xxid: observed ID is too far ahead of the local clock")
+)
+
+// UseHybridClock sets the generator to the hybrid logical clock mode,
+// in which the generator merges the local wall time with the max
+// timestamp of IDs observed from peers by Observe, thus IDs generated
+// after observing an ID are ordered after it, which keeps the ordering
+// of IDs consistent with causality across loosely synchronized machines.
+//
+// maxOffset bounds how far an observed ID may be ahead of the local
+// wall clock, IDs further ahead are rejected by Observe, thus a peer
+// with a bad clock can not push the generator far into the future.
+// A non-positive maxOffset disables the mode.
+//
+// Note that the time and counter state is shared by all generators in
+// the process, unless the generator uses IsolatedSequence, thus an
+// observed ID affects them all.
+func (g *Generator) UseHybridClock(maxOffset time.Duration) *Generator {
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	g.hlcMaxOffset = maxOffset
+	return g
+}
+
+// WithHybridClock is the error-returning counterpart of
+// Generator.UseHybridClock, it returns an error if maxOffset is not
+// positive.
+func WithHybridClock(maxOffset time.Duration) Option {
+	return func(g *Generator) error {
+		if maxOffset <= 0 {
+			return errors.New("xxid: max clock offset must be positive")
+		}
+		g.UseHybridClock(maxOffset)
+		return nil
+	}
+}
+
+// Observe merges the timestamp of an ID received from a peer, e.g. in
+// a request or message, into the generator's clock, IDs generated by
+// New afterwards are strictly later in time than id. If the local clock
+// is ahead of id, it's a no-op.
+//
+// It returns an error if the generator is not in the hybrid logical
+// clock mode, or id is more than the max offset ahead of the local wall
+// clock, see UseHybridClock. How far the generator runs ahead of the
+// wall clock is reported by Stats as Borrowed.
+func (g *Generator) Observe(id ID) error {
+	if g.hlcMaxOffset <= 0 {
+		return errHybridClockDisabled
+	}
+	if id.timeMsec > wallClockMsec()+int64(g.hlcMaxOffset/time.Millisecond) {
+		return errClockOffsetExceeded
+	}
+	g.seq.observe(id.timeMsec)
+	return nil
+}
+
+// observe moves the time and counter state forward to the end of the
+// millisecond timeMsec, thus the next combination is in a later
+// millisecond. The time borrowed is not counted as clock regressions,
+// which are detected by comparing wall clock readings.
+func (s *sequencer) observe(timeMsec int64) {
+	tac := timeMsec<<16 | 0xffff
+	s.mu.Lock()
+	if tac > s.timeAndCounter {
+		s.timeAndCounter = tac
+	}
+	s.mu.Unlock()
+}
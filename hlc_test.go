@@ -0,0 +1,47 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerator_Observe(t *testing.T) {
+	gen := NewGenerator().UseSequenceMode(IsolatedSequence)
+	peer := NewWithTime(time.Now().Add(time.Second))
+	if err := gen.Observe(peer); err == nil {
+		t.Fatalf("expect error when hybrid clock is not enabled")
+	}
+
+	gen.UseHybridClock(time.Minute)
+	if err := gen.Observe(peer); err != nil {
+		t.Fatalf("failed observe, err= %v", err)
+	}
+	prev := peer
+	for i := 0; i < 1000; i++ {
+		id := gen.New()
+		if !id.Time().After(peer.Time()) || CompareIDs(id, prev) <= 0 {
+			t.Fatalf("ID not ordered after observed one: %v, %v", id.Inspect(), prev.Inspect())
+		}
+		prev = id
+	}
+	stats := gen.Stats()
+	if stats.ClockRegressions != 0 || stats.Borrowed <= 0 {
+		t.Fatalf("stats not match, got= %+v", stats)
+	}
+
+	// observing an older ID is a no-op
+	before := gen.CounterState()
+	if err := gen.Observe(NewWithTime(time.Now().Add(-time.Hour))); err != nil {
+		t.Fatalf("failed observe, err= %v", err)
+	}
+	if after := gen.CounterState(); after != before {
+		t.Fatalf("older observed ID should not affect the clock, got= %+v", after)
+	}
+
+	if err := gen.Observe(NewWithTime(time.Now().Add(time.Hour))); err == nil {
+		t.Fatalf("expect error for ID too far ahead")
+	}
+	if _, err := NewGeneratorE(WithHybridClock(0)); err == nil {
+		t.Fatalf("expect error for non-positive max offset")
+	}
+}
@@ -0,0 +1,40 @@
+package xxid
+
+import (
+	"crypto/sha256"
+	"time"
+)
+
+// DefaultIdempotencyWindow is the time window used by IdempotencyID.
+const DefaultIdempotencyWindow = time.Minute
+
+// IdempotencyID derives a deterministic ID from a digest of a request
+// payload, with the time bucketed by DefaultIdempotencyWindow, see
+// IdempotencyIDWindow.
+func IdempotencyID(generatorTime time.Time, payloadHash []byte) ID {
+	return IdempotencyIDWindow(generatorTime, payloadHash, DefaultIdempotencyWindow)
+}
+
+// IdempotencyIDWindow derives a deterministic ID from a digest of a
+// request payload, so that retries of a request map to the same ID
+// without a coordination store.
+//
+// The time of the ID is generatorTime truncated to window, the counter,
+// machine ID, pid and flag are filled with a SHA-256 hash of payloadHash,
+// the machine ID type is Specified8 and the flag is reported as not set.
+// Note that retries which cross a window boundary get different IDs.
+func IdempotencyIDWindow(generatorTime time.Time, payloadHash []byte, window time.Duration) ID {
+	if window > 0 {
+		generatorTime = generatorTime.Truncate(window)
+	}
+	h := sha256.Sum256(payloadHash)
+	id := ID{
+		timeMsec:  generatorTime.UnixNano() / 1e6,
+		counter:   beEnc.Uint16(h[0:2]),
+		pidOrPort: beEnc.Uint16(h[10:12]),
+		flag:      beEnc.Uint16(h[12:14]) & ^uint16(flagMask),
+		mIDType:   Specified8,
+	}
+	copy(id.machineID[:8], h[2:10])
+	return id
+}
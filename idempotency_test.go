@@ -0,0 +1,31 @@
+package xxid
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyID(t *testing.T) {
+	ts := time.Date(2021, 11, 20, 9, 21, 40, 0, time.UTC)
+	h1 := sha256.Sum256([]byte(`{"amount":100}`))
+	h2 := sha256.Sum256([]byte(`{"amount":200}`))
+
+	id := IdempotencyID(ts, h1[:])
+	if got := IdempotencyID(ts.Add(15*time.Second), h1[:]); got != id {
+		t.Fatalf("retry in the same window should get the same ID")
+	}
+	if got := IdempotencyID(ts, h2[:]); got == id {
+		t.Fatalf("different payloads should get different IDs")
+	}
+	if got := IdempotencyID(ts.Add(time.Minute), h1[:]); got == id {
+		t.Fatalf("different windows should get different IDs")
+	}
+	if !id.Time().Equal(ts.Truncate(time.Minute)) || id.HasFlag() {
+		t.Fatalf("idempotency ID content not match, got= %v", id.Inspect())
+	}
+	parsed, err := ParseBase62(id.Base62())
+	if err != nil || parsed != id {
+		t.Fatalf("failed parse idempotency ID, err= %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package xxid
+
+import (
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+var machineIDTypeNames = [...]string{
+	Random:      "Random",
+	HostID:      "HostID",
+	IPv4:        "IPv4",
+	IPv6:        "IPv6",
+	Specified4:  "Specified4",
+	Specified8:  "Specified8",
+	Specified16: "Specified16",
+	HashedIPv4:  "HashedIPv4",
+}
+
+// Inspect returns a human readable description of the ID's content,
+// it is intended for logging and debugging, the format may change.
+// The result depends only on the ID, use FormatAge for its age.
+//
+// Example:
+//
+//	time=2021-11-20T09:21:40.634+08:00 utc=2021-11-20T01:21:40.634Z machineIDType=HostID machineID=218b67c8 pid=171 counter=59141 flag=0
+func (id ID) Inspect() string {
+	const layout = "2006-01-02T15:04:05.000Z07:00"
+	t := id.Time()
+	buf := make([]byte, 0, 192)
+	buf = append(buf, "time="...)
+	buf = t.AppendFormat(buf, layout)
+	buf = append(buf, " utc="...)
+	buf = t.UTC().AppendFormat(buf, layout)
+	buf = append(buf, " machineIDType="...)
+	if id.mIDType <= maxMachineIDType {
+		buf = append(buf, machineIDTypeNames[id.mIDType]...)
+	} else {
+		buf = strconv.AppendInt(buf, int64(id.mIDType), 10)
+	}
+	if ip := id.IP(); ip != nil {
+		buf = append(buf, " ip="...)
+		buf = append(buf, ip.String()...)
+		buf = append(buf, " port="...)
+	} else {
+		buf = append(buf, " machineID="...)
+		buf = append(buf, hex.EncodeToString(id.MachineID())...)
+		buf = append(buf, " pid="...)
+	}
+	buf = strconv.AppendUint(buf, uint64(id.pidOrPort), 10)
+	buf = append(buf, " counter="...)
+	buf = strconv.AppendUint(buf, uint64(id.counter), 10)
+	if id.compact {
+		buf = append(buf, " layout=compact"...)
+	} else {
+		buf = append(buf, " flag="...)
+		buf = strconv.AppendUint(buf, uint64(id.Flag()), 10)
+	}
+	return b2s(buf)
+}
+
+// FormatAge formats the age of an ID, e.g. time.Since(id.Time()), as a
+// human readable relative time, such as "3h12m ago", "2d5h ago" or
+// "in 5m0s" for a negative age, only the two most significant units are
+// kept. Ages less than a second are formatted in milliseconds.
+func FormatAge(age time.Duration) string {
+	suffix := " ago"
+	prefix := ""
+	if age < 0 {
+		age = -age
+		prefix, suffix = "in ", ""
+	}
+	var buf []byte
+	buf = append(buf, prefix...)
+	switch {
+	case age >= 24*time.Hour:
+		buf = appendUnits(buf, age, 24*time.Hour, "d", time.Hour, "h")
+	case age >= time.Hour:
+		buf = appendUnits(buf, age, time.Hour, "h", time.Minute, "m")
+	case age >= time.Minute:
+		buf = appendUnits(buf, age, time.Minute, "m", time.Second, "s")
+	case age >= time.Second:
+		buf = strconv.AppendInt(buf, int64(age/time.Second), 10)
+		buf = append(buf, 's')
+	default:
+		buf = strconv.AppendInt(buf, int64(age/time.Millisecond), 10)
+		buf = append(buf, "ms"...)
+	}
+	buf = append(buf, suffix...)
+	return b2s(buf)
+}
+
+func appendUnits(buf []byte, d, unit1 time.Duration, name1 string, unit2 time.Duration, name2 string) []byte {
+	buf = strconv.AppendInt(buf, int64(d/unit1), 10)
+	buf = append(buf, name1...)
+	buf = strconv.AppendInt(buf, int64(d%unit1/unit2), 10)
+	buf = append(buf, name2...)
+	return buf
+}
+
+// TemplateFuncs returns functions to be used in html/template and
+// text/template, the returned map can be passed to Template.Funcs.
+//
+// The functions accept an ID, a pointer to ID, or a string or bytes
+// in base62 form or string form:
+//
+//	xxid_short    returns ID.Short
+//	xxid_time     returns ID.Time
+//	xxid_inspect  returns ID.Inspect
+//	xxid_string   returns ID.String
+//	xxid_base62   returns ID.Base62 as a string
+//
+// Example:
+//
+//	tmpl := template.New("").Funcs(xxid.TemplateFuncs())
+//	tmpl.Parse(`{{ xxid_time .ID }}`)
+func TemplateFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"xxid_short": func(v interface{}) (int64, error) {
+			id, err := toID(v)
+			return id.Short(), err
+		},
+		"xxid_time": func(v interface{}) (time.Time, error) {
+			id, err := toID(v)
+			return id.Time(), err
+		},
+		"xxid_inspect": func(v interface{}) (string, error) {
+			id, err := toID(v)
+			return id.Inspect(), err
+		},
+		"xxid_string": func(v interface{}) (string, error) {
+			id, err := toID(v)
+			return id.String(), err
+		},
+		"xxid_base62": func(v interface{}) (string, error) {
+			id, err := toID(v)
+			return string(id.Base62()), err
+		},
+	}
+}
+
+func toID(v interface{}) (ID, error) {
+	switch x := v.(type) {
+	case ID:
+		return x, nil
+	case *ID:
+		if x != nil {
+			return *x, nil
+		}
+		return zeroID, nil
+	case string:
+		return Parse(x)
+	case []byte:
+		return parseText(x)
+	}
+	return zeroID, errUnsupportedType(v)
+}
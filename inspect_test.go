@@ -0,0 +1,74 @@
+package xxid
+
+import (
+	"bytes"
+	"html/template"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestID_Inspect(t *testing.T) {
+	gen := NewGenerator().UseIPv4(net.ParseIP("10.9.8.7")).UsePort(8888).UseFlag(123)
+	got := gen.New().Inspect()
+	for _, want := range []string{"machineIDType=IPv4", "ip=10.9.8.7", "port=8888", "flag=123"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Inspect result %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	for _, x := range []struct {
+		age  time.Duration
+		want string
+	}{
+		{0, "0ms ago"},
+		{345 * time.Millisecond, "345ms ago"},
+		{42*time.Second + 500*time.Millisecond, "42s ago"},
+		{12*time.Minute + 5*time.Second, "12m5s ago"},
+		{3*time.Hour + 12*time.Minute + 59*time.Second, "3h12m ago"},
+		{50 * time.Hour, "2d2h ago"},
+		{-5 * time.Minute, "in 5m0s"},
+	} {
+		if got := FormatAge(x.age); got != x.want {
+			t.Fatalf("age not match, want= %v, got= %v", x.want, got)
+		}
+	}
+
+	id := NewWithTime(time.Now().Add(-2 * time.Hour))
+	got := id.Inspect()
+	utc := "utc=" + id.Time().UTC().Format("2006-01-02T15:04:05.000Z")
+	if !strings.Contains(got, utc) || strings.Contains(got, "age=") {
+		t.Fatalf("Inspect result %q does not match", got)
+	}
+	if got != id.Inspect() {
+		t.Fatalf("Inspect result should be deterministic")
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	id := New()
+	tmpl := template.Must(template.New("").Funcs(TemplateFuncs()).Parse(
+		`{{ xxid_short .ID }}|{{ xxid_inspect .Ptr }}|{{ xxid_base62 .Str }}`))
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]interface{}{
+		"ID":  id,
+		"Ptr": &id,
+		"Str": id.String(),
+	})
+	if err != nil {
+		t.Fatalf("failed execute template, err= %v", err)
+	}
+	parts := strings.Split(buf.String(), "|")
+	if len(parts) != 3 || parts[1] != template.HTMLEscapeString(id.Inspect()) ||
+		parts[2] != string(id.Base62()) {
+		t.Fatalf("template output not match, got= %v", buf.String())
+	}
+
+	buf.Reset()
+	if err = tmpl.Execute(&buf, map[string]interface{}{"ID": 1}); err == nil {
+		t.Fatalf("expect error for unsupported type")
+	}
+}
@@ -0,0 +1,97 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShortIDGenerator(t *testing.T) {
+	epoch := time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewInstagramGenerator(epoch, func(key int64) uint16 {
+		return uint16(key % 2000)
+	})
+
+	before := time.Now().Add(-time.Millisecond)
+	var prev int64
+	for i := 0; i < 5000; i++ {
+		id := gen.New(31341)
+		if id <= prev {
+			t.Fatalf("IDs should be increasing")
+		}
+		prev = id
+		if shard := gen.Shard(id); shard != 31341%2000 {
+			t.Fatalf("shard not match, got= %v", shard)
+		}
+	}
+	if l := gen.Layout(); l.TimeBits+l.ShardBits+l.CounterBits != 63 {
+		t.Fatalf("layout bits not match, want= %v, got= %v", 63, l.TimeBits+l.ShardBits+l.CounterBits)
+	}
+	if got := gen.Time(prev); got.Before(before) || got.After(time.Now().Add(time.Second)) {
+		t.Fatalf("time not match, got= %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expect panic for shard out of range")
+		}
+	}()
+	NewInstagramGenerator(epoch, func(int64) uint16 { return MaxInstagramShard + 1 }).New(1)
+}
+
+func TestShortLayout(t *testing.T) {
+	id := New()
+	ts, shard, counter := DefaultShortLayout.Parse(id.Short())
+	if !ts.Equal(id.Time()) || shard != 0 || counter != uint32(id.Counter()) {
+		t.Fatalf("default layout does not match ID.Short")
+	}
+
+	layout := ShortLayout{TimeBits: 41, CounterBits: 22, Epoch: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	gen, err := NewShortIDGenerator(layout, nil)
+	if err != nil {
+		t.Fatalf("failed create generator, err= %v", err)
+	}
+	var prev int64
+	for i := 0; i < 100000; i++ {
+		short := gen.New(0)
+		if short <= prev {
+			t.Fatalf("IDs should be increasing")
+		}
+		prev = short
+	}
+	if gen.Time(prev).Sub(time.Now()) > time.Second {
+		t.Fatalf("time should not be borrowed too far with 22 counter bits")
+	}
+
+	for _, bad := range []ShortLayout{
+		{TimeBits: 41, ShardBits: 1, CounterBits: 23},
+		{TimeBits: 41, CounterBits: 0},
+		{TimeBits: 0, CounterBits: 16},
+		{TimeBits: 41, ShardBits: 17, CounterBits: 1},
+		{TimeBits: 42, CounterBits: 22},
+	} {
+		if _, err := NewShortIDGenerator(bad, nil); err == nil {
+			t.Fatalf("expect error for layout %+v", bad)
+		}
+	}
+}
+
+func TestShortIDGenerator_timeRange(t *testing.T) {
+	for _, epoch := range []time.Time{
+		time.Now().Add(time.Hour),      // before epoch
+		time.Now().Add(-3 * time.Hour), // out of 23 time bits
+	} {
+		layout := ShortLayout{TimeBits: 23, CounterBits: 16, Epoch: epoch}
+		gen, err := NewShortIDGenerator(layout, nil)
+		if err != nil {
+			t.Fatalf("failed create generator, err= %v", err)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expect panic for time out of range, epoch= %v", epoch)
+				}
+			}()
+			gen.New(0)
+		}()
+	}
+}
@@ -0,0 +1,39 @@
+package xxid
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestIntern(t *testing.T) {
+	ids := []ID{New(), New(), NewGenerator().UseMachineID([]byte("0123456789abcdef")).New()}
+	for _, id := range ids {
+		v, err := Intern(id)
+		if err != nil {
+			t.Fatalf("failed intern ID, err= %v", err)
+		}
+		if got := v.ID(); got != id {
+			t.Fatalf("interned ID not match")
+		}
+	}
+	if (InternedID{}).ID() != zeroID {
+		t.Fatalf("zero value should expand to zero ID")
+	}
+	if unsafe.Sizeof(InternedID{}) >= unsafe.Sizeof(ID{}) {
+		t.Fatalf("interned ID should be smaller than ID")
+	}
+}
+
+func TestIntern_Compact(t *testing.T) {
+	id := NewGenerator().UseCompactLayout().New()
+	v, err := Intern(id)
+	if err != nil || v.ID() != id {
+		t.Fatalf("round trip failed, got= %v, err= %v", v.ID().Inspect(), err)
+	}
+	full := id
+	full.compact = false
+	w, err := Intern(full)
+	if err != nil || w.ID() != full || w == v {
+		t.Fatalf("IDs in different layouts share the interned value")
+	}
+}
@@ -0,0 +1,103 @@
+// Package basex implements the base-N encoding engine shared by the
+// base62 and base36 encodings of xxid and package base62.
+package basex
+
+// Encode encodes src to dst with the given characters, the base is the
+// number of characters. The length of src must be a multiple of 4, and
+// the length of dst is exactly the expected length of the encoded form,
+// unused leading bytes of dst are set to characters[0].
+func Encode(dst, src []byte, characters string) {
+	const uint32base = 1 << 32
+	dstBase := uint64(len(characters))
+
+	// Split src into 4 4-byte words, this is where most of the efficiency comes
+	// from because this is a O(N^2) algorithm, and we make N = N / 4 by working
+	// on 32 bits at a time.
+	// IDs are no longer than 8 words, which don't need allocation.
+	var buf [8]uint32
+	parts := buf[:0]
+	if len(src)/4 > len(buf) {
+		parts = make([]uint32, 0, len(src)/4)
+	}
+	for i := 0; i < len(src); i += 4 {
+		x := uint32(src[i])<<24 | uint32(src[i+1])<<16 + uint32(src[i+2])<<8 | uint32(src[i+3])
+		parts = append(parts, x)
+	}
+
+	n := len(dst)
+	bp := parts
+	bq := [8]uint32{}
+
+	for len(bp) != 0 {
+		var value, remainder uint64
+		quotient := bq[:0]
+		for _, c := range bp {
+			value = uint64(c) + remainder*uint32base
+			digit := value / dstBase
+			remainder = value % dstBase
+			if len(quotient) != 0 || digit != 0 {
+				quotient = append(quotient, uint32(digit))
+			}
+		}
+
+		// Writes at the end of the destination buffer because we computed
+		// the lowest bits first.
+		n--
+		dst[n] = characters[remainder]
+		bp = quotient
+	}
+
+	// Add padding at the head of the destination buffer for all bytes that
+	// were not set.
+	for i := 0; i < n; i++ {
+		dst[i] = characters[0]
+	}
+}
+
+// Decode decodes src encoded in base srcBase to dst, dec maps characters
+// to their values, 0xff for invalid characters. The length of dst is
+// exactly the length of the decoded form and must be a multiple of 4.
+// It returns errInvalidChar for invalid characters, and errOverflow if
+// the value does not fit in dst.
+func Decode(dst []byte, src []byte, srcBase uint64, dec *[128]byte, errInvalidChar func(byte) error, errOverflow error) error {
+	const uint32base = 1 << 32
+
+	parts := make([]byte, 0, len(src))
+	for _, c := range src {
+		if c >= 128 || dec[c] == 0xff {
+			return errInvalidChar(c)
+		}
+		parts = append(parts, dec[c])
+	}
+	n := len(dst)
+	bp := parts
+	bq := [64]byte{}
+
+	for len(bp) > 0 {
+		if n < 4 {
+			return errOverflow
+		}
+
+		var value, remainder uint64
+		quotient := bq[:0]
+		for _, c := range bp {
+			value = uint64(c) + remainder*srcBase
+			digit := value / uint32base
+			remainder = value % uint32base
+			if len(quotient) != 0 || digit != 0 {
+				quotient = append(quotient, byte(digit))
+			}
+		}
+
+		dst[n-4] = byte(remainder >> 24)
+		dst[n-3] = byte(remainder >> 16)
+		dst[n-2] = byte(remainder >> 8)
+		dst[n-1] = byte(remainder)
+		n -= 4
+		bp = quotient
+	}
+	for i := 0; i < n; i++ {
+		dst[i] = 0
+	}
+	return nil
+}
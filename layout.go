@@ -0,0 +1,96 @@
+package xxid
+
+import "encoding/json"
+
+// LayoutField describes a field of the binary form of IDs, offsets and
+// widths are in bits, counted from the most significant bit of the first
+// byte, all fields are big-endian unsigned integers or bytes.
+type LayoutField struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Bits   int    `json:"bits"`
+}
+
+// TypeLayout describes the binary form of IDs of a machine ID type.
+type TypeLayout struct {
+	MachineIDType MachineIDType `json:"machineIDType"`
+	Name          string        `json:"name"`
+	BinaryLen     int           `json:"binaryLen"`
+	Base62Len     int           `json:"base62Len"`
+	StringLen     int           `json:"stringLen"`
+	Fields        []LayoutField `json:"fields"`
+}
+
+// Names of fields in LayoutField.
+const (
+	FieldTimestamp     = "timestamp"     // milliseconds since the Unix epoch
+	FieldMachineIDType = "machineIDType" // see MachineIDType
+	FieldCounter       = "counter"
+	FieldMachineID     = "machineID"
+	FieldPidOrPort     = "pidOrPort"
+	FieldFlag          = "flag" // the most significant bit tells whether the flag is set
+)
+
+// Layout returns the layout of the binary form of IDs for each machine
+// ID type, ordered by machine ID type, e.g. for HostID:
+//
+//	timestamp     offset=0   bits=45
+//	machineIDType offset=45  bits=3
+//	counter       offset=48  bits=16
+//	machineID     offset=64  bits=32
+//	pidOrPort     offset=96  bits=16
+//	flag          offset=112 bits=16
+//
+// It lets code generators, ports to other languages and documentation
+// generators derive parsers from the library itself, the returned value
+// can be marshaled to JSON.
+func Layout() []TypeLayout {
+	return layouts(false)
+}
+
+// CompactLayout is like Layout, but returns the compact layout which
+// omits the flag, see Generator.UseCompactLayout.
+func CompactLayout() []TypeLayout {
+	return layouts(true)
+}
+
+func layouts(compact bool) []TypeLayout {
+	out := make([]TypeLayout, 0, maxMachineIDType+1)
+	for t := MachineIDType(0); t <= maxMachineIDType; t++ {
+		id := ID{mIDType: t, compact: compact}
+		mIDBits := machineIdLength[t] * 8
+		fields := []LayoutField{
+			{FieldTimestamp, 0, 45},
+			{FieldMachineIDType, 45, 3},
+			{FieldCounter, 48, 16},
+			{FieldMachineID, 64, mIDBits},
+			{FieldPidOrPort, 64 + mIDBits, 16},
+		}
+		if !compact {
+			fields = append(fields, LayoutField{FieldFlag, 80 + mIDBits, 16})
+		}
+		out = append(out, TypeLayout{
+			MachineIDType: t,
+			Name:          machineIDTypeNames[t],
+			BinaryLen:     id.binaryLen(),
+			Base62Len:     id.base62Len(),
+			StringLen:     id.stringLen(),
+			Fields:        fields,
+		})
+	}
+	return out
+}
+
+// MarshalJSON implements the json.Marshaler interface, the machine ID
+// type is encoded as number, which is the value stored in the binary
+// form.
+func (l TypeLayout) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		MachineIDType uint8         `json:"machineIDType"`
+		Name          string        `json:"name"`
+		BinaryLen     int           `json:"binaryLen"`
+		Base62Len     int           `json:"base62Len"`
+		StringLen     int           `json:"stringLen"`
+		Fields        []LayoutField `json:"fields"`
+	}{uint8(l.MachineIDType), l.Name, l.BinaryLen, l.Base62Len, l.StringLen, l.Fields})
+}
@@ -0,0 +1,49 @@
+package xxid
+
+import (
+	"math/big"
+	"net"
+	"testing"
+)
+
+func TestLayout(t *testing.T) {
+	layouts := Layout()
+	if len(layouts) != int(maxMachineIDType)+1 {
+		t.Fatalf("got %d layouts", len(layouts))
+	}
+	gens := map[MachineIDType]*Generator{
+		HostID:     NewGenerator().UseFlag(77),
+		Specified8: NewGenerator().UseMachineID([]byte("12345678")).UsePort(8080),
+		IPv6:       NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")),
+	}
+	for _, l := range layouts {
+		last := l.Fields[len(l.Fields)-1]
+		if last.Offset+last.Bits != l.BinaryLen*8 {
+			t.Fatalf("type %v: fields do not cover the binary form", l.MachineIDType)
+		}
+		gen := gens[l.MachineIDType]
+		if gen == nil {
+			continue
+		}
+		// decode fields from the binary form by the layout
+		id := gen.New()
+		bin := new(big.Int).SetBytes(id.Binary())
+		field := func(f LayoutField) *big.Int {
+			x := new(big.Int).Rsh(bin, uint(l.BinaryLen*8-f.Offset-f.Bits))
+			return x.And(x, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(f.Bits)), big.NewInt(1)))
+		}
+		want := map[string]*big.Int{
+			FieldTimestamp:     big.NewInt(id.timeMsec),
+			FieldMachineIDType: big.NewInt(int64(id.mIDType)),
+			FieldCounter:       big.NewInt(int64(id.counter)),
+			FieldMachineID:     new(big.Int).SetBytes(id.MachineID()),
+			FieldPidOrPort:     big.NewInt(int64(id.pidOrPort)),
+			FieldFlag:          big.NewInt(int64(id.flag)),
+		}
+		for _, f := range l.Fields {
+			if got := field(f); got.Cmp(want[f.Name]) != 0 {
+				t.Fatalf("field %s not match, type= %v, want= %v, got= %v", f.Name, l.MachineIDType, want[f.Name], got)
+			}
+		}
+	}
+}
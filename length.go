@@ -0,0 +1,178 @@
+package xxid
+
+// Lengths of the encoded forms of IDs, the actual length depends on the
+// machine ID type and the layout, see BinaryLen, EncodedLen, StringLen
+// and Generator.UseCompactLayout. The minimums are those of the compact
+// layout.
+const (
+	MinBinaryLen  = minCompactBinLen
+	MaxBinaryLen  = maxBinEncodedLen
+	MinEncodedLen = minCompactBase62Len
+	MaxEncodedLen = maxBase62EncodedLen
+	MinStringLen  = minCompactStringLen
+	MaxStringLen  = 62
+)
+
+// BinaryLen returns the length of the binary form of IDs with the
+// given machine ID type, which may be 16, 20, or 28.
+// It returns 0 if the machine ID type is unknown.
+func BinaryLen(t MachineIDType) int {
+	if t > maxMachineIDType {
+		return 0
+	}
+	return binEncodedLength[t]
+}
+
+// EncodedLen returns the length of the base62 form of IDs with the
+// given machine ID type, which may be 22, 27, or 38.
+// It returns 0 if the machine ID type is unknown.
+// For the lengths of other encoded forms, see Encoding.Len.
+func EncodedLen(t MachineIDType) int {
+	if t > maxMachineIDType {
+		return 0
+	}
+	return b62EncodedLength[t]
+}
+
+// StringLen returns the length of the string form of IDs with the
+// given machine ID type, which may be 38, 46, or 62.
+// It returns 0 if the machine ID type is unknown.
+func StringLen(t MachineIDType) int {
+	if t > maxMachineIDType {
+		return 0
+	}
+	return strEncodedLength[t]
+}
+
+// Encoding identifies an encoded form of IDs.
+type Encoding int
+
+// Encoded forms of IDs.
+const (
+	EncodingBinary   Encoding = iota // ID.Binary
+	EncodingBase62                   // ID.Base62
+	EncodingString                   // ID.String
+	EncodingUUID                     // ID.UUID
+	EncodingURN                      // ID.URN
+	EncodingBase36                   // ID.Base36
+	EncodingProquint                 // ID.Proquint
+)
+
+// Len returns the length of the encoded form of IDs with the given
+// machine ID type, it is intended for sizing columns and buffers,
+// e.g. EncodingBase36.Len(IPv6).
+//
+// It serves as EncodedLen(MachineIDType, Encoding), the name EncodedLen
+// is taken by the base62 length of a machine ID type, which is the same
+// as EncodingBase62.Len.
+// It returns 0 if the machine ID type or encoding is unknown, or if
+// IDs with the machine ID type can not be represented in the encoding.
+func (e Encoding) Len(t MachineIDType) int {
+	if t > maxMachineIDType {
+		return 0
+	}
+	switch e {
+	case EncodingBinary:
+		return binEncodedLength[t]
+	case EncodingBase62:
+		return b62EncodedLength[t]
+	case EncodingString:
+		return strEncodedLength[t]
+	case EncodingUUID:
+		if binEncodedLength[t] == 16 {
+			return uuidEncodedLen
+		}
+		return 0
+	case EncodingURN:
+		return len(urnPrefix) + b62EncodedLength[t]
+	case EncodingBase36:
+		return b36EncodedLength[t]
+	case EncodingProquint:
+		return binEncodedLength[t]/2*6 - 1
+	}
+	return 0
+}
+
+// EncodedLen returns the length of the encoded form of IDs generated by
+// the generator, see Encoding.Len. If the generator is configured by
+// UseCompactLayout, the lengths are those of the compact layout, which
+// is 0 for EncodingUUID.
+func (g *Generator) EncodedLen(e Encoding) int {
+	cfg := g.config()
+	if cfg.compact {
+		return e.compactLen(cfg.mIDType)
+	}
+	return e.Len(cfg.mIDType)
+}
+
+// compactLen returns the length of the encoded form of IDs in the
+// compact layout, it returns 0 if IDs in the compact layout can not be
+// represented in the encoding.
+func (e Encoding) compactLen(t MachineIDType) int {
+	if t > maxMachineIDType {
+		return 0
+	}
+	switch e {
+	case EncodingBinary:
+		return compactBinLength[t]
+	case EncodingBase62:
+		return compactB62Length[t]
+	case EncodingString:
+		return compactStrLength[t]
+	case EncodingURN:
+		return len(urnPrefix) + compactB62Length[t]
+	case EncodingBase36:
+		return compactB36Length[t]
+	case EncodingProquint:
+		return compactBinLength[t]/2*6 - 1
+	}
+	return 0
+}
+
+// maxTimeMsec is the max timestamp which can be represented in the
+// binary form, which holds 45 bits of milliseconds.
+const maxTimeMsec = 1<<45 - 1
+
+// Bounds of the base62 forms of IDs whose binary form is 16 bytes,
+// i.e. the machine ID type is Random, HostID, IPv4, Specified4 or
+// HashedIPv4, which is the common case. They are MinID(Random) and
+// MaxID(HashedIPv4) in base62 form, the base62 form of any ID of these
+// types is in range [MinBase62, MaxBase62].
+// For other machine ID types, use MinID and MaxID.
+const (
+	MinBase62 = "0000000000000000000000"
+	MaxBase62 = "7n42DGM5Tflk9n8mt7Fhc7"
+)
+
+// MinID returns the smallest ID with the given machine ID type, the
+// timestamp and all other fields are zero, it can be used as sentinel
+// value and lower bound of range scans.
+// It returns the zero ID if the machine ID type is unknown.
+func MinID(t MachineIDType) ID {
+	if t > maxMachineIDType {
+		return zeroID
+	}
+	return ID{mIDType: t}
+}
+
+// MaxID returns the largest ID with the given machine ID type, the
+// timestamp is the max value which can be represented, and all bits of
+// the other fields are set, it can be used as sentinel value and upper
+// bound of range scans.
+// It returns the zero ID if the machine ID type is unknown.
+func MaxID(t MachineIDType) ID {
+	if t > maxMachineIDType {
+		return zeroID
+	}
+	id := ID{
+		timeMsec:  maxTimeMsec,
+		pidOrPort: 0xffff,
+		counter:   0xffff,
+		flag:      0xffff,
+		mIDType:   t,
+	}
+	for i := 0; i < machineIdLength[t]; i++ {
+		id.machineID[i] = 0xff
+	}
+	return id
+}
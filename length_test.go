@@ -0,0 +1,107 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLengths(t *testing.T) {
+	ids := []ID{
+		New(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::1")).New(),
+	}
+	for _, id := range ids {
+		typ := id.MachineIDType()
+		if got := BinaryLen(typ); got != len(id.Binary()) {
+			t.Fatalf("BinaryLen not match, type= %v, got= %v", typ, got)
+		}
+		if got := EncodedLen(typ); got != len(id.Base62()) {
+			t.Fatalf("EncodedLen not match, type= %v, got= %v", typ, got)
+		}
+		if got := StringLen(typ); got != len(id.String()) {
+			t.Fatalf("StringLen not match, type= %v, got= %v", typ, got)
+		}
+	}
+	for typ := Random; typ <= maxMachineIDType; typ++ {
+		if BinaryLen(typ) < MinBinaryLen || BinaryLen(typ) > MaxBinaryLen ||
+			EncodedLen(typ) < MinEncodedLen || EncodedLen(typ) > MaxEncodedLen ||
+			StringLen(typ) < MinStringLen || StringLen(typ) > MaxStringLen {
+			t.Fatalf("length out of range, type= %v", typ)
+		}
+		id := MaxID(typ).Compact()
+		if len(id.Binary()) < MinBinaryLen || len(id.Base62()) < MinEncodedLen || len(id.String()) < MinStringLen {
+			t.Fatalf("compact length out of range, type= %v", typ)
+		}
+	}
+	if MinBinaryLen != 14 || MinEncodedLen != 19 || MinStringLen != 34 {
+		t.Fatalf("min lengths should be those of the compact layout")
+	}
+	if BinaryLen(maxMachineIDType+1) != 0 || EncodedLen(maxMachineIDType+1) != 0 ||
+		StringLen(maxMachineIDType+1) != 0 {
+		t.Fatalf("length of unknown type should be 0")
+	}
+}
+
+func TestEncoding_Len(t *testing.T) {
+	gens := []*Generator{
+		NewGenerator(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}),
+		NewGenerator().UseIPv6(net.ParseIP("::1")),
+		NewGenerator().UseHashedIPv4(net.ParseIP("10.9.8.7"), nil),
+	}
+	for _, gen := range gens {
+		id := gen.New()
+		encoded := map[Encoding]string{
+			EncodingBinary:   string(id.Binary()),
+			EncodingBase62:   string(id.Base62()),
+			EncodingString:   id.String(),
+			EncodingUUID:     id.UUID(),
+			EncodingURN:      id.URN(),
+			EncodingBase36:   string(id.Base36()),
+			EncodingProquint: id.Proquint(),
+		}
+		for enc, s := range encoded {
+			if got := gen.EncodedLen(enc); got != len(s) {
+				t.Fatalf("length not match, type= %v, encoding= %v, got= %v, want= %v",
+					id.MachineIDType(), enc, got, len(s))
+			}
+		}
+	}
+	for typ := Random; typ <= maxMachineIDType; typ++ {
+		if got := EncodingBase62.Len(typ); got != EncodedLen(typ) {
+			t.Fatalf("base62 length not match, want= %v, got= %v", EncodedLen(typ), got)
+		}
+	}
+	if EncodingBase62.Len(maxMachineIDType+1) != 0 || Encoding(-1).Len(Random) != 0 {
+		t.Fatalf("length of unknown type or encoding should be 0")
+	}
+}
+
+func TestMinMaxID(t *testing.T) {
+	for mtype := MachineIDType(0); mtype <= maxMachineIDType; mtype++ {
+		min, max := MinID(mtype), MaxID(mtype)
+		for _, id := range []ID{min, max} {
+			parsed, err := ParseBase62(id.Base62())
+			if err != nil || parsed != id {
+				t.Fatalf("type %v: round trip of %v failed, err= %v", mtype, id, err)
+			}
+		}
+		id := NewGenerator().New()
+		if id.MachineIDType() == mtype && !(CompareIDs(min, id) < 0 && CompareIDs(id, max) < 0) {
+			t.Fatalf("type %v: ID not in range", mtype)
+		}
+		if bin := max.Binary(); bin[len(bin)-1] != 0xff || bin[0] != 0xff {
+			t.Fatalf("type %v: unexpected max binary %x", mtype, bin)
+		}
+	}
+	if got := string(MinID(Random).Base62()); got != MinBase62 {
+		t.Fatalf("MinBase62 not match, want= %v, got= %v", got, MinBase62)
+	}
+	if got := string(MaxID(HashedIPv4).Base62()); got != MaxBase62 {
+		t.Fatalf("MaxBase62 not match, want= %v, got= %v", got, MaxBase62)
+	}
+	if MinID(100) != zeroID || MaxID(100) != zeroID {
+		t.Fatalf("expect zero ID for unknown type")
+	}
+}
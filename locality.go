@@ -0,0 +1,129 @@
+package xxid
+
+import (
+	"bytes"
+	"sort"
+)
+
+// DefaultPageCapacity is the default number of keys per B-tree leaf page
+// used by AnalyzeLocality, which is about a 16KB page of 16 bytes keys
+// with row pointers and overhead.
+const DefaultPageCapacity = 400
+
+// LocalityReport describes how well the order of keys inserted into a
+// B-tree index matches the append-only pattern.
+type LocalityReport struct {
+	// Samples is the number of keys analyzed.
+	Samples int
+
+	// Appends is the number of keys larger than all keys inserted before.
+	Appends int
+
+	// RightmostInserts is the number of keys inserted into the rightmost
+	// leaf page, which is usually hot in buffer pool.
+	RightmostInserts int
+
+	// PageSplits is the estimated number of leaf page splits, i.e. a key
+	// is inserted into a full page other than at the end of the index.
+	// Appending to a full rightmost page allocates a new page and is not
+	// counted as a split.
+	PageSplits int
+
+	// Pages is the estimated number of leaf pages after inserting all
+	// keys.
+	Pages int
+
+	// FillFactor is the average fill ratio of the leaf pages, it is close
+	// to 1 for append-only inserts and about 0.7 for random inserts.
+	FillFactor float64
+
+	// LocalityScore is RightmostInserts divided by Samples, in range
+	// [0, 1], higher is better.
+	LocalityScore float64
+}
+
+// AnalyzeLocality simulates inserting the binary forms of ids, which
+// are in insertion order, into a B-tree index with pageCapacity keys per
+// leaf page, and reports the insert locality. A pageCapacity less than 2
+// is replaced by DefaultPageCapacity.
+//
+// It helps to compare xxid layouts with other key schemes such as random
+// UUIDs before a schema change, see AnalyzeKeyLocality.
+func AnalyzeLocality(ids []ID, pageCapacity int) LocalityReport {
+	keys := make([][]byte, len(ids))
+	for i := range ids {
+		keys[i] = ids[i].Binary()
+	}
+	return AnalyzeKeyLocality(keys, pageCapacity)
+}
+
+// AnalyzeKeyLocality is like AnalyzeLocality, but accepts arbitrary keys
+// compared by bytes.Compare, e.g. random UUIDs.
+func AnalyzeKeyLocality(keys [][]byte, pageCapacity int) LocalityReport {
+	if pageCapacity < 2 {
+		pageCapacity = DefaultPageCapacity
+	}
+	report := LocalityReport{Samples: len(keys)}
+	if len(keys) == 0 {
+		return report
+	}
+
+	// pages are ordered by key, each page holds sorted keys
+	pages := [][][]byte{make([][]byte, 0, pageCapacity)}
+	var max []byte
+	for i, key := range keys {
+		if i == 0 || bytes.Compare(key, max) > 0 {
+			report.Appends++
+			max = key
+		}
+
+		// the first page whose last key >= key, or the rightmost page
+		p := sort.Search(len(pages)-1, func(j int) bool {
+			page := pages[j]
+			return bytes.Compare(page[len(page)-1], key) >= 0
+		})
+		page := pages[p]
+		rightmost := p == len(pages)-1
+		if rightmost {
+			report.RightmostInserts++
+		}
+		pos := sort.Search(len(page), func(j int) bool {
+			return bytes.Compare(page[j], key) >= 0
+		})
+
+		if len(page) < pageCapacity {
+			pages[p] = insertKey(page, pos, key)
+			continue
+		}
+		if rightmost && pos == len(page) {
+			pages = append(pages, append(make([][]byte, 0, pageCapacity), key))
+			continue
+		}
+
+		// split the full page in halves
+		report.PageSplits++
+		half := len(page) / 2
+		right := append(make([][]byte, 0, pageCapacity), page[half:]...)
+		left := page[:half:pageCapacity]
+		if pos <= half {
+			left = insertKey(left, pos, key)
+		} else {
+			right = insertKey(right, pos-half, key)
+		}
+		pages = append(pages, nil)
+		copy(pages[p+2:], pages[p+1:])
+		pages[p], pages[p+1] = left, right
+	}
+
+	report.Pages = len(pages)
+	report.FillFactor = float64(len(keys)) / float64(len(pages)*pageCapacity)
+	report.LocalityScore = float64(report.RightmostInserts) / float64(len(keys))
+	return report
+}
+
+func insertKey(page [][]byte, pos int, key []byte) [][]byte {
+	page = append(page, nil)
+	copy(page[pos+1:], page[pos:])
+	page[pos] = key
+	return page
+}
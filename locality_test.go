@@ -0,0 +1,35 @@
+package xxid
+
+import (
+	cryptorand "crypto/rand"
+	"testing"
+)
+
+func TestAnalyzeLocality(t *testing.T) {
+	const n = 20000
+	gen := NewGenerator()
+	ids := make([]ID, n)
+	for i := range ids {
+		ids[i] = gen.New()
+	}
+	report := AnalyzeLocality(ids, 100)
+	if report.Samples != n || report.Appends != n || report.RightmostInserts != n ||
+		report.PageSplits != 0 || report.Pages != n/100 || report.FillFactor != 1 || report.LocalityScore != 1 {
+		t.Fatalf("unexpected report for sequential IDs: %+v", report)
+	}
+
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = make([]byte, 16)
+		cryptorand.Read(keys[i])
+	}
+	report = AnalyzeKeyLocality(keys, 100)
+	if report.PageSplits < n/100 || report.LocalityScore > 0.1 ||
+		report.FillFactor < 0.6 || report.FillFactor > 0.8 {
+		t.Fatalf("unexpected report for random keys: %+v", report)
+	}
+
+	if report = AnalyzeLocality(nil, 0); report.Samples != 0 || report.Pages != 0 {
+		t.Fatalf("unexpected report for empty input: %+v", report)
+	}
+}
@@ -2,8 +2,12 @@ package machineid
 
 import "fmt"
 
-// ID returns the platform specific machine id of the current host OS.
+// ID returns the platform specific machine id of the current host OS,
+// or the value set by SetOverride if it is not empty.
 func ID() (string, error) {
+	if id := getOverride(); id != "" {
+		return id, nil
+	}
 	id, err := readPlatformMachineID()
 	if err != nil {
 		return "", fmt.Errorf("machineid: %v", err)
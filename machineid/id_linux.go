@@ -2,7 +2,10 @@
 
 package machineid
 
-import "io/ioutil"
+import (
+	"io/ioutil"
+	"strings"
+)
 
 const (
 	// dbusPath is the default path for dbus machine id.
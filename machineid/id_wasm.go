@@ -0,0 +1,21 @@
+// +build js wasip1
+
+package machineid
+
+import (
+	"errors"
+	"os"
+)
+
+// HostEnv is the environment variable from which the machine id is read
+// on WebAssembly targets, the embedding host (e.g. a browser page or an
+// edge worker runtime) may supply a stable identifier through it.
+// When it is not set, callers fall back to a random machine id.
+const HostEnv = "XXID_MACHINE_ID"
+
+func readPlatformMachineID() (string, error) {
+	if id := os.Getenv(HostEnv); id != "" {
+		return id, nil
+	}
+	return "", errors.New(HostEnv + " not supplied by the WebAssembly host")
+}
@@ -0,0 +1,43 @@
+package machineid
+
+import "sync"
+
+var (
+	overrideMu sync.RWMutex
+	overrideID string
+)
+
+// SetOverride sets the machine id returned by ID, regardless of the
+// platform, an empty string removes the override.
+//
+// It is an escape hatch for environments where the platform machine id
+// is not accessible or not meaningful, e.g. mobile apps and sandboxes.
+// Note that the default generator of package xxid reads the machine id
+// during package initialization, call xxid.InitDefault after changing
+// the override to take effect.
+func SetOverride(id string) {
+	overrideMu.Lock()
+	overrideID = id
+	overrideMu.Unlock()
+}
+
+// SetAndroidID sets the Settings.Secure.ANDROID_ID passed in by the host
+// app as machine id, it is intended to be called via gomobile bindings,
+// since the value is not accessible from Go on Android.
+func SetAndroidID(androidID string) {
+	SetOverride(androidID)
+}
+
+// SetIdentifierForVendor sets the UIDevice.identifierForVendor passed in
+// by the host app as machine id, it is intended to be called via gomobile
+// bindings, since the value is not accessible from Go on iOS.
+func SetIdentifierForVendor(idfv string) {
+	SetOverride(idfv)
+}
+
+func getOverride() string {
+	overrideMu.RLock()
+	id := overrideID
+	overrideMu.RUnlock()
+	return id
+}
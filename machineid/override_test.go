@@ -0,0 +1,17 @@
+package machineid
+
+import "testing"
+
+func TestSetOverride(t *testing.T) {
+	defer SetOverride("")
+
+	SetAndroidID("9774d56d682e549c")
+	if id, err := ID(); err != nil || id != "9774d56d682e549c" {
+		t.Fatalf("override not applied, id= %q, err= %v", id, err)
+	}
+
+	SetOverride("")
+	if id, _ := ID(); id == "9774d56d682e549c" {
+		t.Fatalf("override not removed")
+	}
+}
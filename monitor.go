@@ -0,0 +1,124 @@
+package xxid
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// IngestMonitor observes IDs received from peers and detects machines
+// whose clocks are skewed, by comparing the timestamps embedded in IDs
+// with the local time when they are observed.
+//
+// Note that an ID is usually observed some time after it is generated,
+// the threshold should be larger than the expected delivery latency,
+// else machines would be reported as behind.
+//
+// An IngestMonitor is safe for concurrent use.
+type IngestMonitor struct {
+	threshold  time.Duration
+	minSamples int
+	now        func() time.Time
+
+	mu       sync.Mutex
+	machines map[machineKey]*MachineSkew
+}
+
+type machineKey struct {
+	mIDType   MachineIDType
+	machineID [16]byte
+}
+
+// MachineSkew is the clock skew statistics of a machine observed by an
+// IngestMonitor.
+type MachineSkew struct {
+	MachineIDType MachineIDType
+	MachineID     []byte
+
+	// Samples is the number of observed IDs.
+	Samples int
+
+	// Ahead and Behind are the number of observed IDs whose timestamps
+	// are ahead of or behind the local time beyond the threshold.
+	Ahead  int
+	Behind int
+
+	// MeanSkew is the mean difference of the timestamps to the local
+	// time, a positive value means the machine's clock is ahead.
+	MeanSkew time.Duration
+
+	sum time.Duration
+}
+
+// NewIngestMonitor returns an IngestMonitor which reports a machine as
+// skewed when at least minSamples IDs from it are observed, and the
+// timestamps of more than half of them are ahead of, or behind the local
+// time beyond threshold.
+func NewIngestMonitor(threshold time.Duration, minSamples int) *IngestMonitor {
+	return &IngestMonitor{
+		threshold:  threshold,
+		minSamples: minSamples,
+		now:        time.Now,
+		machines:   make(map[machineKey]*MachineSkew),
+	}
+}
+
+// Observe records an ID received from a peer.
+func (m *IngestMonitor) Observe(id ID) {
+	skew := id.Time().Sub(m.now())
+	key := machineKey{id.mIDType, id.machineID}
+
+	m.mu.Lock()
+	stats := m.machines[key]
+	if stats == nil {
+		stats = &MachineSkew{
+			MachineIDType: id.mIDType,
+			MachineID:     id.MachineID(),
+		}
+		m.machines[key] = stats
+	}
+	stats.Samples++
+	stats.sum += skew
+	stats.MeanSkew = stats.sum / time.Duration(stats.Samples)
+	if skew > m.threshold {
+		stats.Ahead++
+	} else if skew < -m.threshold {
+		stats.Behind++
+	}
+	m.mu.Unlock()
+}
+
+// Skewed returns the statistics of machines which are considered to
+// have skewed clocks, sorted by the absolute mean skew in descending
+// order.
+func (m *IngestMonitor) Skewed() []MachineSkew {
+	var out []MachineSkew
+	m.mu.Lock()
+	for _, stats := range m.machines {
+		if stats.Samples < m.minSamples {
+			continue
+		}
+		if stats.Ahead*2 > stats.Samples || stats.Behind*2 > stats.Samples {
+			out = append(out, *stats)
+		}
+	}
+	m.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		return absDuration(out[i].MeanSkew) > absDuration(out[j].MeanSkew)
+	})
+	return out
+}
+
+// Reset drops all the statistics.
+func (m *IngestMonitor) Reset() {
+	m.mu.Lock()
+	m.machines = make(map[machineKey]*MachineSkew)
+	m.mu.Unlock()
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
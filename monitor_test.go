@@ -0,0 +1,39 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestMonitor(t *testing.T) {
+	now := time.Now().Truncate(time.Millisecond)
+	mon := NewIngestMonitor(time.Second, 3)
+	mon.now = func() time.Time { return now }
+
+	ahead := NewGenerator().UseMachineID([]byte("aaaa"))
+	behind := NewGenerator().UseMachineID([]byte("bbbb"))
+	healthy := NewGenerator().UseMachineID([]byte("cccc"))
+	for i := 0; i < 5; i++ {
+		mon.Observe(ahead.NewWithTime(now.Add(10 * time.Second)))
+		mon.Observe(behind.NewWithTime(now.Add(-3 * time.Second)))
+		mon.Observe(healthy.NewWithTime(now.Add(100 * time.Millisecond)))
+	}
+	// an occasional outlier does not flag a healthy machine
+	mon.Observe(healthy.NewWithTime(now.Add(time.Minute)))
+
+	skewed := mon.Skewed()
+	if len(skewed) != 2 {
+		t.Fatalf("expect 2 skewed machines, got %d", len(skewed))
+	}
+	if string(skewed[0].MachineID) != "aaaa" || skewed[0].Ahead != 5 || skewed[0].MeanSkew != 10*time.Second {
+		t.Fatalf("ahead machine not match, got= %+v", skewed[0])
+	}
+	if string(skewed[1].MachineID) != "bbbb" || skewed[1].Behind != 5 {
+		t.Fatalf("behind machine not match, got= %+v", skewed[1])
+	}
+
+	mon.Reset()
+	if len(mon.Skewed()) != 0 {
+		t.Fatalf("expect no skewed machines after reset")
+	}
+}
@@ -0,0 +1,5 @@
+// +build !race
+
+package xxid
+
+const raceEnabled = false
@@ -0,0 +1,43 @@
+package xxid
+
+import "strings"
+
+// ObjectKey returns a key of an object store like S3 for the ID, in the
+// form of "prefix/ab/cd/<base62>", where the shard components "ab/cd"
+// are the top shardBits bits of ID.ShardKey in hex, split into path
+// components of two hex characters.
+//
+// Since the shard is derived from a hash of the ID instead of the
+// timestamp, objects written at the same time are spread across key
+// ranges, avoiding the hot partition problem of purely time-ordered
+// keys. A shardBits of 8 makes 256 shards, 16 makes 65536 shards.
+// A shardBits of 0 returns "prefix/<base62>", an empty prefix is
+// omitted. It panics if shardBits is not in range [0, 32].
+func ObjectKey(prefix string, id ID, shardBits int) string {
+	if shardBits < 0 || shardBits > 32 {
+		panic("xxid: shardBits must be in range [0, 32]")
+	}
+	const hexDigits = "0123456789abcdef"
+
+	prefix = strings.TrimRight(prefix, "/")
+	b62Len := id.base62Len()
+	out := make([]byte, 0, len(prefix)+1+12+b62Len)
+	if prefix != "" {
+		out = append(out, prefix...)
+		out = append(out, '/')
+	}
+	if shardBits > 0 {
+		digits := (shardBits + 3) / 4
+		shard := uint64(id.ShardKey()) >> uint(32-shardBits)
+		for i := 0; i < digits; i++ {
+			if i > 0 && i%2 == 0 {
+				out = append(out, '/')
+			}
+			out = append(out, hexDigits[shard>>uint(4*(digits-1-i))&0x0f])
+		}
+		out = append(out, '/')
+	}
+	out = out[:len(out)+b62Len]
+	id.putBase62(out[len(out)-b62Len:])
+	return b2s(out)
+}
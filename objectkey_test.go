@@ -0,0 +1,46 @@
+package xxid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestObjectKey(t *testing.T) {
+	id := New()
+	b62 := string(id.Base62())
+	hash := fmt.Sprintf("%08x", id.ShardKey())
+
+	for _, x := range []struct {
+		prefix string
+		bits   int
+		want   string
+	}{
+		{"logs", 0, "logs/" + b62},
+		{"", 0, b62},
+		{"logs/", 8, "logs/" + hash[:2] + "/" + b62},
+		{"a/b", 16, "a/b/" + hash[:2] + "/" + hash[2:4] + "/" + b62},
+		{"x", 32, "x/" + hash[:2] + "/" + hash[2:4] + "/" + hash[4:6] + "/" + hash[6:8] + "/" + b62},
+	} {
+		if got := ObjectKey(x.prefix, id, x.bits); got != x.want {
+			t.Fatalf("object key not match, want= %v, got= %v", x.want, got)
+		}
+	}
+
+	// 12 bits makes 3 hex digits, the last one in [0, f]
+	got := ObjectKey("p", id, 12)
+	parts := strings.Split(got, "/")
+	if len(parts) != 4 || len(parts[1]) != 2 || len(parts[2]) != 1 {
+		t.Fatalf("object key with 12 bits not match, got= %v", got)
+	}
+
+	// consecutive IDs are spread across shards
+	gen := NewGenerator()
+	shards := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		shards[strings.SplitN(ObjectKey("", gen.New(), 8), "/", 2)[0]] = true
+	}
+	if len(shards) < 50 {
+		t.Fatalf("consecutive IDs are not spread, got= %v shards", len(shards))
+	}
+}
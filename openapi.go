@@ -0,0 +1,141 @@
+package xxid
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errUnsupportedEncoding = errors.New("xxid: encoding is unsupported for the machine ID type")
+
+// Schema is an OpenAPI 3 or JSON Schema fragment which describes IDs in
+// an encoded form, it can be marshaled to JSON and embedded into
+// API documents, thus the documents stay in sync with the encoders.
+type Schema struct {
+	Schema      string   `json:"$schema,omitempty"`
+	Type        string   `json:"type"`
+	Format      string   `json:"format,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	MinLength   int      `json:"minLength,omitempty"`
+	MaxLength   int      `json:"maxLength,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Example     string   `json:"example,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+}
+
+// OpenAPISchema returns the OpenAPI 3 schema of IDs with machine ID type
+// t in encoding e, with an example value, e.g.
+//
+//	{"type":"string","format":"xxid-base62",
+//	 "pattern":"^([0-7][0-9A-Za-z]{21}|[0-9A-S][0-9A-Za-z]{18})$",
+//	 "minLength":19,"maxLength":22,"description":"...","example":"..."}
+//
+// IDs in both the full and the compact layout are accepted, thus
+// minLength is the length of the compact form, see
+// Generator.UseCompactLayout, except for UUID, which has no compact form.
+// The binary form is described as a base64 string of format "byte",
+// which is how []byte is marshaled to JSON.
+// It returns an error if t or e is unknown, or if IDs with the machine
+// ID type can not be represented in the encoding.
+func OpenAPISchema(e Encoding, t MachineIDType) (Schema, error) {
+	s, err := newSchema(e, t)
+	if err != nil {
+		return Schema{}, err
+	}
+	s.Example, _ = Example(e, t)
+	return s, nil
+}
+
+// JSONSchema is like OpenAPISchema, but returns a JSON Schema (draft
+// 2020-12) with the example value in the "examples" keyword.
+func JSONSchema(e Encoding, t MachineIDType) (Schema, error) {
+	s, err := newSchema(e, t)
+	if err != nil {
+		return Schema{}, err
+	}
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	example, _ := Example(e, t)
+	s.Examples = []string{example}
+	return s, nil
+}
+
+// Example returns a stable example value of IDs with machine ID type t
+// in encoding e, it can be used to register examples in API documents,
+// e.g. swagger annotations. The binary form is returned in base64.
+func Example(e Encoding, t MachineIDType) (string, error) {
+	if e.Len(t) == 0 {
+		return "", errUnsupportedEncoding
+	}
+	id := ID{
+		timeMsec:  1637371300634, // 2021-11-20T01:21:40.634Z
+		pidOrPort: 171,
+		counter:   59141,
+		mIDType:   t,
+		machineID: [16]byte{0x21, 0x8b, 0x67, 0xc8, 0x3a, 0x50, 0x9e, 0x14,
+			0xc2, 0x77, 0x05, 0xd9, 0x6e, 0xb1, 0x40, 0x0f},
+	}
+	switch e {
+	case EncodingBinary:
+		return base64.StdEncoding.EncodeToString(id.Binary()), nil
+	case EncodingBase62:
+		return string(id.Base62()), nil
+	case EncodingString:
+		return id.formatString(time.UTC), nil
+	case EncodingUUID:
+		return id.UUID(), nil
+	case EncodingURN:
+		return id.URN(), nil
+	case EncodingBase36:
+		return string(id.Base36()), nil
+	case EncodingProquint:
+		return id.Proquint(), nil
+	}
+	return "", errUnsupportedEncoding
+}
+
+func newSchema(e Encoding, t MachineIDType) (Schema, error) {
+	n := e.Len(t)
+	if n == 0 {
+		return Schema{}, errUnsupportedEncoding
+	}
+	s := Schema{Type: "string", MinLength: n, MaxLength: n}
+	if m := e.compactLen(t); m > 0 {
+		s.MinLength = m
+	}
+	switch e {
+	case EncodingBinary:
+		s.Format = "byte"
+		s.MinLength = base64.StdEncoding.EncodedLen(s.MinLength)
+		s.MaxLength = base64.StdEncoding.EncodedLen(n)
+		s.Description = "xxid in binary form, encoded in base64"
+	case EncodingBase62:
+		s.Format = "xxid-base62"
+		s.Pattern = Base62Pattern(t)
+		s.Description = "xxid in base62 form, sortable by generation time"
+	case EncodingString:
+		s.Format = "xxid-string"
+		s.Pattern = StringPattern(t)
+		s.Description = "xxid in string form, starting with the local generation time"
+	case EncodingUUID:
+		s.Format = "uuid"
+		s.Pattern = "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"
+		s.Description = "xxid in UUID text form"
+	case EncodingURN:
+		s.Format = "xxid-urn"
+		s.Pattern = "^urn:xxid:" + strings.TrimPrefix(Base62Pattern(t), "^")
+		s.Description = "xxid in URN form"
+	case EncodingBase36:
+		s.Format = "xxid-base36"
+		s.Pattern = "^([0-9A-Z]{" + strconv.Itoa(n) + "}|[0-9A-Z]{" + strconv.Itoa(s.MinLength) + "})$"
+		s.Description = "xxid in base36 form, sortable by generation time"
+	case EncodingProquint:
+		const group = "[bdfghjklmnprstvz][aiou][bdfghjklmnprstvz][aiou][bdfghjklmnprstvz]"
+		s.Format = "xxid-proquint"
+		s.Pattern = "^" + group + "((-" + group + "){" + strconv.Itoa((n+1)/6-1) +
+			"}|(-" + group + "){" + strconv.Itoa((s.MinLength+1)/6-1) + "})$"
+		s.Description = "xxid in proquint form"
+	}
+	return s, nil
+}
@@ -0,0 +1,103 @@
+package xxid
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAPISchema(t *testing.T) {
+	encodings := []Encoding{EncodingBinary, EncodingBase62, EncodingString,
+		EncodingUUID, EncodingURN, EncodingBase36, EncodingProquint}
+	for mtype := MachineIDType(0); mtype <= maxMachineIDType; mtype++ {
+		for _, e := range encodings {
+			s, err := OpenAPISchema(e, mtype)
+			if e.Len(mtype) == 0 {
+				if err == nil {
+					t.Fatalf("encoding %v type %v: expect error", e, mtype)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("encoding %v type %v: %v", e, mtype, err)
+			}
+			if len(s.Example) != s.MaxLength || s.MinLength != s.MaxLength && !hasCompactForm(e) {
+				t.Fatalf("encoding %v type %v: example %q does not match length", e, mtype, s.Example)
+			}
+			if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(s.Example) {
+				t.Fatalf("encoding %v type %v: example %q does not match %s", e, mtype, s.Example, s.Pattern)
+			}
+
+			var id ID
+			switch e {
+			case EncodingBinary:
+				bin, _ := base64.StdEncoding.DecodeString(s.Example)
+				id, err = ParseBinary(bin)
+			case EncodingBase36:
+				id, err = ParseBase36([]byte(s.Example))
+			case EncodingURN:
+				id, err = ParseURN(s.Example)
+			case EncodingProquint:
+				id, err = ParseProquint(s.Example)
+			case EncodingString:
+				id, err = parseString(s.Example, time.UTC)
+			default:
+				id, err = Parse(s.Example)
+			}
+			if err != nil || id.MachineIDType() != mtype {
+				t.Fatalf("encoding %v type %v: example %q does not parse, err= %v", e, mtype, s.Example, err)
+			}
+		}
+	}
+}
+
+func hasCompactForm(e Encoding) bool {
+	return e.compactLen(Random) > 0
+}
+
+func TestOpenAPISchema_Compact(t *testing.T) {
+	gen := NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseCompactLayout()
+	id := gen.New()
+	for _, tc := range []struct {
+		e     Encoding
+		value string
+	}{
+		{EncodingBinary, base64.StdEncoding.EncodeToString(id.Binary())},
+		{EncodingBase62, string(id.Base62())},
+		{EncodingString, id.String()},
+		{EncodingURN, id.URN()},
+		{EncodingBase36, string(id.Base36())},
+		{EncodingProquint, id.Proquint()},
+	} {
+		s, err := OpenAPISchema(tc.e, IPv6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tc.value) != s.MinLength || len(tc.value) != gen.EncodedLen(tc.e) && tc.e != EncodingBinary {
+			t.Fatalf("encoding %v: compact value %q does not match minLength %d", tc.e, tc.value, s.MinLength)
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(tc.value) {
+			t.Fatalf("encoding %v: compact value %q does not match %s", tc.e, tc.value, s.Pattern)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	s, err := JSONSchema(EncodingBase62, HostID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := json.Marshal(s)
+	for _, want := range []string{`"$schema":"https://json-schema.org/`, `"type":"string"`, `"examples":["`} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("JSON schema %s does not contain %s", out, want)
+		}
+	}
+	if strings.Contains(string(out), `"example":`) {
+		t.Fatalf("JSON schema should not have example keyword: %s", out)
+	}
+}
@@ -0,0 +1,38 @@
+package xxid
+
+// NewPair generates a pair of correlated IDs by the default generator,
+// see Generator.NewPair.
+func NewPair() (root, child ID) {
+	return defaultGenerator.NewPair()
+}
+
+// NewPair generates a pair of correlated but distinct IDs, e.g. a trace
+// ID and the ID of its root span, with a single access to the counter.
+//
+// The child shares the machine ID, pid or port and flag with the root,
+// and takes the time and counter right after the root, thus the pair
+// sorts adjacently. Use SameTrace to check whether two IDs are such a
+// pair.
+func (g *Generator) NewPair() (root, child ID) {
+	short := g.seq.reserve(2)
+	root = newID(g.config(), short>>16, uint16(short))
+	child = root
+	child.timeMsec, child.counter = (short+1)>>16, uint16(short+1)
+	return root, child
+}
+
+// SameTrace reports whether a and b are a pair generated by NewPair,
+// in either order.
+//
+// Note that unless the generator is configured by UseFlag, the check
+// relies on the random flag shared by the pair, adjacent IDs which are
+// not generated as a pair may be reported as the same trace with
+// probability 1/32768.
+func SameTrace(a, b ID) bool {
+	if a.mIDType != b.mIDType || a.machineID != b.machineID ||
+		a.pidOrPort != b.pidOrPort || a.flag != b.flag {
+		return false
+	}
+	delta := a.Short() - b.Short()
+	return delta == 1 || delta == -1
+}
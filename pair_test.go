@@ -0,0 +1,25 @@
+package xxid
+
+import "testing"
+
+func TestNewPair(t *testing.T) {
+	root, child := NewPair()
+	if root == child {
+		t.Fatalf("root and child should be distinct")
+	}
+	if CompareIDs(root, child) >= 0 {
+		t.Fatalf("child should sort after root")
+	}
+	if !SameTrace(root, child) || !SameTrace(child, root) {
+		t.Fatalf("pair should be the same trace")
+	}
+
+	other := New().SetFlag(1)
+	if SameTrace(root, other) || SameTrace(child, other) {
+		t.Fatalf("unrelated IDs should not be the same trace")
+	}
+	root2, _ := NewPair()
+	if SameTrace(root, root2) {
+		t.Fatalf("roots of different pairs should not be the same trace")
+	}
+}
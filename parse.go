@@ -0,0 +1,77 @@
+package xxid
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Parse parses an ID from its base62 form, string form or hyphenated
+// UUID text returned by ID.UUID.
+func Parse(s string) (ID, error) {
+	return scanText(s)
+}
+
+// ParseOptions configures a tolerant parser for noisy input, e.g. IDs
+// scraped from log lines or received from webhooks.
+//
+// The options are applied in the following order: TrimSpace, URLDecode,
+// TrimQuotes, TrimSpace (again), Prefixes.
+type ParseOptions struct {
+	// TrimSpace removes leading and trailing white space.
+	TrimSpace bool
+
+	// URLDecode decodes URL-encoded input, e.g. "%22...%22".
+	URLDecode bool
+
+	// TrimQuotes removes a pair of surrounding quotes, which may be
+	// double quotes, single quotes or backquotes.
+	TrimQuotes bool
+
+	// Prefixes are stripped from the input, the first matched prefix
+	// is stripped, e.g. "req_" or "trace-id=".
+	Prefixes []string
+
+	// ValidateMachineID rejects IDs whose machine ID contradicts its
+	// type, see ID.ValidateMachineID.
+	ValidateMachineID bool
+}
+
+// Parse parses an ID from its base62 form, string form or hyphenated
+// UUID text after the input being cleaned up according to the options.
+func (opts ParseOptions) Parse(s string) (ID, error) {
+	if opts.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if opts.URLDecode && strings.ContainsAny(s, "%+") {
+		tmp, err := url.QueryUnescape(s)
+		if err != nil {
+			return zeroID, errInvalidURLEncoding
+		}
+		s = tmp
+	}
+	if opts.TrimQuotes && len(s) >= 2 {
+		switch q := s[0]; q {
+		case '"', '\'', '`':
+			if s[len(s)-1] == q {
+				s = s[1 : len(s)-1]
+			}
+		}
+	}
+	if opts.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	for _, prefix := range opts.Prefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = s[len(prefix):]
+			break
+		}
+	}
+	id, err := Parse(s)
+	if err == nil && opts.ValidateMachineID {
+		err = id.ValidateMachineID()
+	}
+	if err != nil {
+		return zeroID, err
+	}
+	return id, nil
+}
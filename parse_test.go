@@ -0,0 +1,94 @@
+package xxid
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	id := New()
+	for _, s := range []string{string(id.Base62()), id.String(), id.UUID(), strings.ToUpper(id.UUID())} {
+		got, err := Parse(s)
+		if err != nil || got != id {
+			t.Fatalf("failed parse %v, err= %v", s, err)
+		}
+	}
+	if _, err := Parse("invalid"); err == nil {
+		t.Fatalf("expect error for invalid input")
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	id := New()
+	b62 := string(id.Base62())
+	opts := ParseOptions{
+		TrimSpace:  true,
+		URLDecode:  true,
+		TrimQuotes: true,
+		Prefixes:   []string{"req_", "trace-id="},
+	}
+	for _, s := range []string{
+		b62,
+		"  " + b62 + "\n",
+		`"` + b62 + `"`,
+		` '` + b62 + `' `,
+		"%22" + b62 + "%22",
+		"req_" + b62,
+		`"trace-id=` + b62 + `"`,
+		"trace-id%3D" + b62,
+		"\t" + id.String(),
+	} {
+		got, err := opts.Parse(s)
+		if err != nil || got != id {
+			t.Fatalf("failed parse %q, err= %v", s, err)
+		}
+	}
+
+	for _, s := range []string{
+		`"` + b62,
+		"%zz" + b62,
+		"other_" + b62,
+	} {
+		if _, err := opts.Parse(s); err == nil {
+			t.Fatalf("expect error for %q", s)
+		}
+	}
+	if _, err := (ParseOptions{}).Parse(" " + b62); err == nil {
+		t.Fatalf("expect error without TrimSpace")
+	}
+}
+
+func TestID_ValidateMachineID(t *testing.T) {
+	valid := []ID{
+		New(),
+		NewGenerator().UseIPv4(net.ParseIP("10.9.8.7")).New(),
+		NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")).New(),
+	}
+	for _, id := range valid {
+		if err := id.ValidateMachineID(); err != nil {
+			t.Fatalf("expect valid machine ID %v, err= %v", id.Inspect(), err)
+		}
+	}
+
+	invalid := []ID{
+		NewGenerator().UseIPv4(net.ParseIP("0.0.0.0")).New(),
+		NewGenerator().UseIPv4(net.ParseIP("224.0.0.1")).New(),
+		NewGenerator().UseIPv4(net.ParseIP("255.255.255.255")).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::")).New(),
+		NewGenerator().UseIPv6(net.ParseIP("ff02::1")).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::ffff:10.9.8.7")).New(),
+	}
+	opts := ParseOptions{ValidateMachineID: true}
+	for _, id := range invalid {
+		if err := id.ValidateMachineID(); err == nil {
+			t.Fatalf("expect invalid machine ID %v", id.Inspect())
+		}
+		if _, err := opts.Parse(string(id.Base62())); err == nil {
+			t.Fatalf("expect parse error for %v", id.Inspect())
+		}
+		if _, err := Parse(string(id.Base62())); err != nil {
+			t.Fatalf("validation should be opt-in, err= %v", err)
+		}
+	}
+}
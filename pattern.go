@@ -0,0 +1,107 @@
+package xxid
+
+import "strconv"
+
+// base62MaxFirstChars are the max first characters of base62 forms,
+// keyed by the length of the base62 form, larger values overflow the
+// binary form.
+var base62MaxFirstChars = map[int]byte{
+	22: '7',
+	27: 'a',
+	38: 'C',
+
+	// the compact layout
+	19: 'S',
+	25: '2',
+	35: 'l',
+}
+
+// base62FirstCharClass returns the character class of the first
+// character of base62 forms of length n.
+func base62FirstCharClass(n int) string {
+	max := string(base62MaxFirstChars[n])
+	switch {
+	case max <= "9":
+		return "[0-" + max + "]"
+	case max <= "Z":
+		return "[0-9A-" + max + "]"
+	case max == "a":
+		return "[0-9A-Za]"
+	}
+	return "[0-9A-Za-" + max + "]"
+}
+
+// Base62Pattern returns an anchored regular expression which matches the
+// base62 form of IDs with machine ID type t, in both the full and the
+// compact layout, e.g. "^([0-7][0-9A-Za-z]{21}|[0-9A-S][0-9A-Za-z]{18})$".
+// It returns an empty string if t is unknown.
+//
+// The pattern is compatible with RE2, ECMAScript and PCRE, thus can be
+// used in OpenAPI schemas, JSON Schema and WAF rules.
+// Note that the pattern checks only the length and characters.
+func Base62Pattern(t MachineIDType) string {
+	if t > maxMachineIDType {
+		return ""
+	}
+	full, compact := b62EncodedLength[t], compactB62Length[t]
+	return "^(" + base62FirstCharClass(full) + "[0-9A-Za-z]{" + strconv.Itoa(full-1) + "}|" +
+		base62FirstCharClass(compact) + "[0-9A-Za-z]{" + strconv.Itoa(compact-1) + "})$"
+}
+
+// StringPattern returns an anchored regular expression which matches the
+// string form of IDs with machine ID type t, in both the full and the
+// compact layout, which omits the 4 characters of flag. It returns an
+// empty string if t is unknown. See Base62Pattern for details.
+func StringPattern(t MachineIDType) string {
+	if t > maxMachineIDType {
+		return ""
+	}
+	hexLen := machineIdLength[t]*2 + 8
+	return "^[0-9]{17}([0-9a-f]{4})?" + strconv.Itoa(int(t)) + "[0-9a-f]{" + strconv.Itoa(hexLen) + "}$"
+}
+
+// IsBase62Form reports whether s matches Base62Pattern of any machine ID
+// type, it is a cheap check to reject garbage before parsing, it does
+// not allocate.
+func IsBase62Form(s string) bool {
+	if max, ok := base62MaxFirstChars[len(s)]; !ok || s[0] > max {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= 128 || dec[c] == 0xff {
+			return false
+		}
+	}
+	return true
+}
+
+// IsStringForm reports whether s matches StringPattern of any machine ID
+// type, like IsBase62Form it does not allocate.
+func IsStringForm(s string) bool {
+	if len(s) < minCompactStringLen {
+		return false
+	}
+	// the machine ID type follows the flag, which is omitted in the
+	// compact layout
+	typePos := 21
+	t := MachineIDType(s[typePos] - '0')
+	if t > maxMachineIDType || len(s) != strEncodedLength[t] {
+		typePos = 17
+		t = MachineIDType(s[typePos] - '0')
+		if t > maxMachineIDType || len(s) != compactStrLength[t] {
+			return false
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case i < 17 || i == typePos:
+			if c < '0' || c > '9' {
+				return false
+			}
+		case !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f'):
+			return false
+		}
+	}
+	return true
+}
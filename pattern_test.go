@@ -0,0 +1,45 @@
+package xxid
+
+import (
+	"net"
+	"regexp"
+	"testing"
+)
+
+func TestPatterns(t *testing.T) {
+	gens := []*Generator{
+		NewGenerator(),
+		NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")),
+		NewGenerator().UseMachineID([]byte("12345678")),
+		NewGenerator().UseHashedIPv4(net.ParseIP("10.1.2.3"), []byte("key")),
+	}
+	for _, gen := range gens {
+		id := gen.New()
+		mtype := id.MachineIDType()
+		b62 := regexp.MustCompile(Base62Pattern(mtype))
+		str := regexp.MustCompile(StringPattern(mtype))
+		for _, id := range []ID{id, id.Compact(), MaxID(mtype), MaxID(mtype).Compact()} {
+			if s := string(id.Base62()); !b62.MatchString(s) || !IsBase62Form(s) || str.MatchString(s) {
+				t.Fatalf("base62 form not match, type= %v, got= %v", mtype, s)
+			}
+			if s := id.String(); !str.MatchString(s) || !IsStringForm(s) || b62.MatchString(s) {
+				t.Fatalf("string form not match, type= %v, got= %v", mtype, s)
+			}
+		}
+	}
+
+	for _, s := range []string{"", "8zzzzzzzzzzzzzzzzzzzzz", "0000000000000000000-00", "abc", "T000000000000000000"} {
+		if IsBase62Form(s) {
+			t.Fatalf("invalid base62 form should not match, got= %q", s)
+		}
+	}
+	for _, s := range []string{"", "2021112009214063400000xxxxxxxxxxxxxxxx", "20211120092140634000092180b67c800ab0e705",
+		"20211120092140634900000000000000000"} {
+		if IsStringForm(s) {
+			t.Fatalf("invalid string form should not match, got= %q", s)
+		}
+	}
+	if Base62Pattern(100) != "" || StringPattern(100) != "" {
+		t.Fatal("expect empty pattern for unknown type")
+	}
+}
@@ -0,0 +1,33 @@
+package xxid
+
+import "errors"
+
+var errIncorrectPayloadLength = errors.New("xxid: length of payload is incorrect")
+
+// payloadOffset is the offset of the payload in the binary form, i.e.
+// the length of the timestamp and machine ID type.
+const payloadOffset = 6
+
+// Payload returns the non-timestamp part of the ID's binary form as an
+// opaque byte slice, i.e. the counter, machine ID, pid or port, and flag.
+// The length is 10, 14, or 22 according to the machine ID type, or 8,
+// 12, or 20 in the compact layout, which has no flag.
+//
+// Like the payload of KSUID, it lets generic tooling treat IDs uniformly
+// without switching on the machine ID type.
+func (id ID) Payload() []byte {
+	return id.Binary()[payloadOffset:]
+}
+
+// WithPayload returns a copy of the ID with the non-timestamp part
+// replaced by payload, see Payload. The timestamp, machine ID type and
+// layout are kept, it returns an error if the length of payload does not
+// match the machine ID type and layout.
+func (id ID) WithPayload(payload []byte) (ID, error) {
+	buf := id.Binary()
+	if len(payload) != len(buf)-payloadOffset {
+		return zeroID, errIncorrectPayloadLength
+	}
+	copy(buf[payloadOffset:], payload)
+	return decodeBinary(buf)
+}
@@ -0,0 +1,59 @@
+package xxid
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestIDPayload(t *testing.T) {
+	for _, gen := range []*Generator{
+		NewGenerator().UseFlag(5),
+		NewGenerator().UseMachineID([]byte("12345678")),
+		NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")),
+	} {
+		id := gen.New()
+		payload := id.Payload()
+		if want := len(id.Binary()) - 6; len(payload) != want {
+			t.Fatalf("payload length not match, want= %v, got= %v", want, len(payload))
+		}
+		if !bytes.Equal(payload, id.Binary()[6:]) {
+			t.Fatalf("payload not match binary form")
+		}
+
+		got, err := id.WithPayload(payload)
+		if err != nil || got != id {
+			t.Fatalf("WithPayload result not match, want= %v, got= %v, err= %v", id, got, err)
+		}
+
+		other := gen.New()
+		got, err = other.WithPayload(payload)
+		if err != nil || got.Counter() != id.Counter() || !got.Time().Equal(other.Time()) {
+			t.Fatalf("WithPayload result not match, got= %v, err= %v", got.Inspect(), err)
+		}
+
+		if _, err := id.WithPayload(payload[1:]); err == nil {
+			t.Fatalf("expect error for incorrect payload length")
+		}
+	}
+}
+
+func TestIDPayload_Compact(t *testing.T) {
+	id := NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).UseCompactLayout().New()
+	payload := id.Payload()
+	if len(payload) != 12 {
+		t.Fatalf("payload length not match, want= %v, got= %v", 12, len(payload))
+	}
+	got, err := id.WithPayload(payload)
+	if err != nil || got != id {
+		t.Fatalf("round trip failed, got= %v, err= %v", got.Inspect(), err)
+	}
+	payload[2] = 0xff
+	got, err = id.WithPayload(payload)
+	if err != nil || !got.IsCompact() || !bytes.Equal(got.Payload(), payload) {
+		t.Fatalf("WithPayload lost the compact layout, got= %v, err= %v", got.Inspect(), err)
+	}
+	if _, err = id.WithPayload(make([]byte, 14)); err == nil {
+		t.Fatalf("expect error for payload of the full layout")
+	}
+}
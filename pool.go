@@ -0,0 +1,154 @@
+package xxid
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// IDPool keeps pre-generated IDs in a lock-free ring buffer, which is
+// topped up by a background goroutine when the number of IDs falls to
+// the low watermark. Get is served from the buffer without locks, it
+// smooths the tail latency in request paths.
+//
+// Note that IDs are generated ahead of time, the timestamp of an ID is
+// the time it was pre-generated, which may be earlier than the time it
+// is retrieved. IDs in the pool are retrieved in the order they were
+// generated, but IDs generated directly by Get when the pool is empty
+// may be ahead of IDs retrieved later.
+type IDPool struct {
+	// head and tail are accessed atomically, they are the first fields
+	// to be 64-bit aligned on 32-bit platforms.
+	head uint64 // next position to consume
+	tail uint64 // next position to produce, owned by the refiller
+
+	gen      *Generator
+	lowWater uint64
+	slots    []poolSlot
+	mask     uint64
+
+	refill    chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// poolSlot is a slot of the ring buffer, seq tells the state of the
+// slot, it's the position to be produced when the slot is empty and the
+// position plus one when it's filled.
+type poolSlot struct {
+	seq uint64
+	id  ID
+}
+
+// NewIDPool returns an IDPool which keeps up to size IDs generated by
+// gen, the default generator is used if gen is nil. The pool is refilled
+// when it holds no more than lowWater IDs. size is rounded up to a power
+// of 2, lowWater is clamped to [0, size).
+//
+// The pool starts a background goroutine, call Close to stop it.
+func NewIDPool(gen *Generator, size, lowWater int) *IDPool {
+	if gen == nil {
+		gen = defaultGenerator
+	}
+	n := 2
+	for n < size {
+		n <<= 1
+	}
+	if lowWater < 0 {
+		lowWater = 0
+	} else if lowWater >= n {
+		lowWater = n - 1
+	}
+	p := &IDPool{
+		gen:      gen,
+		lowWater: uint64(lowWater),
+		slots:    make([]poolSlot, n),
+		mask:     uint64(n - 1),
+		refill:   make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i := range p.slots {
+		p.slots[i].seq = uint64(i)
+	}
+	p.fill()
+	go p.run()
+	return p
+}
+
+// Get returns a pre-generated ID, if the pool is empty, e.g. drained
+// faster than refilling, it generates an ID directly.
+func (p *IDPool) Get() ID {
+	for {
+		pos := atomic.LoadUint64(&p.head)
+		slot := &p.slots[pos&p.mask]
+		seq := atomic.LoadUint64(&slot.seq)
+		switch {
+		case seq == pos+1:
+			if atomic.CompareAndSwapUint64(&p.head, pos, pos+1) {
+				id := slot.id
+				atomic.StoreUint64(&slot.seq, pos+p.mask+1)
+				if atomic.LoadUint64(&p.tail)-(pos+1) <= p.lowWater {
+					p.signal()
+				}
+				return id
+			}
+		case seq < pos+1:
+			// empty
+			p.signal()
+			return p.gen.New()
+		}
+	}
+}
+
+// Len returns the number of IDs in the pool.
+func (p *IDPool) Len() int {
+	tail := atomic.LoadUint64(&p.tail)
+	head := atomic.LoadUint64(&p.head)
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+// Close stops the background goroutine, Get still works after closing,
+// it generates IDs directly once the pool is drained.
+func (p *IDPool) Close() {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+	})
+}
+
+func (p *IDPool) signal() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func (p *IDPool) run() {
+	defer close(p.done)
+	for {
+		select {
+		case <-p.refill:
+			p.fill()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// fill generates IDs until the ring buffer is full.
+func (p *IDPool) fill() {
+	for {
+		pos := p.tail
+		slot := &p.slots[pos&p.mask]
+		if atomic.LoadUint64(&slot.seq) != pos {
+			return
+		}
+		slot.id = p.gen.New()
+		atomic.StoreUint64(&slot.seq, pos+1)
+		atomic.StoreUint64(&p.tail, pos+1)
+	}
+}
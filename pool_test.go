@@ -0,0 +1,67 @@
+package xxid
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIDPool(t *testing.T) {
+	pool := NewIDPool(nil, 100, 20)
+	defer pool.Close()
+	if pool.Len() != 128 {
+		t.Fatalf("pool not filled, Len = %d", pool.Len())
+	}
+
+	const workers, perWorker = 8, 5000
+	results := make([][]ID, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				results[w] = append(results[w], pool.Get())
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool)
+	for _, ids := range results {
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %v", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	// the pool is refilled in background
+	deadline := time.Now().Add(time.Second)
+	for pool.Len() <= 20 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if pool.Len() <= 20 {
+		t.Fatalf("pool not refilled, Len = %d", pool.Len())
+	}
+
+	pool.Close()
+	pool.Close()
+	for i := 0; i < 200; i++ {
+		pool.Get()
+	}
+	if pool.Len() != 0 {
+		t.Fatalf("closed pool should not be refilled, Len = %d", pool.Len())
+	}
+}
+
+func BenchmarkIDPool_Get(b *testing.B) {
+	pool := NewIDPool(nil, 1<<14, 1<<12)
+	defer pool.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.Get()
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package xxid
+
+import "strings"
+
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+)
+
+// Proquint encodes the ID's binary form into proquint, a pronounceable
+// representation of five-letter groups separated by '-', each group
+// encodes 16 bits, e.g. "lusab-babad-...".
+// See https://arxiv.org/html/0901.4016 for the specification.
+//
+// The returned string consists of 8, 10, or 14 groups according to the
+// machine ID type, or 7, 9, or 13 groups in the compact layout.
+func (id ID) Proquint() string {
+	buf := id.Binary()
+	out := make([]byte, 0, len(buf)/2*6-1)
+	for i := 0; i < len(buf); i += 2 {
+		if i > 0 {
+			out = append(out, '-')
+		}
+		x := uint16(buf[i])<<8 | uint16(buf[i+1])
+		out = append(out,
+			proquintConsonants[x>>12&0x0f],
+			proquintVowels[x>>10&0x03],
+			proquintConsonants[x>>6&0x0f],
+			proquintVowels[x>>4&0x03],
+			proquintConsonants[x&0x0f],
+		)
+	}
+	return b2s(out)
+}
+
+// ParseProquint parses an ID from its proquint representation returned
+// by ID.Proquint.
+func ParseProquint(str string) (ID, error) {
+	if (len(str)+1)%6 != 0 {
+		return zeroID, errInvalidProquint
+	}
+	buf := make([]byte, 0, (len(str)+1)/6*2)
+	for i := 0; i < len(str); i += 6 {
+		if i > 0 && str[i-1] != '-' {
+			return zeroID, errInvalidProquint
+		}
+		var x uint16
+		for j := 0; j < 5; j++ {
+			var v int
+			if j%2 == 0 {
+				v = strings.IndexByte(proquintConsonants, str[i+j])
+				x = x<<4 | uint16(v)
+			} else {
+				v = strings.IndexByte(proquintVowels, str[i+j])
+				x = x<<2 | uint16(v)
+			}
+			if v < 0 {
+				return zeroID, errInvalidProquint
+			}
+		}
+		buf = append(buf, byte(x>>8), byte(x))
+	}
+	return decodeBinary(buf)
+}
@@ -0,0 +1,59 @@
+package xxid
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestIDProquint(t *testing.T) {
+	ids := []ID{
+		New(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::1")).New(),
+	}
+	for _, id := range ids {
+		encoded := id.Proquint()
+		if groups := strings.Count(encoded, "-") + 1; groups != len(id.Binary())/2 {
+			t.Fatalf("proquint groups not match, got= %v", encoded)
+		}
+		got, err := ParseProquint(encoded)
+		if err != nil || got != id {
+			t.Fatalf("failed parse proquint %v, err= %v", encoded, err)
+		}
+	}
+
+	// Example from the specification: 127.0.0.1 => "lusab-babad".
+	id := NewGenerator().UseIPv4(net.ParseIP("127.0.0.1")).New()
+	if got := id.Proquint(); !strings.Contains(got, "-lusab-babad-") {
+		t.Fatalf("proquint of IPv4 machine ID not match, got= %v", got)
+	}
+
+	for _, s := range []string{
+		"",
+		"lusab",
+		"lusab_babad-lusab-babad-lusab-babad-lusab-babad",
+		"lusab-babad-lusab-babad-lusab-babad-lusab-babax",
+	} {
+		if _, err := ParseProquint(s); err == nil {
+			t.Fatalf("expect error for invalid proquint %q", s)
+		}
+	}
+}
+
+func TestIDProquint_Compact(t *testing.T) {
+	for _, gen := range []*Generator{
+		NewGenerator().UseCompactLayout(),
+		NewGenerator().UseIPv6(net.ParseIP("fd00::1")).UseCompactLayout(),
+	} {
+		id := gen.New()
+		s := id.Proquint()
+		if len(s) != gen.EncodedLen(EncodingProquint) {
+			t.Fatalf("length not match, got= %v", len(s))
+		}
+		got, err := ParseProquint(s)
+		if err != nil || got != id {
+			t.Fatalf("round trip failed, got= %v, err= %v", got.Inspect(), err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package xxid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// Pseudonymizer replaces the machine IDs inside IDs with stable
+// pseudonyms derived by HMAC-SHA256 with a secret, so datasets of IDs can
+// be shared with analysts without revealing internal network topology,
+// while IDs from the same host still share the same pseudonym and can be
+// grouped, see GroupByMachine.
+//
+// The timestamp, counter and flag are kept. IP addresses are replaced
+// by opaque values, the machine ID type IPv4 and HashedIPv4 become
+// Specified4, IPv6 becomes Specified16, and the port is replaced by a
+// pseudonym as well, other types are kept, and pids are kept.
+// The length of encoded forms does not change.
+//
+// A Pseudonymizer is safe for concurrent use.
+type Pseudonymizer struct {
+	secret []byte
+}
+
+// NewPseudonymizer returns a Pseudonymizer using secret, the same secret
+// gives the same pseudonyms, use a different secret for each dataset
+// shared to avoid the pseudonyms being linked across datasets.
+func NewPseudonymizer(secret []byte) *Pseudonymizer {
+	return &Pseudonymizer{secret: append([]byte(nil), secret...)}
+}
+
+// Apply returns id with its machine ID replaced by the pseudonym.
+func (p *Pseudonymizer) Apply(id ID) ID {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte{byte(id.mIDType)})
+	mac.Write(id.MachineID())
+	sum := mac.Sum(nil)
+
+	out := id
+	out.machineID = [16]byte{}
+	copy(out.machineID[:machineIdLength[id.mIDType]], sum)
+	switch id.mIDType {
+	case IPv4, HashedIPv4:
+		out.mIDType = Specified4
+	case IPv6:
+		out.mIDType = Specified16
+	default:
+		return out
+	}
+	mac.Reset()
+	mac.Write(sum)
+	mac.Write([]byte{byte(id.pidOrPort >> 8), byte(id.pidOrPort)})
+	port := mac.Sum(nil)
+	out.pidOrPort = uint16(port[0])<<8 | uint16(port[1])
+	return out
+}
+
+// ApplyAll returns a new slice of ids with their machine IDs replaced by
+// pseudonyms.
+func (p *Pseudonymizer) ApplyAll(ids []ID) []ID {
+	out := make([]ID, len(ids))
+	for i := range ids {
+		out[i] = p.Apply(ids[i])
+	}
+	return out
+}
+
+// Base62 re-encodes id into base62 form with its machine ID replaced by
+// the pseudonym.
+func (p *Pseudonymizer) Base62(id ID) string {
+	return string(p.Apply(id).Base62())
+}
+
+// String re-encodes id into string form with its machine ID replaced by
+// the pseudonym.
+func (p *Pseudonymizer) String(id ID) string {
+	return p.Apply(id).String()
+}
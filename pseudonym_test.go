@@ -0,0 +1,47 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPseudonymizer(t *testing.T) {
+	p := NewPseudonymizer([]byte("secret"))
+	hostA := NewGenerator().UseIPv4(net.ParseIP("10.0.0.1")).UsePort(8080)
+	hostB := NewGenerator().UseIPv6(net.ParseIP("2001:db8::1")).UsePort(8080)
+	hostC := NewGenerator().UseMachineID([]byte("12345678"))
+
+	a1, a2 := hostA.New(), hostA.New()
+	b1, c1 := hostB.New(), hostC.New()
+	pa1, pa2, pb1, pc1 := p.Apply(a1), p.Apply(a2), p.Apply(b1), p.Apply(c1)
+
+	if pa1.MachineIDType() != Specified4 || pb1.MachineIDType() != Specified16 || pc1.MachineIDType() != Specified8 {
+		t.Fatalf("unexpected machine ID types %v %v %v", pa1.MachineIDType(), pb1.MachineIDType(), pc1.MachineIDType())
+	}
+	if pa1.IP() != nil || pb1.IP() != nil {
+		t.Fatalf("pseudonyms should not be IP addresses")
+	}
+	if string(pa1.MachineID()) != string(pa2.MachineID()) || pa1.Port() != pa2.Port() {
+		t.Fatalf("pseudonyms of the same host differ")
+	}
+	if pa1.Port() == 8080 || pc1.Pid() != c1.Pid() {
+		t.Fatalf("port should be replaced and pid kept")
+	}
+	if string(pa1.MachineID()) == string(a1.MachineID()) || string(pc1.MachineID()) == string(c1.MachineID()) {
+		t.Fatalf("machine ID not replaced")
+	}
+	if !pa1.Time().Equal(a1.Time()) || pa1.Counter() != a1.Counter() || pa1.Flag() != a1.Flag() {
+		t.Fatalf("timestamp, counter or flag changed")
+	}
+	if len(p.Base62(b1)) != len(b1.Base62()) || len(p.String(a1)) != len(a1.String()) {
+		t.Fatalf("length of encoded forms changed")
+	}
+
+	other := NewPseudonymizer([]byte("other"))
+	if string(other.Apply(a1).MachineID()) == string(pa1.MachineID()) {
+		t.Fatalf("pseudonyms with different secrets should differ")
+	}
+	if all := p.ApplyAll([]ID{a1, b1}); all[0] != pa1 || all[1] != pb1 {
+		t.Fatalf("ApplyAll not match Apply")
+	}
+}
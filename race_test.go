@@ -0,0 +1,7 @@
+// +build race
+
+package xxid
+
+// raceEnabled tells whether the race detector is enabled, which makes
+// allocation counts unreliable.
+const raceEnabled = true
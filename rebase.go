@@ -0,0 +1,101 @@
+package xxid
+
+import (
+	"errors"
+	"time"
+)
+
+var errRebaseOverflow = errors.New("xxid: rebased value overflows")
+
+// Rebase converts an ID whose timestamp is counted in milliseconds since
+// fromEpoch, e.g. generated by a fork with a custom epoch, into one since
+// toEpoch, the absolute time is kept. Rebasing to the Unix epoch makes
+// ID.Time return the right time.
+//
+// Since all IDs are shifted by the same offset, the ordering is kept.
+// It returns an error if the rebased timestamp is negative or overflows
+// the 45 bits of the binary form.
+func Rebase(id ID, fromEpoch, toEpoch time.Time) (ID, error) {
+	offset := epochOffset(fromEpoch, toEpoch)
+	return rebase(id, offset)
+}
+
+// RebaseAll is the batch variant of Rebase, it returns an error if any
+// of ids can not be rebased.
+func RebaseAll(ids []ID, fromEpoch, toEpoch time.Time) ([]ID, error) {
+	offset := epochOffset(fromEpoch, toEpoch)
+	out := make([]ID, len(ids))
+	for i, id := range ids {
+		x, err := rebase(id, offset)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = x
+	}
+	return out, nil
+}
+
+func epochOffset(fromEpoch, toEpoch time.Time) int64 {
+	return fromEpoch.UnixNano()/1e6 - toEpoch.UnixNano()/1e6
+}
+
+func rebase(id ID, offset int64) (ID, error) {
+	msec := id.timeMsec + offset
+	if msec < 0 || msec > maxTimeMsec {
+		return zeroID, errRebaseOverflow
+	}
+	id.timeMsec = msec
+	return id, nil
+}
+
+// RebaseShort converts an integer ID of layout from to layout to, the
+// time, shard and counter are kept. It returns an error if either layout
+// is invalid, short is negative, or the time, shard or counter does not
+// fit in layout to. Since layouts leave the sign bit unused, the ordering
+// is kept for IDs rebased with the same layouts.
+func RebaseShort(short int64, from, to ShortLayout) (int64, error) {
+	if err := from.validate(); err != nil {
+		return 0, err
+	}
+	if err := to.validate(); err != nil {
+		return 0, err
+	}
+	return rebaseShort(short, from, to)
+}
+
+// RebaseShorts is the batch variant of RebaseShort, it returns an error
+// if any of shorts can not be rebased.
+func RebaseShorts(shorts []int64, from, to ShortLayout) ([]int64, error) {
+	if err := from.validate(); err != nil {
+		return nil, err
+	}
+	if err := to.validate(); err != nil {
+		return nil, err
+	}
+	out := make([]int64, len(shorts))
+	for i, short := range shorts {
+		x, err := rebaseShort(short, from, to)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = x
+	}
+	return out, nil
+}
+
+func rebaseShort(short int64, from, to ShortLayout) (int64, error) {
+	if short < 0 {
+		return 0, errRebaseOverflow
+	}
+	fromTime := int64(uint64(short) >> uint(from.ShardBits+from.CounterBits))
+	shard := uint64(short>>uint(from.CounterBits)) & (1<<uint(from.ShardBits) - 1)
+	counter := uint64(short) & (1<<uint(from.CounterBits) - 1)
+
+	msec := fromTime + epochOffset(from.Epoch, to.Epoch)
+	if msec < 0 || uint64(msec) >= 1<<uint(to.TimeBits) ||
+		shard >= 1<<uint(to.ShardBits) || counter >= 1<<uint(to.CounterBits) {
+		return 0, errRebaseOverflow
+	}
+	x := uint64(msec)<<uint(to.ShardBits+to.CounterBits) | shard<<uint(to.CounterBits) | counter
+	return int64(x), nil
+}
@@ -0,0 +1,81 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRebase(t *testing.T) {
+	custom := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	unix := time.Unix(0, 0)
+	now := time.Now()
+
+	// an ID generated by a fork counting from the custom epoch
+	forked := NewWithTime(time.Unix(0, 0).Add(now.Sub(custom)))
+	got, err := Rebase(forked, custom, unix)
+	if err != nil || !got.Time().Equal(now.Truncate(time.Millisecond)) {
+		t.Fatalf("Rebase = %v, %v, want time %v", got.Time(), err, now)
+	}
+	if got.Counter() != forked.Counter() || string(got.MachineID()) != string(forked.MachineID()) {
+		t.Fatalf("fields other than timestamp changed")
+	}
+	back, err := Rebase(got, unix, custom)
+	if err != nil || back != forked {
+		t.Fatalf("Rebase back = %v, %v", back, err)
+	}
+	if _, err := Rebase(forked, unix, now.Add(24*time.Hour)); err == nil {
+		t.Fatalf("expect error for negative timestamp")
+	}
+
+	gen := NewGenerator()
+	ids := []ID{gen.New(), gen.New(), gen.New()}
+	rebased, err := RebaseAll(ids, unix, custom)
+	if err != nil || !IsSorted(rebased) {
+		t.Fatalf("RebaseAll = %v, %v", rebased, err)
+	}
+	if _, err := RebaseAll(ids, unix, now.Add(time.Hour)); err == nil {
+		t.Fatalf("expect error for RebaseAll")
+	}
+}
+
+func TestRebaseShort(t *testing.T) {
+	from := ShortLayout{TimeBits: 40, ShardBits: 13, CounterBits: 10, Epoch: time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC)}
+	to := ShortLayout{TimeBits: 40, ShardBits: 13, CounterBits: 10, Epoch: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	gen, err := NewShortIDGenerator(from, func(int64) uint16 { return 42 })
+	if err != nil {
+		t.Fatal(err)
+	}
+	shorts := []int64{gen.New(0), gen.New(0), gen.New(0)}
+	rebased, err := RebaseShorts(shorts, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range shorts {
+		t1, s1, c1 := from.Parse(shorts[i])
+		t2, s2, c2 := to.Parse(rebased[i])
+		if !t1.Equal(t2) || s1 != s2 || c1 != c2 {
+			t.Fatalf("rebased value not match: %v %v %v, %v %v %v", t1, s1, c1, t2, s2, c2)
+		}
+		if i > 0 && rebased[i] <= rebased[i-1] {
+			t.Fatalf("ordering not kept")
+		}
+	}
+	if x, err := RebaseShort(rebased[0], to, from); err != nil || x != shorts[0] {
+		t.Fatalf("RebaseShort back = %v, %v", x, err)
+	}
+
+	narrow := ShortLayout{TimeBits: 40, ShardBits: 4, CounterBits: 10, Epoch: to.Epoch}
+	if _, err := RebaseShort(shorts[0], from, narrow); err == nil {
+		t.Fatalf("expect error for shard overflow")
+	}
+	if _, err := RebaseShort(shorts[0], from, ShortLayout{}); err == nil {
+		t.Fatalf("expect error for invalid layout")
+	}
+	if _, err := RebaseShort(-shorts[0], from, to); err == nil {
+		t.Fatalf("expect error for negative value")
+	}
+	short := ShortLayout{TimeBits: 30, ShardBits: 13, CounterBits: 10, Epoch: to.Epoch}
+	if _, err := RebaseShort(shorts[0], from, short); err == nil {
+		t.Fatalf("expect error for time overflow")
+	}
+}
@@ -0,0 +1,84 @@
+package xxid
+
+import (
+	"fmt"
+	"sync"
+)
+
+var regionRegistry struct {
+	mu    sync.RWMutex
+	bits  uint
+	codes map[string]uint16
+	names []string
+}
+
+// RegisterRegions registers regions (or IDCs) which are encoded into the
+// highest bits of flag, it formalizes the pattern using flag as region.
+// The regions are assigned codes from 1 in order, code 0 means that the
+// region is unknown, the remaining lower bits of the flag are left to
+// user.
+//
+// bits must be in range [1, 15], and the number of regions must not be
+// larger than 1<<bits - 1, else an error is returned.
+// Calling it again replaces the registered regions, IDs generated with
+// previous registration may report incorrect regions.
+func RegisterRegions(bits int, regions ...string) error {
+	if bits < 1 || bits > 15 {
+		return fmt.Errorf("xxid: region bits %d out of range [1, 15]", bits)
+	}
+	if capacity := 1<<uint(bits) - 1; len(regions) > capacity {
+		return fmt.Errorf("xxid: too many regions, %d bits allows at most %d regions", bits, capacity)
+	}
+	codes := make(map[string]uint16, len(regions))
+	for i, name := range regions {
+		if _, ok := codes[name]; ok || name == "" {
+			return fmt.Errorf("xxid: region name %q is empty or duplicate", name)
+		}
+		codes[name] = uint16(i + 1)
+	}
+
+	regionRegistry.mu.Lock()
+	regionRegistry.bits = uint(bits)
+	regionRegistry.codes = codes
+	regionRegistry.names = append([]string{""}, regions...)
+	regionRegistry.mu.Unlock()
+	return nil
+}
+
+// UseRegion sets the generator to encode the given region into the
+// highest bits of flag, the lower bits set by UseFlag are kept, thus
+// UseFlag should be called before UseRegion if both are used.
+//
+// It panics if the region is not registered by RegisterRegions.
+func (g *Generator) UseRegion(region string) *Generator {
+	g.checkReloaded()
+	regionRegistry.mu.RLock()
+	code, ok := regionRegistry.codes[region]
+	shift := 15 - regionRegistry.bits
+	regionRegistry.mu.RUnlock()
+	if !ok {
+		panic(fmt.Sprintf("xxid: region %q is not registered", region))
+	}
+	lowMask := uint16(1)<<shift - 1
+	g.flag = flagMask | code<<shift | g.flag&lowMask
+	return g
+}
+
+// Region returns the region encoded in the ID's flag by
+// Generator.UseRegion, it returns "" if the flag is not set or the
+// region is unknown.
+func (id ID) Region() string {
+	if id.flag&flagMask == 0 {
+		return ""
+	}
+	regionRegistry.mu.RLock()
+	defer regionRegistry.mu.RUnlock()
+	if regionRegistry.bits == 0 {
+		return ""
+	}
+	code := int(id.Flag() >> (15 - regionRegistry.bits))
+	if code >= len(regionRegistry.names) {
+		return ""
+	}
+	return regionRegistry.names[code]
+}
@@ -0,0 +1,49 @@
+package xxid
+
+import "testing"
+
+// saveRegions saves the region registry, the returned function restores
+// it, thus tests don't leak registered regions to others.
+func saveRegions() (restore func()) {
+	regionRegistry.mu.Lock()
+	bits, codes, names := regionRegistry.bits, regionRegistry.codes, regionRegistry.names
+	regionRegistry.mu.Unlock()
+	return func() {
+		regionRegistry.mu.Lock()
+		regionRegistry.bits, regionRegistry.codes, regionRegistry.names = bits, codes, names
+		regionRegistry.mu.Unlock()
+	}
+}
+
+func TestRegion(t *testing.T) {
+	defer saveRegions()()
+
+	if err := RegisterRegions(2, "a", "b", "c", "d"); err == nil {
+		t.Fatalf("expect error for exceeding capacity")
+	}
+	if err := RegisterRegions(4, "a", "a"); err == nil {
+		t.Fatalf("expect error for duplicate regions")
+	}
+	if err := RegisterRegions(4, "us-east-1", "eu-west-1"); err != nil {
+		t.Fatalf("failed register regions, err= %v", err)
+	}
+
+	gen := NewGenerator().UseFlag(123).UseRegion("eu-west-1")
+	id := gen.New()
+	if got := id.Region(); got != "eu-west-1" {
+		t.Fatalf("region not match, got= %q", got)
+	}
+	if got := id.Flag(); got != 2<<11|123 {
+		t.Fatalf("flag not match, got= %v", got)
+	}
+	if got := NewGenerator().UseFlag(123).New().Region(); got != "" {
+		t.Fatalf("region should be empty, got= %q", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expect panic for unregistered region")
+		}
+	}()
+	NewGenerator().UseRegion("unknown")
+}
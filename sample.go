@@ -0,0 +1,25 @@
+package xxid
+
+import "hash/fnv"
+
+// SampleIn reports whether the ID is in a sample of the given rate, which
+// is in range [0, 1], e.g. 0.01 samples about 1% of IDs.
+//
+// The decision is a stable hash of the ID excluding the timestamp, thus
+// services which see the same ID make the same decision without sharing
+// state, and an ID sampled at a rate is also sampled at higher rates.
+func (id ID) SampleIn(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	h.Write(id.encodeBinary()[6:])
+	// mix64 spreads the bits of hashes of similar inputs, e.g. IDs
+	// differ only in counter
+	x := mix64(h.Sum64())
+	// compare the top 53 bits, which are exact in float64
+	return float64(x>>11) < rate*(1<<53)
+}
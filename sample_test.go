@@ -0,0 +1,38 @@
+package xxid
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIDSampleIn(t *testing.T) {
+	gen := NewGenerator()
+	const n = 100000
+	sampled := 0
+	for i := 0; i < n; i++ {
+		id := gen.New()
+		if id.SampleIn(0.1) {
+			sampled++
+			if !id.SampleIn(0.5) {
+				t.Fatal("ID sampled at 0.1 but not at 0.5")
+			}
+		}
+		if id.SampleIn(0) || !id.SampleIn(1) {
+			t.Fatal("unexpected decision for rate 0 or 1")
+		}
+	}
+	if ratio := float64(sampled) / n; math.Abs(ratio-0.1) > 0.01 {
+		t.Fatalf("sampled ratio not match, want= %v, got= %v", 0.1, ratio)
+	}
+
+	id := gen.New()
+	parsed, _ := ParseBase62(id.Base62())
+	other := id
+	other.timeMsec += int64(time.Hour / time.Millisecond)
+	for _, rate := range []float64{0.01, 0.3, 0.7} {
+		if parsed.SampleIn(rate) != id.SampleIn(rate) || other.SampleIn(rate) != id.SampleIn(rate) {
+			t.Fatalf("decision is not stable at rate %v", rate)
+		}
+	}
+}
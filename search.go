@@ -0,0 +1,83 @@
+package xxid
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// CompareIDs compares a and b in the same order as their binary forms,
+// which is the order of timestamp, machine ID type, counter, machine ID,
+// pid or port and flag, an ID in the compact layout sorts before the
+// same ID in the full layout. It returns -1 if a < b, 0 if a == b, and
+// 1 if a > b, it can be used with slices.SortFunc and
+// slices.BinarySearchFunc.
+func CompareIDs(a, b ID) int {
+	switch {
+	case a.timeMsec != b.timeMsec:
+		return cmpInt64(a.timeMsec, b.timeMsec)
+	case a.mIDType != b.mIDType:
+		return cmpInt64(int64(a.mIDType), int64(b.mIDType))
+	case a.counter != b.counter:
+		return cmpInt64(int64(a.counter), int64(b.counter))
+	}
+	if c := bytes.Compare(a.MachineID(), b.MachineID()); c != 0 {
+		return c
+	}
+	switch {
+	case a.pidOrPort != b.pidOrPort:
+		return cmpInt64(int64(a.pidOrPort), int64(b.pidOrPort))
+	case a.flag != b.flag:
+		return cmpInt64(int64(a.flag), int64(b.flag))
+	case a.compact != b.compact:
+		if a.compact {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// IsSorted reports whether ids are sorted in ascending order of their
+// binary forms.
+func IsSorted(ids []ID) bool {
+	for i := 1; i < len(ids); i++ {
+		if CompareIDs(ids[i-1], ids[i]) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func cmpInt64(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// Search searches for target in ids sorted in ascending order of their
+// binary forms, it returns the index where target is found, or where it
+// would be inserted if not found, and whether it is found.
+func Search(ids []ID, target ID) (int, bool) {
+	i := sort.Search(len(ids), func(i int) bool {
+		return CompareIDs(ids[i], target) >= 0
+	})
+	return i, i < len(ids) && ids[i] == target
+}
+
+// RangeIndices returns the range ids[lo:hi] of IDs generated in the time
+// window [from, to), ids must be sorted in ascending order.
+func RangeIndices(ids []ID, from, to time.Time) (lo, hi int) {
+	fromNsec, toNsec := from.UnixNano(), to.UnixNano()
+	lo = sort.Search(len(ids), func(i int) bool {
+		return ids[i].timeMsec*1e6 >= fromNsec
+	})
+	hi = lo + sort.Search(len(ids)-lo, func(i int) bool {
+		return ids[lo+i].timeMsec*1e6 >= toNsec
+	})
+	return lo, hi
+}
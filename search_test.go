@@ -0,0 +1,78 @@
+package xxid
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCompareIDs(t *testing.T) {
+	gen8 := NewGenerator().UseMachineID([]byte("abcdefgh"))
+	var ids []ID
+	for i := 0; i < 200; i++ {
+		ts := time.Unix(1637371300, int64(rand.Intn(3))*1e6)
+		switch i % 3 {
+		case 0:
+			ids = append(ids, NewWithTime(ts))
+		case 1:
+			ids = append(ids, gen8.NewWithTime(ts))
+		case 2:
+			ids = append(ids, NewWithTime(ts).SetFlag(uint16(i)))
+		}
+	}
+	for _, a := range ids {
+		for _, b := range ids {
+			want := bytes.Compare(a.Binary(), b.Binary())
+			if got := CompareIDs(a, b); got != want {
+				t.Fatalf("compare result not match, want= %v, got= %v", want, got)
+			}
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	base := time.Unix(1637371300, 0)
+	var ids []ID
+	for i := 0; i < 100; i++ {
+		ids = append(ids, NewWithTime(base.Add(time.Duration(i/10)*time.Second)))
+	}
+	ids[0], ids[99] = ids[99], ids[0]
+	if IsSorted(ids) {
+		t.Fatalf("IDs should not be sorted")
+	}
+	sort.Slice(ids, func(i, j int) bool { return CompareIDs(ids[i], ids[j]) < 0 })
+	if !IsSorted(ids) {
+		t.Fatalf("IDs should be sorted")
+	}
+
+	for i, id := range ids {
+		if got, ok := Search(ids, id); !ok || got != i {
+			t.Fatalf("search result not match, want= %v, got= %v, %v", i, got, ok)
+		}
+	}
+	if _, ok := Search(ids, New()); ok {
+		t.Fatalf("should not find absent ID")
+	}
+
+	lo, hi := RangeIndices(ids, base.Add(2*time.Second), base.Add(5*time.Second))
+	if lo != 20 || hi != 50 {
+		t.Fatalf("range indices not match, got= %v, %v", lo, hi)
+	}
+	if lo, hi := RangeIndices(ids, base.Add(time.Hour), base.Add(2*time.Hour)); lo != hi {
+		t.Fatalf("range should be empty, got= %v, %v", lo, hi)
+	}
+}
+
+func TestCompareIDs_Compact(t *testing.T) {
+	id := NewGenerator().UseCompactLayout().New()
+	full := id
+	full.compact = false
+	if CompareIDs(id, id) != 0 || CompareIDs(id, full) != -1 || CompareIDs(full, id) != 1 {
+		t.Fatalf("CompareIDs disagrees with ==")
+	}
+	if bytes.Compare(id.Binary(), full.Binary()) != -1 {
+		t.Fatalf("CompareIDs disagrees with the binary forms")
+	}
+}
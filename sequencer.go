@@ -0,0 +1,297 @@
+package xxid
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// globalSeq is the time and counter state shared by all generators
+// in the process.
+var globalSeq sequencer
+
+// SequenceMode controls whether a generator shares the time and counter
+// state with other generators in the process.
+type SequenceMode int
+
+const (
+	// SharedSequence makes a generator share the time and counter state
+	// with all other generators in the process which use this mode, IDs
+	// generated by them are strictly ordered across generators.
+	// It is the default mode.
+	SharedSequence SequenceMode = iota
+
+	// IsolatedSequence gives a generator its own time and counter state,
+	// IDs are ordered only within the generator, and the generator does
+	// not contend with others on the shared state.
+	//
+	// The uniqueness is guaranteed only within the generator, generators
+	// in this mode must differ in machine ID, pid, port or flag, else
+	// they may generate duplicate IDs.
+	IsolatedSequence
+)
+
+// UseSequenceMode sets the sequence mode of the generator, see
+// SharedSequence and IsolatedSequence. It should be called before
+// generating any IDs, switching to IsolatedSequence starts a new time
+// and counter state. The sequence mode can not be changed by Reload.
+func (g *Generator) UseSequenceMode(mode SequenceMode) *Generator {
+	switch mode {
+	case SharedSequence:
+		g.seq = &globalSeq
+	case IsolatedSequence:
+		if g.seq == &globalSeq {
+			g.seq = &sequencer{counter: fastrand()}
+		}
+	}
+	return g
+}
+
+// SequenceMode returns the sequence mode of the generator.
+func (g *Generator) SequenceMode() SequenceMode {
+	if g.seq == &globalSeq {
+		return SharedSequence
+	}
+	return IsolatedSequence
+}
+
+// sequencer holds the time and counter state used to generate IDs.
+//
+// generated is accessed atomically, it is the first field to be 64-bit
+// aligned on 32-bit platforms.
+type sequencer struct {
+	generated uint64
+	counter   uint32
+
+	mu             sync.Mutex
+	timeAndCounter int64
+	regressions    uint64
+	lastWall       int64 // last clock reading, to tell regressions from borrowed time
+}
+
+func (s *sequencer) incrCounter() uint16 {
+	atomic.AddUint64(&s.generated, 1)
+	return uint16(atomic.AddUint32(&s.counter, 1))
+}
+
+// next guarantees that the combination of the returned time and counter
+// will never be duplicate for the sequencer, even the clock has been
+// turned back or leap second happens.
+func (s *sequencer) next() (timeMsec int64, counter uint16) {
+	t, coarse := nowMsec()
+	c := s.incrCounter()
+	tac := t<<16 | int64(c) // time and counter
+
+	s.mu.Lock()
+	prev := s.timeAndCounter
+	if tac <= prev && coarse {
+		// the coarse clock lags, read the wall clock
+		t = wallClockMsec()
+		tac = t<<16 | int64(c)
+	}
+	if tac <= prev {
+		if t < s.lastWall {
+			s.regressions++
+		}
+		tac = prev + 1
+	}
+	s.lastWall = t
+	s.timeAndCounter = tac
+	t, c = tac>>16, uint16(tac)
+	s.mu.Unlock()
+	return t, c
+}
+
+// nextAt returns the time and counter for an ID with the given time,
+// which is after last, the time and counter of the previous ID issued
+// by the caller. If it is not before the latest combination of the
+// sequencer, or if it is moved forward from a last one which is, it is
+// reserved from the sequencer like next, and live is true. Otherwise it
+// has a historical time and the sequencer is not changed.
+func (s *sequencer) nextAt(t, last int64, lastLive bool) (tac int64, live bool) {
+	tac = t<<16 | int64(s.incrCounter())
+	moved := tac <= last
+	if moved {
+		tac = last + 1
+	}
+
+	s.mu.Lock()
+	live = tac>>16 >= s.timeAndCounter>>16 || moved && lastLive
+	if live {
+		if tac <= s.timeAndCounter {
+			tac = s.timeAndCounter + 1
+		}
+		s.timeAndCounter = tac
+	}
+	s.mu.Unlock()
+	return tac, live
+}
+
+// reserve reserves n consecutive combinations of time and counter, it
+// returns the first one.
+func (s *sequencer) reserve(n int) int64 {
+	t, coarse := nowMsec()
+	atomic.AddUint64(&s.generated, uint64(n))
+	c := uint16(atomic.AddUint32(&s.counter, uint32(n)) - uint32(n) + 1)
+	first := t<<16 | int64(c)
+
+	s.mu.Lock()
+	prev := s.timeAndCounter
+	if first <= prev && coarse {
+		t = wallClockMsec()
+		first = t<<16 | int64(c)
+	}
+	if first <= prev {
+		if t < s.lastWall {
+			s.regressions++
+		}
+		first = prev + 1
+	}
+	s.lastWall = t
+	s.timeAndCounter = first + int64(n) - 1
+	s.mu.Unlock()
+	return first
+}
+
+func incrCounter() uint16 {
+	return globalSeq.incrCounter()
+}
+
+// readTimeAndCounter guarantees that the combination of the returned
+// time and counter will never be duplicate inside a process, even the
+// clock has been turned back or leap second happens.
+func readTimeAndCounter() (timeMsec int64, counter uint16) {
+	return globalSeq.next()
+}
+
+// ProcessUniqueShort returns a value in the same format as ID.Short,
+// which is guaranteed to be unique inside the process, regardless of
+// the sequence mode of generators, and increasing as long as the
+// process runs.
+//
+// It is backed by the process-wide time and counter state shared by
+// New and generators using SharedSequence, thus it never collides with
+// the short values of IDs generated by them. It can be used as database
+// keys where a 64 bits integer is required, the value can be expanded
+// to a full ID by Generator.FromShort.
+func ProcessUniqueShort() int64 {
+	t, c := globalSeq.next()
+	return t<<16 | int64(c)
+}
+
+// CounterState is a snapshot of the time and counter state of a
+// generator.
+type CounterState struct {
+	// LastTime is the time of the last issued ID by New.
+	LastTime time.Time
+
+	// LastCounter is the counter of the last issued ID by New.
+	LastCounter uint16
+
+	// Counter is the current counter value, the next ID gets the
+	// counter value incremented from it.
+	Counter uint16
+}
+
+// CounterState returns a snapshot of the generator's time and counter
+// state, it can be used by recovery tooling to save the state and
+// resume from it by SetCounter.
+//
+// Note that the state is shared by all generators in the process, unless
+// the generator uses IsolatedSequence.
+func (g *Generator) CounterState() CounterState {
+	s := g.seq
+	s.mu.Lock()
+	tac := s.timeAndCounter
+	s.mu.Unlock()
+	return CounterState{
+		LastTime:    time.Unix(0, (tac>>16)*1e6),
+		LastCounter: uint16(tac),
+		Counter:     uint16(atomic.LoadUint32(&s.counter)),
+	}
+}
+
+// SetCounter sets the counter value, the next ID generated gets the
+// counter value c, unless it would be duplicate with or go backwards
+// from a previously issued ID in the same millisecond, in which case
+// the time and counter are moved forward.
+//
+// It is intended to resume from a known sequence after restoring a
+// snapshot, or to force counter wraparound in tests.
+// Note that the state is shared by all generators in the process, unless
+// the generator uses IsolatedSequence.
+func (g *Generator) SetCounter(c uint16) {
+	atomic.StoreUint32(&g.seq.counter, uint32(c-1))
+}
+
+// MaxReserve is the max number of IDs which can be reserved by one call
+// of Generator.Reserve.
+const MaxReserve = 1 << 16
+
+var errInvalidReserveSize = errors.New("xxid: reserve size is invalid")
+
+// Reserve atomically reserves n consecutive combinations of time and
+// counter, it returns the value of ID.Short of the first one, the i-th
+// reserved ID has the short value firstShort+i, which can be expanded to
+// a full ID by Generator.FromShort.
+//
+// It lets batch writers assign IDs to n rows with a single lock
+// acquisition and guaranteed contiguity. The reserved values never
+// collide with IDs generated by New. If the counter of the current
+// millisecond can not hold n values, the range continues into the
+// following milliseconds.
+// It returns an error if n is not in range [1, MaxReserve].
+func (g *Generator) Reserve(n int) (firstShort int64, err error) {
+	if n < 1 || n > MaxReserve {
+		return 0, errInvalidReserveSize
+	}
+	return g.seq.reserve(n), nil
+}
+
+// Stats holds runtime statistics of a generator.
+type Stats struct {
+	// Generated is the number of IDs generated.
+	Generated uint64
+
+	// LastTime is the time of the last issued ID by New.
+	LastTime time.Time
+
+	// LastCounter is the counter of the last issued ID by New.
+	LastCounter uint16
+
+	// ClockRegressions is the number of times the wall clock was found
+	// turned back and compensated by moving the time forward.
+	ClockRegressions uint64
+
+	// Borrowed is how far LastTime is ahead of the wall clock, it is
+	// zero if LastTime is not in the future. A large value indicates
+	// that the generator is running far into the future.
+	Borrowed time.Duration
+}
+
+// Stats returns runtime statistics of the generator, it can be used by
+// health checks to flag a generator running far into the future.
+//
+// Note that the statistics are shared by all generators in the process,
+// unless the generator uses IsolatedSequence.
+func (g *Generator) Stats() Stats {
+	s := g.seq
+	s.mu.Lock()
+	tac := s.timeAndCounter
+	regressions := s.regressions
+	s.mu.Unlock()
+
+	now := time.Now()
+	last := time.Unix(0, (tac>>16)*1e6)
+	stats := Stats{
+		Generated:        atomic.LoadUint64(&s.generated),
+		LastTime:         last,
+		LastCounter:      uint16(tac),
+		ClockRegressions: regressions,
+	}
+	if last.After(now) {
+		stats.Borrowed = last.Sub(now)
+	}
+	return stats
+}
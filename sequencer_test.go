@@ -0,0 +1,156 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestGenerator_SetCounter(t *testing.T) {
+	gen := NewGenerator()
+
+	// NewWithTime does not go through the time guard, thus the
+	// counter values are deterministic.
+	gen.SetCounter(0xfffe)
+	now := time.Now()
+	for _, want := range []uint16{0xfffe, 0xffff, 0} {
+		if got := gen.NewWithTime(now).Counter(); got != want {
+			t.Fatalf("counter not match, want= %v, got= %v", want, got)
+		}
+	}
+
+	gen.SetCounter(0xffff)
+	id1 := gen.New()
+	id2 := gen.New()
+	if id1.Short() >= id2.Short() {
+		t.Fatalf("IDs not ordered across counter wraparound")
+	}
+
+	state := gen.CounterState()
+	if state.LastCounter != id2.Counter() || !state.LastTime.Equal(id2.Time()) {
+		t.Fatalf("counter state not match, got= %+v", state)
+	}
+	if state.Counter != 0 {
+		t.Fatalf("counter state not match, got= %+v", state)
+	}
+}
+
+func TestGenerator_Stats(t *testing.T) {
+	seq := &sequencer{}
+	gen := NewGenerator()
+	gen.seq = seq
+
+	id := gen.New()
+	stats := gen.Stats()
+	if stats.Generated != 1 || stats.LastCounter != id.Counter() ||
+		!stats.LastTime.Equal(id.Time()) || stats.ClockRegressions != 0 || stats.Borrowed != 0 {
+		t.Fatalf("stats not match, got= %+v", stats)
+	}
+
+	// Time borrowed after a counter wraparound is not a regression.
+	seq.mu.Lock()
+	seq.timeAndCounter = (seq.lastWall+2)<<16 | 0xffff
+	seq.mu.Unlock()
+	gen.New()
+	if stats = gen.Stats(); stats.ClockRegressions != 0 {
+		t.Fatalf("borrowed time counted as clock regression, got= %+v", stats)
+	}
+
+	// Simulate the clock being turned back.
+	future := time.Now().Add(time.Hour).UnixNano() / 1e6
+	seq.timeAndCounter = future << 16
+	seq.lastWall = future
+	id = gen.New()
+	stats = gen.Stats()
+	if stats.Generated != 3 || stats.ClockRegressions != 1 ||
+		stats.Borrowed < 59*time.Minute || id.Time().Before(time.Now().Add(59*time.Minute)) {
+		t.Fatalf("stats not match, got= %+v", stats)
+	}
+}
+
+func TestGenerator_Reserve(t *testing.T) {
+	gen := NewGenerator()
+	before := gen.New().Short()
+	first, err := gen.Reserve(1000)
+	if err != nil {
+		t.Fatalf("failed reserve, err= %v", err)
+	}
+	if first <= before {
+		t.Fatalf("reserved range should follow previous IDs")
+	}
+	if after := gen.New().Short(); after < first+1000 {
+		t.Fatalf("reserved range collides with new ID")
+	}
+
+	for _, n := range []int{0, -1, MaxReserve + 1} {
+		if _, err := gen.Reserve(n); err == nil {
+			t.Fatalf("expect error for reserve size %d", n)
+		}
+	}
+}
+
+func TestGenerator_SequenceMode(t *testing.T) {
+	shared := NewGenerator()
+	if shared.SequenceMode() != SharedSequence {
+		t.Fatalf("default mode should be SharedSequence")
+	}
+
+	isolated := NewGenerator().UseFlag(1).UseSequenceMode(IsolatedSequence)
+	if isolated.SequenceMode() != IsolatedSequence {
+		t.Fatalf("mode not match, got= %v", isolated.SequenceMode())
+	}
+	before := shared.Stats().Generated
+	isolated.New()
+	isolated.New()
+	if got := shared.Stats().Generated; got != before {
+		t.Fatalf("isolated generator should not touch shared state")
+	}
+	if got := isolated.Stats().Generated; got != 2 {
+		t.Fatalf("isolated stats not match, got= %v", got)
+	}
+
+	isolated.UseSequenceMode(SharedSequence)
+	if isolated.SequenceMode() != SharedSequence {
+		t.Fatalf("mode not match, got= %v", isolated.SequenceMode())
+	}
+
+	gen, err := NewGeneratorE(WithSequenceMode(IsolatedSequence))
+	if err != nil || gen.SequenceMode() != IsolatedSequence {
+		t.Fatalf("WithSequenceMode failed, err= %v", err)
+	}
+	if _, err := NewGeneratorE(WithSequenceMode(100)); err == nil {
+		t.Fatalf("expect error for unknown sequence mode")
+	}
+}
+
+func TestProcessUniqueShort(t *testing.T) {
+	isolated := NewGenerator().UseSequenceMode(IsolatedSequence)
+	seen := make(map[int64]bool)
+	prev := int64(0)
+	for i := 0; i < 10000; i++ {
+		var short int64
+		switch i % 3 {
+		case 0:
+			short = ProcessUniqueShort()
+		case 1:
+			short = New().Short()
+		case 2:
+			isolated.New()
+			short = ProcessUniqueShort()
+		}
+		if seen[short] || short <= prev {
+			t.Fatalf("short value is duplicate or not increasing: %v", short)
+		}
+		seen[short] = true
+		prev = short
+	}
+}
+
+func TestAtomicAlignment(t *testing.T) {
+	// 64-bit atomic operations panic on 32-bit platforms if the
+	// fields are not 64-bit aligned.
+	if unsafe.Offsetof(sequencer{}.generated)%8 != 0 ||
+		unsafe.Offsetof(IDPool{}.head)%8 != 0 || unsafe.Offsetof(IDPool{}.tail)%8 != 0 {
+		t.Fatalf("fields accessed atomically are not 64-bit aligned")
+	}
+}
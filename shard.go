@@ -0,0 +1,15 @@
+package xxid
+
+import "hash/fnv"
+
+// ShardKey returns a stable 32 bits hash of the ID, it is computed as
+// FNV-1a hash of the ID's binary form.
+//
+// Since the ID's binary form starts with the timestamp, IDs which are
+// close in time are adjacent when sorted, ShardKey can be used to spread
+// such IDs evenly across shards or partitions.
+func (id ID) ShardKey() uint32 {
+	h := fnv.New32a()
+	h.Write(id.encodeBinary())
+	return h.Sum32()
+}
@@ -0,0 +1,99 @@
+package xxid
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// MaxShards is the max number of shards of a ShardedGenerator.
+const MaxShards = 256
+
+var errInvalidShardCount = errors.New("xxid: shard count is invalid")
+
+// ShardedGenerator is a logical generator backed by n shards, each shard
+// owns a distinct range of counter values and its own time state, thus
+// generating IDs on different shards does not contend. Callers are
+// routed to shards by a per-P cache, goroutines running on the same P
+// mostly use the same shard.
+//
+// IDs are unique across shards, since the counter ranges are disjoint,
+// and ordered within a shard, but not strictly ordered across shards.
+// Each shard generates at most 65536/n IDs per millisecond before
+// borrowing the next millisecond.
+//
+// Like IsolatedSequence, the uniqueness is guaranteed only among IDs
+// generated by the ShardedGenerator, it must differ from other
+// generators in machine ID, pid, port or flag.
+type ShardedGenerator struct {
+	cfg    *Generator
+	shards []genShard
+	next   uint32
+	pool   sync.Pool
+}
+
+type genShard struct {
+	mu       sync.Mutex
+	base     uint16 // first counter value of the range
+	size     int    // number of counter values of the range
+	lastTime int64
+	used     int
+
+	_ [64]byte // avoid false sharing
+}
+
+// NewShardedGenerator returns a ShardedGenerator with n shards, opts are
+// applied like NewGeneratorE. n must be in range [1, MaxShards], a
+// typical value is runtime.GOMAXPROCS(0).
+func NewShardedGenerator(n int, opts ...Option) (*ShardedGenerator, error) {
+	if n < 1 || n > MaxShards {
+		return nil, errInvalidShardCount
+	}
+	cfg, err := NewGeneratorE(opts...)
+	if err != nil {
+		return nil, err
+	}
+	g := &ShardedGenerator{cfg: cfg, shards: make([]genShard, n)}
+	size := (1 << 16) / n
+	for i := range g.shards {
+		g.shards[i].base = uint16(i * size)
+		g.shards[i].size = size
+	}
+	g.pool.New = func() interface{} {
+		i := atomic.AddUint32(&g.next, 1) % uint32(len(g.shards))
+		return &g.shards[i]
+	}
+	return g, nil
+}
+
+// Shards returns the number of shards.
+func (g *ShardedGenerator) Shards() int {
+	return len(g.shards)
+}
+
+// New generates a unique ID.
+func (g *ShardedGenerator) New() ID {
+	s := g.pool.Get().(*genShard)
+	timeMsec, counter := s.nextTimeAndCounter()
+	g.pool.Put(s)
+	return newID(g.cfg.config(), timeMsec, counter)
+}
+
+func (s *genShard) nextTimeAndCounter() (int64, uint16) {
+	t, _ := nowMsec()
+	s.mu.Lock()
+	if t > s.lastTime {
+		s.lastTime = t
+		s.used = 0
+	} else if s.used == s.size {
+		// counter range of the millisecond is exhausted, or the clock
+		// has been turned back
+		s.lastTime++
+		s.used = 0
+	}
+	t = s.lastTime
+	c := s.base + uint16(s.used)
+	s.used++
+	s.mu.Unlock()
+	return t, c
+}
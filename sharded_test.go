@@ -0,0 +1,74 @@
+package xxid
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestShardedGenerator(t *testing.T) {
+	for _, n := range []int{0, MaxShards + 1} {
+		if _, err := NewShardedGenerator(n); err == nil {
+			t.Fatalf("expect error for %d shards", n)
+		}
+	}
+
+	gen, err := NewShardedGenerator(runtime.GOMAXPROCS(0)+3, WithFlag(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const workers, perWorker = 8, 20000
+	results := make([][]ID, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ids := make([]ID, perWorker)
+			for i := range ids {
+				ids[i] = gen.New()
+			}
+			results[w] = ids
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool, workers*perWorker)
+	for _, ids := range results {
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %v", id)
+			}
+			seen[id] = true
+			if id.Flag() != 9 {
+				t.Fatalf("option not applied")
+			}
+		}
+	}
+}
+
+func TestShardedGenerator_Exhausted(t *testing.T) {
+	gen, _ := NewShardedGenerator(MaxShards)
+	s := &gen.shards[1]
+	seen := make(map[int64]bool)
+	for i := 0; i < 3*s.size; i++ {
+		tm, c := s.nextTimeAndCounter()
+		if c < s.base || int(c-s.base) >= s.size {
+			t.Fatalf("counter %d out of range", c)
+		}
+		short := tm<<16 | int64(c)
+		if seen[short] {
+			t.Fatalf("duplicate time and counter")
+		}
+		seen[short] = true
+	}
+}
+
+func BenchmarkShardedGenerator(b *testing.B) {
+	gen, _ := NewShardedGenerator(runtime.GOMAXPROCS(0))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gen.New()
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package xxid
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+)
+
+// Layout of short values with a folded fingerprint, the timestamp takes
+// 42 bits, which lasts until year 2109.
+const (
+	FoldedFingerprintBits = 5
+	foldedTimeBits        = 42
+	foldedFingerprintMask = 1<<FoldedFingerprintBits - 1
+)
+
+var errFoldedTimeOverflow = errors.New("xxid: timestamp overflows the folded short form")
+
+// ErrShortCollision is returned by ShortFolder.Fold in strict mode when
+// the fingerprint of an ID is already taken by another machine ID and
+// pid, thus the folded short values may collide.
+var ErrShortCollision = errors.New("xxid: fingerprint of folded short form is taken by another generator")
+
+// ShortFingerprint returns a 5 bits fingerprint of the ID's machine ID
+// type, machine ID and pid or port, see ShortFolder.
+func (id ID) ShortFingerprint() uint8 {
+	h := fnv.New32a()
+	h.Write([]byte{byte(id.mIDType), byte(id.pidOrPort >> 8), byte(id.pidOrPort)})
+	h.Write(id.MachineID())
+	sum := h.Sum32()
+	return uint8(sum^sum>>8^sum>>16^sum>>24) & foldedFingerprintMask
+}
+
+// ShortFolder folds a fingerprint of the machine ID and pid into the
+// unused high bits of ID.Short, to disambiguate IDs generated in the same
+// millisecond with the same counter value by different generators, whose
+// short values collide since the counter is only 16 bits.
+//
+// The folded value is laid out as 1 bit zero (sign), 5 bits fingerprint,
+// 42 bits timestamp and 16 bits counter, thus it's positive and ordered
+// by time for IDs from the same generator. With 5 bits, two generators
+// still have a chance of 1/32 to share the fingerprint, enable Strict to
+// detect it.
+//
+// The zero value is ready to use. A ShortFolder is safe for concurrent
+// use.
+type ShortFolder struct {
+	// Strict makes Fold return ErrShortCollision when the fingerprint of
+	// an ID is taken by another machine ID and pid folded before, which
+	// means the folded value can not be made unique.
+	Strict bool
+
+	mu     sync.Mutex
+	owners map[uint8]machineEntry
+}
+
+// Fold returns the short value of id with its fingerprint folded in.
+// It returns an error if the timestamp does not fit in 42 bits, or in
+// strict mode, if the fingerprint is taken by another generator.
+func (f *ShortFolder) Fold(id ID) (int64, error) {
+	if id.timeMsec < 0 || id.timeMsec >= 1<<foldedTimeBits {
+		return 0, errFoldedTimeOverflow
+	}
+	fp := id.ShortFingerprint()
+	if f.Strict {
+		owner := machineEntry{mIDType: id.mIDType, pidOrPort: id.pidOrPort, machineID: id.machineID}
+		f.mu.Lock()
+		if f.owners == nil {
+			f.owners = make(map[uint8]machineEntry)
+		}
+		prev, ok := f.owners[fp]
+		if !ok {
+			f.owners[fp] = owner
+		}
+		f.mu.Unlock()
+		if ok && prev != owner {
+			return 0, ErrShortCollision
+		}
+	}
+	return int64(fp)<<(foldedTimeBits+16) | id.Short(), nil
+}
+
+// UnfoldShort splits a value returned by ShortFolder.Fold into the
+// short value, which can be expanded by Generator.FromShort, and the
+// fingerprint.
+func UnfoldShort(folded int64) (short int64, fingerprint uint8) {
+	const shortBits = foldedTimeBits + 16
+	return folded & (1<<shortBits - 1), uint8(folded>>shortBits) & foldedFingerprintMask
+}
@@ -0,0 +1,54 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShortFolder(t *testing.T) {
+	gen := NewGenerator()
+	id := gen.New()
+	var folder ShortFolder
+	folded, err := folder.Fold(id)
+	if err != nil || folded <= 0 {
+		t.Fatalf("failed fold ID, got= %v, err= %v", folded, err)
+	}
+	short, fp := UnfoldShort(folded)
+	if short != id.Short() || fp != id.ShortFingerprint() {
+		t.Fatalf("UnfoldShort result not match, want= %v, %v, got= %v, %v", id.Short(), id.ShortFingerprint(), short, fp)
+	}
+	if next, _ := folder.Fold(gen.New()); next <= folded {
+		t.Fatalf("folded values of a generator are not ordered")
+	}
+
+	// find two pids of which the fingerprints collide
+	var a, b ID
+	fps := map[uint8]uint16{}
+	for pid := uint16(1); ; pid++ {
+		x := NewGenerator().UsePort(pid).New()
+		if prev, ok := fps[x.ShortFingerprint()]; ok {
+			a = NewGenerator().UsePort(prev).New()
+			b = x
+			break
+		}
+		fps[x.ShortFingerprint()] = pid
+	}
+	strict := ShortFolder{Strict: true}
+	if _, err := strict.Fold(a); err != nil {
+		t.Fatalf("failed fold ID, err= %v", err)
+	}
+	if _, err := strict.Fold(a); err != nil {
+		t.Fatalf("same generator should not collide, err= %v", err)
+	}
+	if _, err := strict.Fold(b); err != ErrShortCollision {
+		t.Fatalf("error not match, want= %v, got= %v", ErrShortCollision, err)
+	}
+	if _, err := folder.Fold(b); err != nil {
+		t.Fatalf("non-strict folder should not fail, err= %v", err)
+	}
+
+	future := NewWithTime(time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC))
+	if _, err := folder.Fold(future); err == nil {
+		t.Fatalf("expect error for timestamp overflow")
+	}
+}
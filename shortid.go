@@ -0,0 +1,146 @@
+package xxid
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var errInvalidShortLayout = errors.New("xxid: short layout is invalid")
+
+// maxShortBits is the max total bits of a ShortLayout, the sign bit is
+// not used, thus IDs are never negative.
+const maxShortBits = 63
+
+// ShortLayout describes the bit allocation of 64-bit integer IDs, which
+// consist of milliseconds since Epoch, logical shard and counter, from
+// the high bits to the low. The total bits must not exceed 63, the sign
+// bit is not used, thus IDs are never negative and keep ordered.
+//
+// ID.Short uses the layout DefaultShortLayout, other layouts trade the
+// time range for more shard or counter bits, e.g. 41 bits of time and
+// 22 bits of counter allows 4M IDs per millisecond for about 69 years.
+type ShortLayout struct {
+	TimeBits    int
+	ShardBits   int
+	CounterBits int
+	Epoch       time.Time
+}
+
+// DefaultShortLayout is the layout of the value returned by ID.Short.
+var DefaultShortLayout = ShortLayout{
+	TimeBits:    47,
+	CounterBits: 16,
+	Epoch:       time.Unix(0, 0),
+}
+
+func (l ShortLayout) validate() error {
+	if l.TimeBits < 1 || l.ShardBits < 0 || l.CounterBits < 1 || l.CounterBits > 32 ||
+		l.ShardBits > 16 || l.TimeBits+l.ShardBits+l.CounterBits > maxShortBits {
+		return errInvalidShortLayout
+	}
+	return nil
+}
+
+// Parse splits an integer ID of the layout into its time, shard and
+// counter.
+func (l ShortLayout) Parse(short int64) (t time.Time, shard uint16, counter uint32) {
+	msec := int64(uint64(short)>>uint(l.ShardBits+l.CounterBits)) + l.Epoch.UnixNano()/1e6
+	shard = uint16(short>>uint(l.CounterBits)) & (1<<uint(l.ShardBits) - 1)
+	counter = uint32(short) & (1<<uint(l.CounterBits) - 1)
+	return time.Unix(0, msec*1e6), shard, counter
+}
+
+// ShortIDGenerator generates 64-bit integer IDs of a ShortLayout.
+//
+// It is intended for systems keyed by int64, e.g. tables sharded by
+// Postgres schemas, the shard of a row can be read from its ID. Unlike
+// ID, such IDs contain no machine information, uniqueness across
+// processes relies on that each logical shard is served by only one
+// generator at a time.
+type ShortIDGenerator struct {
+	layout    ShortLayout
+	epochMsec int64
+	shard     func(key int64) uint16
+
+	counter uint32
+	mu      sync.Mutex
+	last    int64 // time and counter of the last issued ID
+}
+
+// NewShortIDGenerator returns a ShortIDGenerator of layout, shard maps
+// a key (e.g. user ID) to the logical shard, the result must fit in
+// layout.ShardBits, else New panics. shard may be nil if ShardBits is 0.
+// It returns an error if the layout is invalid.
+func NewShortIDGenerator(layout ShortLayout, shard func(key int64) uint16) (*ShortIDGenerator, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	if shard == nil {
+		shard = func(int64) uint16 { return 0 }
+	}
+	return &ShortIDGenerator{
+		layout:    layout,
+		epochMsec: layout.Epoch.UnixNano() / 1e6,
+		shard:     shard,
+	}, nil
+}
+
+// New generates an ID in the logical shard of key.
+//
+// When the counter of the current millisecond is exhausted or the clock
+// has been turned back, the time is moved forward to keep the IDs unique
+// and increasing.
+// It panics if the time is before Epoch or does not fit in TimeBits.
+func (g *ShortIDGenerator) New(key int64) int64 {
+	shardBits, counterBits := uint(g.layout.ShardBits), uint(g.layout.CounterBits)
+	shard := g.shard(key)
+	if uint64(shard) >= 1<<shardBits {
+		panic("xxid: logical shard out of range")
+	}
+	t := time.Now().UnixNano()/1e6 - g.epochMsec
+	if t < 0 {
+		panic("xxid: time is before epoch")
+	}
+	c := atomic.AddUint32(&g.counter, 1) & (1<<counterBits - 1)
+	tac := t<<counterBits | int64(c) // time and counter
+
+	g.mu.Lock()
+	if tac <= g.last {
+		tac = g.last + 1
+	}
+	t = tac >> counterBits
+	if t >= 1<<uint(g.layout.TimeBits) {
+		g.mu.Unlock()
+		panic("xxid: time out of range of the short layout")
+	}
+	g.last = tac
+	g.mu.Unlock()
+
+	c = uint32(tac) & (1<<counterBits - 1)
+	return t<<(shardBits+counterBits) | int64(shard)<<counterBits | int64(c)
+}
+
+// Layout returns the layout of g.
+func (g *ShortIDGenerator) Layout() ShortLayout {
+	return g.layout
+}
+
+// Time returns the generation time of an ID generated by g.
+func (g *ShortIDGenerator) Time(id int64) time.Time {
+	t, _, _ := g.layout.Parse(id)
+	return t
+}
+
+// Shard returns the logical shard of an ID generated by g.
+func (g *ShortIDGenerator) Shard(id int64) uint16 {
+	_, shard, _ := g.layout.Parse(id)
+	return shard
+}
+
+// Sequence returns the counter of an ID generated by g.
+func (g *ShortIDGenerator) Sequence(id int64) uint32 {
+	_, _, c := g.layout.Parse(id)
+	return c
+}
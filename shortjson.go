@@ -0,0 +1,34 @@
+package xxid
+
+import "strconv"
+
+// ShortJSON is an ID which marshals to a JSON number of its short form
+// returned by ID.Short, it is intended for payloads consumed by systems
+// which only handle integer IDs.
+//
+// Convert an ID to use it, e.g. xxid.ShortJSON(id), and convert it back
+// by ID(v). Note that the short form does not retain the machine ID,
+// pid or port and flag of an ID, when unmarshaling, these fields are
+// filled from the default generator, like ID.Scan does.
+// Also note that the value exceeds 2^53, consumers decoding JSON
+// numbers as float64 (e.g. JavaScript) lose precision.
+type ShortJSON ID
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v ShortJSON) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, ID(v).Short(), 10), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, a JSON null
+// is a no-op.
+func (v *ShortJSON) UnmarshalJSON(buf []byte) error {
+	if string(buf) == "null" {
+		return nil
+	}
+	short, err := strconv.ParseInt(b2s(buf), 10, 64)
+	if err != nil || short < 0 {
+		return errInvalidShortJSON
+	}
+	*v = ShortJSON(fromShort(defaultGenerator.config(), short))
+	return nil
+}
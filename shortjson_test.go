@@ -0,0 +1,32 @@
+package xxid
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestShortJSON(t *testing.T) {
+	id := New()
+	buf, err := json.Marshal(ShortJSON(id))
+	if err != nil {
+		t.Fatalf("failed marshal ShortJSON, err= %v", err)
+	}
+	if want := strconv.FormatInt(id.Short(), 10); string(buf) != want {
+		t.Fatalf("ShortJSON not match, want= %v, got= %s", want, buf)
+	}
+
+	var got ShortJSON
+	if err = json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("failed unmarshal ShortJSON, err= %v", err)
+	}
+	if ID(got).Short() != id.Short() || ID(got).MachineIDType() != id.MachineIDType() {
+		t.Fatalf("ShortJSON round trip not match")
+	}
+
+	for _, bad := range []string{`"abc"`, `-1`, `1.5`} {
+		if err = json.Unmarshal([]byte(bad), &got); err == nil {
+			t.Fatalf("expect error for %s", bad)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package xxid
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// Latest returns the largest ID of ids in the order of their binary
+// forms, which is the latest generated for IDs from the same generator.
+// It returns the zero ID if ids is empty.
+func Latest(ids []ID) ID {
+	if len(ids) == 0 {
+		return zeroID
+	}
+	latest := ids[0]
+	for _, id := range ids[1:] {
+		if CompareIDs(id, latest) > 0 {
+			latest = id
+		}
+	}
+	return latest
+}
+
+// Earliest returns the smallest ID of ids in the order of their binary
+// forms. It returns the zero ID if ids is empty.
+func Earliest(ids []ID) ID {
+	if len(ids) == 0 {
+		return zeroID
+	}
+	earliest := ids[0]
+	for _, id := range ids[1:] {
+		if CompareIDs(id, earliest) < 0 {
+			earliest = id
+		}
+	}
+	return earliest
+}
+
+// Dedup returns a new slice of ids with duplicates removed, the first
+// occurrence of each ID is kept in the original order.
+func Dedup(ids []ID) []ID {
+	out := make([]ID, 0, len(ids))
+	seen := make(map[ID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+// GroupByMachine groups ids by their machine ID type and machine ID,
+// the keys are formatted as "type:hex", e.g. "1:218b67c8", like
+// Generator.Identity without the pid. IDs in each group are kept in the
+// original order.
+func GroupByMachine(ids []ID) map[string][]ID {
+	groups := make(map[string][]ID)
+	keys := make(map[machineEntry]string)
+	for _, id := range ids {
+		entry := machineEntry{mIDType: id.mIDType, machineID: id.machineID}
+		key, ok := keys[entry]
+		if !ok {
+			buf := make([]byte, 0, 40)
+			buf = strconv.AppendInt(buf, int64(id.mIDType), 10)
+			buf = append(buf, ':')
+			buf = append(buf, hex.EncodeToString(id.MachineID())...)
+			key = b2s(buf)
+			keys[entry] = key
+		}
+		groups[key] = append(groups[key], id)
+	}
+	return groups
+}
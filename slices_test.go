@@ -0,0 +1,39 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSliceUtilities(t *testing.T) {
+	genA := NewGenerator().UseMachineID([]byte{1, 2, 3, 4})
+	genB := NewGenerator().UseIPv4(net.ParseIP("10.0.0.1"))
+	now := time.Now()
+	a1 := genA.NewWithTime(now)
+	b1 := genB.NewWithTime(now.Add(time.Second))
+	a2 := genA.NewWithTime(now.Add(-time.Second))
+	ids := []ID{a1, b1, a2, a1, b1}
+
+	if got := Latest(ids); got != b1 {
+		t.Fatalf("Latest result not match, want= %v, got= %v", b1, got)
+	}
+	if got := Earliest(ids); got != a2 {
+		t.Fatalf("Earliest result not match, want= %v, got= %v", a2, got)
+	}
+	if Latest(nil) != zeroID || Earliest(nil) != zeroID {
+		t.Fatalf("expect zero ID for empty input")
+	}
+
+	deduped := Dedup(ids)
+	if len(deduped) != 3 || deduped[0] != a1 || deduped[1] != b1 || deduped[2] != a2 {
+		t.Fatalf("Dedup result not match, want= %v, got= %v", []ID{a1, b1, a2}, deduped)
+	}
+
+	groups := GroupByMachine(ids)
+	ga := groups["4:01020304"]
+	gb := groups["2:0a000001"]
+	if len(groups) != 2 || len(ga) != 3 || len(gb) != 2 || ga[1] != a2 {
+		t.Fatalf("GroupByMachine result not match, got= %v", groups)
+	}
+}
@@ -0,0 +1,202 @@
+package xxid
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// SQLStorage determines the physical form of an ID when it is saved
+// into a database by ID.Value.
+type SQLStorage int32
+
+const (
+	// StoreBase62 saves IDs as strings in base62 form, it is suitable for
+	// text columns, e.g. SQLite TEXT or MySQL CHAR(22).
+	StoreBase62 SQLStorage = 0
+
+	// StoreBinary saves IDs as bytes in binary form, it is suitable for
+	// binary columns, e.g. MySQL BINARY(16) or PostgreSQL BYTEA.
+	StoreBinary SQLStorage = 1
+
+	// StoreShort saves IDs as int64 values returned by ID.Short, it is
+	// suitable for integer columns, e.g. PostgreSQL BIGINT.
+	//
+	// Note that the short form does not retain the machine ID, pid or
+	// port and flag of an ID, when scanning an integer value, these
+	// fields are filled from the default generator.
+	StoreShort SQLStorage = 2
+)
+
+var sqlStorage int32
+
+// SetSQLStorage changes the physical form of IDs saved into databases,
+// it affects all IDs in the process. The default is StoreBase62.
+//
+// Scan accepts all the forms regardless of this setting.
+func SetSQLStorage(s SQLStorage) {
+	if s < StoreBase62 || s > StoreShort {
+		panic(fmt.Sprintf("xxid: unknown SQL storage %d", s))
+	}
+	atomic.StoreInt32(&sqlStorage, int32(s))
+}
+
+// CurrentSQLStorage returns the physical form of IDs saved into
+// databases, which is set by SetSQLStorage.
+func CurrentSQLStorage() SQLStorage {
+	return SQLStorage(atomic.LoadInt32(&sqlStorage))
+}
+
+// ColumnDDL returns the recommended column type for IDs with the given
+// machine ID type, in the physical form set by SetSQLStorage, dialect
+// may be "mysql", "postgres" or "sqlite". The lengths come from the
+// same tables used by the encoders, e.g. "BINARY(16)", "BYTEA" or
+// "CHAR(22) CHARACTER SET ascii COLLATE ascii_bin".
+//
+// Base62 columns use a binary collation, so that the database compares
+// and sorts them in the same order as ID.Base62.
+func ColumnDDL(dialect string, mtype MachineIDType) (string, error) {
+	return columnDDL(dialect, mtype, false)
+}
+
+// CompactColumnDDL is like ColumnDDL, but returns the column type for
+// IDs in the compact layout, see Generator.UseCompactLayout, e.g.
+// "BINARY(14)" or "CHAR(19) CHARACTER SET ascii COLLATE ascii_bin".
+//
+// IDs in the compact layout must not be saved into fixed-length columns
+// returned by ColumnDDL, which pad them to the length of the full layout.
+func CompactColumnDDL(dialect string, mtype MachineIDType) (string, error) {
+	return columnDDL(dialect, mtype, true)
+}
+
+func columnDDL(dialect string, mtype MachineIDType, compact bool) (string, error) {
+	if mtype > maxMachineIDType {
+		return "", errUnknownMachineIDType
+	}
+	storage := SQLStorage(atomic.LoadInt32(&sqlStorage))
+	binLen := strconv.Itoa(binEncodedLength[mtype])
+	b62Len := strconv.Itoa(b62EncodedLength[mtype])
+	if compact {
+		binLen = strconv.Itoa(compactBinLength[mtype])
+		b62Len = strconv.Itoa(compactB62Length[mtype])
+	}
+	switch dialect {
+	case "mysql":
+		switch storage {
+		case StoreBinary:
+			return "BINARY(" + binLen + ")", nil
+		case StoreShort:
+			return "BIGINT", nil
+		}
+		return "CHAR(" + b62Len + ") CHARACTER SET ascii COLLATE ascii_bin", nil
+	case "postgres":
+		switch storage {
+		case StoreBinary:
+			return "BYTEA", nil
+		case StoreShort:
+			return "BIGINT", nil
+		}
+		return "CHAR(" + b62Len + `) COLLATE "C"`, nil
+	case "sqlite":
+		switch storage {
+		case StoreBinary:
+			return "BLOB", nil
+		case StoreShort:
+			return "INTEGER", nil
+		}
+		return "TEXT", nil
+	}
+	return "", fmt.Errorf("xxid: unknown SQL dialect %q", dialect)
+}
+
+// Value implements the driver.Valuer interface.
+func (id ID) Value() (driver.Value, error) {
+	switch SQLStorage(atomic.LoadInt32(&sqlStorage)) {
+	case StoreBinary:
+		return id.Binary(), nil
+	case StoreShort:
+		return id.Short(), nil
+	}
+	return string(id.Base62()), nil
+}
+
+// Scan implements the sql.Scanner interface.
+//
+// It accepts an ID in binary form, base62 form or string form, and
+// an int64 value returned by ID.Short.
+// IDs saved in UUID columns (e.g. PostgreSQL uuid) are also accepted,
+// drivers may return them as raw 16 bytes or hyphenated UUID text.
+// A nil value scans into the zero ID.
+func (id *ID) Scan(value interface{}) (err error) {
+	var tmp ID
+	switch val := value.(type) {
+	case nil:
+		tmp = zeroID
+	case string:
+		tmp, err = scanText(val)
+	case []byte:
+		switch len(val) {
+		case 14, 16, 18, 20, 26, 28: // binary form
+			tmp, err = decodeBinary(val)
+		default:
+			tmp, err = scanText(b2s(val))
+		}
+	case [16]byte:
+		tmp, err = decodeBinary(val[:])
+	case int64:
+		tmp = fromShort(defaultGenerator.config(), val)
+	default:
+		return errUnsupportedType(value)
+	}
+	if err != nil {
+		return err
+	}
+	*id = tmp
+	return nil
+}
+
+func scanText(s string) (ID, error) {
+	if len(s) == uuidEncodedLen {
+		return ParseUUID(s)
+	}
+	return parseText(s2b(s))
+}
+
+// parseText parses an ID from its base62 form or string form.
+//
+// Both forms may be 38 characters, the string form consists of only
+// digits and lowercase hex characters, which is practically impossible
+// for the base62 form, thus such input is parsed as string form.
+func parseText(src []byte) (ID, error) {
+	if len(src) >= minCompactStringLen && isHexDigits(src) {
+		return ParseString(b2s(src))
+	}
+	return ParseBase62(src)
+}
+
+func isHexDigits(src []byte) bool {
+	for _, c := range src {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// fromShort rebuilds an ID from a value returned by ID.Short, other
+// fields are filled from gen, the flag is not randomized.
+func fromShort(gen *genConfig, short int64) ID {
+	id := ID{
+		timeMsec:  short >> 16,
+		pidOrPort: gen.pidOrPort,
+		counter:   uint16(short),
+		flag:      gen.flag,
+		mIDType:   gen.mIDType,
+		machineID: gen.machineID,
+	}
+	if gen.compact {
+		id = id.Compact()
+	}
+	return id
+}
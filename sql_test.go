@@ -0,0 +1,109 @@
+package xxid
+
+import (
+	"net"
+	"testing"
+)
+
+func TestID_ValueScan(t *testing.T) {
+	defer SetSQLStorage(StoreBase62)
+
+	ids := []ID{
+		New(),
+		NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}).New(),
+		NewGenerator().UseIPv6(net.ParseIP("::1")).New(),
+	}
+	for _, storage := range []SQLStorage{StoreBase62, StoreBinary} {
+		SetSQLStorage(storage)
+		for _, id := range ids {
+			value, err := id.Value()
+			if err != nil {
+				t.Fatalf("failed get value, storage= %v, err= %v", storage, err)
+			}
+			var got ID
+			if err = got.Scan(value); err != nil {
+				t.Fatalf("failed scan value, storage= %v, err= %v", storage, err)
+			}
+			if got != id {
+				t.Fatalf("scan result not match, storage= %v, src= %v, got= %v", storage, id, got)
+			}
+		}
+	}
+
+	SetSQLStorage(StoreShort)
+	id := New()
+	value, _ := id.Value()
+	if value != id.Short() {
+		t.Fatalf("short storage value not match, got= %v", value)
+	}
+	var got ID
+	if err := got.Scan(value); err != nil || got.Short() != id.Short() {
+		t.Fatalf("failed scan short value, err= %v", err)
+	}
+
+	for _, src := range []interface{}{id.String(), []byte(id.String())} {
+		got = ID{}
+		if err := got.Scan(src); err != nil || got != id {
+			t.Fatalf("failed scan string form %v, err= %v", src, err)
+		}
+	}
+
+	uuidBin := id.Binary()
+	var uuidArr [16]byte
+	copy(uuidArr[:], uuidBin)
+	for _, src := range []interface{}{id.UUID(), []byte(id.UUID()), uuidBin, uuidArr} {
+		got = ID{}
+		if err := got.Scan(src); err != nil || got != id {
+			t.Fatalf("failed scan UUID value %v, err= %v", src, err)
+		}
+	}
+
+	got = id
+	if err := got.Scan(nil); err != nil || got != zeroID {
+		t.Fatalf("failed scan nil value, err= %v", err)
+	}
+	if err := got.Scan(1.5); err == nil {
+		t.Fatalf("expect error for unsupported type")
+	}
+}
+
+func TestColumnDDL(t *testing.T) {
+	defer SetSQLStorage(StoreBase62)
+
+	cases := []struct {
+		storage SQLStorage
+		dialect string
+		mtype   MachineIDType
+		want    string
+	}{
+		{StoreBase62, "mysql", HostID, "CHAR(22) CHARACTER SET ascii COLLATE ascii_bin"},
+		{StoreBase62, "postgres", Specified8, `CHAR(27) COLLATE "C"`},
+		{StoreBase62, "sqlite", IPv6, "TEXT"},
+		{StoreBinary, "mysql", IPv6, "BINARY(28)"},
+		{StoreBinary, "postgres", HostID, "BYTEA"},
+		{StoreShort, "mysql", HostID, "BIGINT"},
+		{StoreShort, "sqlite", HostID, "INTEGER"},
+	}
+	for _, c := range cases {
+		SetSQLStorage(c.storage)
+		got, err := ColumnDDL(c.dialect, c.mtype)
+		if err != nil || got != c.want {
+			t.Fatalf("ColumnDDL(%v, %v) not match, want= %v, got= %v, err= %v",
+				c.dialect, c.mtype, c.want, got, err)
+		}
+	}
+	SetSQLStorage(StoreBinary)
+	if got, _ := CompactColumnDDL("mysql", HostID); got != "BINARY(14)" {
+		t.Fatalf("CompactColumnDDL not match, want= BINARY(14), got= %v", got)
+	}
+	SetSQLStorage(StoreBase62)
+	if got, _ := CompactColumnDDL("postgres", Specified8); got != `CHAR(25) COLLATE "C"` {
+		t.Fatalf(`CompactColumnDDL not match, want= CHAR(25) COLLATE "C", got= %v`, got)
+	}
+	if _, err := ColumnDDL("oracle", HostID); err == nil {
+		t.Fatalf("expect error for unknown dialect")
+	}
+	if _, err := ColumnDDL("mysql", maxMachineIDType+1); err == nil {
+		t.Fatalf("expect error for unknown machine ID type")
+	}
+}
@@ -0,0 +1,111 @@
+package xxid
+
+import (
+	"sync"
+	"time"
+)
+
+// maxTimeBuckets is the max number of distinct milliseconds remembered
+// by a time guard, the oldest bucket is evicted when it is exceeded.
+const maxTimeBuckets = 1 << 14
+
+// timeGuard protects IDs generated with user-specified times from
+// duplicates, for each distinct millisecond, it remembers the range of
+// counter values issued, which is [first, first+span].
+type timeGuard struct {
+	mu      sync.Mutex
+	buckets map[int64]*timeBucket
+	order   []int64 // ring of bucket keys in insertion order
+	next    int
+}
+
+type timeBucket struct {
+	first uint16
+	span  uint16
+}
+
+func newTimeGuard() *timeGuard {
+	return &timeGuard{buckets: make(map[int64]*timeBucket)}
+}
+
+// issue returns a time and counter which is not issued before, the
+// counter c is used if it's not in the issued range of the millisecond,
+// else the one following the range is used. If all counter values of
+// the millisecond are exhausted, it moves to the next millisecond.
+func (g *timeGuard) issue(timeMsec int64, c uint16) (int64, uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		b := g.buckets[timeMsec]
+		if b == nil {
+			g.add(timeMsec, &timeBucket{first: c})
+			return timeMsec, c
+		}
+		if b.span == 0xffff {
+			timeMsec++
+			continue
+		}
+		if offset := c - b.first; offset > b.span {
+			b.span = offset
+			return timeMsec, c
+		}
+		b.span++
+		return timeMsec, b.first + b.span
+	}
+}
+
+func (g *timeGuard) add(timeMsec int64, b *timeBucket) {
+	if len(g.order) < maxTimeBuckets {
+		g.order = append(g.order, timeMsec)
+	} else {
+		delete(g.buckets, g.order[g.next])
+		g.order[g.next] = timeMsec
+		g.next = (g.next + 1) % maxTimeBuckets
+	}
+	g.buckets[timeMsec] = b
+}
+
+// UseTimeGuard makes NewWithTime route user-specified times through a
+// duplicate guard, for each distinct millisecond, the range of counter
+// values issued is remembered, and a counter value which would collide
+// is moved forward after the range. If all 65536 counter values of a
+// millisecond are used, the ID is moved to the next millisecond.
+//
+// Without the guard, repeated calls of NewWithTime with the same time
+// may generate duplicate IDs after the counter wraps around, which is
+// the fast behavior of NewWithTimeUnsafe. The guard remembers the most
+// recent 16384 distinct milliseconds, the protection is lost for
+// milliseconds evicted.
+//
+// Unlike NewAt, IDs generated with different times don't affect each
+// other, thus backfills of out-of-order times keep their timestamps.
+func (g *Generator) UseTimeGuard() *Generator {
+	if g.timeGuard == nil {
+		g.timeGuard = newTimeGuard()
+	}
+	return g
+}
+
+// WithTimeGuard is the counterpart of Generator.UseTimeGuard.
+func WithTimeGuard() Option {
+	return func(g *Generator) error {
+		g.UseTimeGuard()
+		return nil
+	}
+}
+
+// NewWithTimeUnsafe generates an ID with the given time without the
+// duplicate guard, see Generator.NewWithTimeUnsafe.
+func NewWithTimeUnsafe(t time.Time) ID {
+	return defaultGenerator.NewWithTimeUnsafe(t)
+}
+
+// NewWithTimeUnsafe generates an ID with the given time, the counter is
+// incremented from the generator's counter, repeated calls with the
+// same time may generate duplicate IDs after the counter wraps around.
+// It is the fastest way to generate IDs with user-specified times.
+func (g *Generator) NewWithTimeUnsafe(t time.Time) ID {
+	timeMsec := t.UnixNano() / 1e6
+	incr := g.seq.incrCounter()
+	return newID(g.config(), timeMsec, incr)
+}
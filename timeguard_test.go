@@ -0,0 +1,58 @@
+package xxid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerator_UseTimeGuard(t *testing.T) {
+	now := time.Now()
+	gen := NewGenerator().UseSequenceMode(IsolatedSequence).UseTimeGuard()
+	seen := make(map[int64]bool)
+	for i := 0; i < 70000; i++ {
+		id := gen.NewWithTime(now)
+		if seen[id.Short()] {
+			t.Fatalf("duplicate ID at %d: %v", i, id)
+		}
+		seen[id.Short()] = true
+		if i == 65535 && !id.Time().Equal(now.Truncate(time.Millisecond)) {
+			t.Fatalf("ID moved before the millisecond is exhausted")
+		}
+		if i == 65536 && !id.Time().Equal(now.Truncate(time.Millisecond).Add(time.Millisecond)) {
+			t.Fatalf("ID not moved to next millisecond when exhausted")
+		}
+	}
+
+	// interleaved times keep their timestamps
+	past := now.Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		if id := gen.NewWithTime(past); !id.Time().Equal(past.Truncate(time.Millisecond)) {
+			t.Fatalf("ID with past time moved: %v", id.Time())
+		}
+	}
+
+	unsafeGen := NewGenerator().UseSequenceMode(IsolatedSequence)
+	first := unsafeGen.NewWithTimeUnsafe(now)
+	for i := 0; i < 65535; i++ {
+		unsafeGen.NewWithTimeUnsafe(now)
+	}
+	if dup := unsafeGen.NewWithTime(now); dup.Short() != first.Short() {
+		t.Fatalf("expect duplicate short value without guard")
+	}
+}
+
+func TestTimeGuardEviction(t *testing.T) {
+	g := newTimeGuard()
+	for i := int64(0); i < maxTimeBuckets+10; i++ {
+		g.issue(i, 0)
+	}
+	if len(g.buckets) != maxTimeBuckets {
+		t.Fatalf("buckets not bounded, got %d", len(g.buckets))
+	}
+	if _, ok := g.buckets[0]; ok {
+		t.Fatalf("oldest bucket not evicted")
+	}
+	if tm, c := g.issue(maxTimeBuckets+9, 0); tm != maxTimeBuckets+9 || c != 1 {
+		t.Fatalf("issue = %v, %v", tm, c)
+	}
+}
@@ -0,0 +1,214 @@
+// Package ulidcompat exposes an API matching github.com/oklog/ulid/v2,
+// backed by xxid, so codebases can switch imports with minimal diffs
+// when migrating from ULID to xxid, e.g.
+//
+//	import ulid "github.com/jxskiss/xxid/v2/ulidcompat"
+//
+//	id := ulid.MustNew(ulid.Timestamp(time.Now()), entropy)
+//
+// Note that only the API is compatible, the IDs are xxid IDs, their
+// string form is the base62 form of xxid instead of Crockford's base32,
+// existing ULID values can be converted by xxidmigrate.FromULID.
+// The entropy sources are accepted but not used, the uniqueness and
+// monotonicity are ensured by xxid's machine ID and counter.
+package ulidcompat
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+var errNotULIDRepresentable = errors.New("ulidcompat: ID can not be represented as an ULID")
+
+// ULID is the 16 bytes binary form of an xxid ID, with methods matching
+// oklog/ulid.ULID. Only IDs whose binary form is 16 bytes can be
+// represented as ULID, see xxid.ID.UUID.
+type ULID [16]byte
+
+// Zero is the zero value of ULID.
+var Zero ULID
+
+// New returns an ID with the given Unix milliseconds timestamp,
+// entropy is ignored. It returns an error if the ID generated by the
+// default generator can not be represented as ULID.
+func New(ms uint64, entropy io.Reader) (ULID, error) {
+	return FromID(xxid.NewWithTime(Time(ms)))
+}
+
+// MustNew is like New but panics on failure.
+func MustNew(ms uint64, entropy io.Reader) ULID {
+	id, err := New(ms, entropy)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Make returns an ID with the current time, it panics if the ID
+// generated by the default generator can not be represented as ULID.
+func Make() ULID {
+	return MustNew(Now(), nil)
+}
+
+// Monotonic returns entropy as is, IDs are already monotonic within the
+// same millisecond by xxid's counter.
+func Monotonic(entropy io.Reader, inc uint64) io.Reader {
+	return entropy
+}
+
+// DefaultEntropy returns nil, the entropy is not used by New.
+func DefaultEntropy() io.Reader {
+	return nil
+}
+
+// Parse parses an ID from its base62 form, string form or UUID text.
+func Parse(s string) (ULID, error) {
+	id, err := xxid.Parse(s)
+	if err != nil {
+		return Zero, err
+	}
+	return FromID(id)
+}
+
+// ParseStrict is the same as Parse.
+func ParseStrict(s string) (ULID, error) {
+	return Parse(s)
+}
+
+// MustParse is like Parse but panics on failure.
+func MustParse(s string) ULID {
+	id, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Now returns the current time as Unix milliseconds.
+func Now() uint64 {
+	return Timestamp(time.Now())
+}
+
+// Timestamp converts t to Unix milliseconds.
+func Timestamp(t time.Time) uint64 {
+	return uint64(t.UnixNano() / 1e6)
+}
+
+// Time converts Unix milliseconds to time.Time.
+func Time(ms uint64) time.Time {
+	return time.Unix(0, int64(ms)*1e6)
+}
+
+// FromID converts an xxid ID to ULID, it returns an error if the binary
+// form of id is not 16 bytes. The zero ID converts to Zero.
+func FromID(id xxid.ID) (ULID, error) {
+	var u ULID
+	if id.IsZero() {
+		return u, nil
+	}
+	bin := id.Binary()
+	if len(bin) != len(u) {
+		return u, errNotULIDRepresentable
+	}
+	copy(u[:], bin)
+	return u, nil
+}
+
+// ID returns the underlying xxid ID, Zero and invalid values return
+// the zero ID.
+func (u ULID) ID() xxid.ID {
+	if u == Zero {
+		return xxid.ID{}
+	}
+	id, _ := xxid.ParseBinary(u[:])
+	return id
+}
+
+// String returns the base62 form of the ID.
+func (u ULID) String() string {
+	return string(u.ID().Base62())
+}
+
+// Bytes returns the binary form of the ID.
+func (u ULID) Bytes() []byte {
+	return u[:]
+}
+
+// Time returns the time of the ID as Unix milliseconds.
+func (u ULID) Time() uint64 {
+	return Timestamp(u.ID().Time())
+}
+
+// Timestamp returns the time of the ID.
+func (u ULID) Timestamp() time.Time {
+	return u.ID().Time()
+}
+
+// IsZero reports whether the ID is the zero value.
+func (u ULID) IsZero() bool {
+	return u == Zero
+}
+
+// Compare returns -1, 0 or 1 by comparing the binary forms, which is
+// the same order as xxid.CompareIDs.
+func (u ULID) Compare(other ULID) int {
+	return bytes.Compare(u[:], other[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (u ULID) MarshalText() ([]byte, error) {
+	return u.ID().MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (u *ULID) UnmarshalText(b []byte) error {
+	var id xxid.ID
+	if err := id.UnmarshalText(b); err != nil {
+		return err
+	}
+	return u.setID(id)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (u ULID) MarshalBinary() ([]byte, error) {
+	return u.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (u *ULID) UnmarshalBinary(b []byte) error {
+	id, err := xxid.ParseBinary(b)
+	if err != nil {
+		return err
+	}
+	return u.setID(id)
+}
+
+// Value implements the driver.Valuer interface, the ID is stored in the
+// form configured by xxid.SetSQLStorage.
+func (u ULID) Value() (driver.Value, error) {
+	return u.ID().Value()
+}
+
+// Scan implements the sql.Scanner interface, it accepts the values
+// accepted by xxid.ID.Scan. A nil value scans into Zero.
+func (u *ULID) Scan(value interface{}) error {
+	var id xxid.ID
+	if err := id.Scan(value); err != nil {
+		return err
+	}
+	return u.setID(id)
+}
+
+func (u *ULID) setID(id xxid.ID) error {
+	x, err := FromID(id)
+	if err != nil {
+		return err
+	}
+	*u = x
+	return nil
+}
@@ -0,0 +1,76 @@
+package ulidcompat
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestULID(t *testing.T) {
+	now := time.Now()
+	entropy := Monotonic(rand.Reader, 0)
+	id := MustNew(Timestamp(now), entropy)
+	if id.Time() != Timestamp(now) || !id.Timestamp().Equal(Time(Timestamp(now))) {
+		t.Fatalf("time not match")
+	}
+
+	parsed, err := Parse(id.String())
+	if err != nil || parsed != id {
+		t.Fatalf("failed parse, err= %v", err)
+	}
+	next := Make()
+	if id.Compare(next) >= 0 || next.Compare(id) <= 0 || id.Compare(id) != 0 {
+		t.Fatalf("compare result not match")
+	}
+
+	var u ULID
+	text, _ := id.MarshalText()
+	if err := u.UnmarshalText(text); err != nil || u != id {
+		t.Fatalf("text round trip not match, err= %v", err)
+	}
+	bin, _ := id.MarshalBinary()
+	if err := u.UnmarshalBinary(bin); err != nil || u != id {
+		t.Fatalf("binary round trip not match, err= %v", err)
+	}
+	if got, err := FromID(id.ID()); err != nil || got != id || !bytes.Equal(id.Bytes(), id.ID().Binary()) {
+		t.Fatalf("xxid ID round trip not match, err= %v", err)
+	}
+	if _, err := Parse("invalid"); err == nil {
+		t.Fatalf("expect error for invalid input")
+	}
+}
+
+func TestULID_SQL(t *testing.T) {
+	id := Make()
+	v, err := id.Value()
+	if err != nil {
+		t.Fatalf("failed get value, err= %v", err)
+	}
+	var u ULID
+	if err = u.Scan(v); err != nil || u != id {
+		t.Fatalf("value round trip not match, want= %v, got= %v, err= %v", id, u, err)
+	}
+	if err = u.Scan(id.Bytes()); err != nil || u != id {
+		t.Fatalf("scan binary not match, want= %v, got= %v, err= %v", id, u, err)
+	}
+	if err = u.Scan(nil); err != nil || u != Zero || !u.IsZero() {
+		t.Fatalf("scan nil not match, want= %v, got= %v, err= %v", Zero, u, err)
+	}
+
+	wide := xxid.NewGenerator().UseMachineID([]byte("12345678")).New()
+	if err = u.Scan(string(wide.Base62())); err == nil {
+		t.Fatalf("expect error for ID which can not be represented as ULID")
+	}
+	if _, err = FromID(wide); err == nil {
+		t.Fatalf("expect error for ID which can not be represented as ULID")
+	}
+	if got, err := FromID(xxid.ID{}); err != nil || got != Zero {
+		t.Fatalf("zero ID not match, want= %v, got= %v, err= %v", Zero, got, err)
+	}
+	if !Zero.ID().IsZero() {
+		t.Fatalf("ID of Zero should be zero")
+	}
+}
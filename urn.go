@@ -0,0 +1,24 @@
+package xxid
+
+import "strings"
+
+const urnPrefix = "urn:xxid:"
+
+// URN returns the URN representation of the ID, which is
+// "urn:xxid:" followed by the ID's base62 form.
+func (id ID) URN() string {
+	out := make([]byte, len(urnPrefix)+id.base62Len())
+	copy(out, urnPrefix)
+	id.putBase62(out[len(urnPrefix):])
+	return b2s(out)
+}
+
+// ParseURN parses an ID from its URN representation returned by ID.URN.
+// The "urn:xxid:" prefix is matched case-insensitively as required by
+// RFC 8141.
+func ParseURN(urn string) (ID, error) {
+	if len(urn) < len(urnPrefix) || !strings.EqualFold(urn[:len(urnPrefix)], urnPrefix) {
+		return zeroID, errInvalidURN
+	}
+	return ParseBase62(s2b(urn[len(urnPrefix):]))
+}
@@ -0,0 +1,25 @@
+package xxid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIDURN(t *testing.T) {
+	id := New()
+	urn := id.URN()
+	if want := "urn:xxid:" + string(id.Base62()); urn != want {
+		t.Fatalf("URN not match, want= %v, got= %v", want, urn)
+	}
+	for _, s := range []string{urn, strings.Replace(urn, "urn:xxid:", "URN:XXID:", 1)} {
+		got, err := ParseURN(s)
+		if err != nil || got != id {
+			t.Fatalf("failed parse URN %v, err= %v", s, err)
+		}
+	}
+	for _, s := range []string{"", "urn:xxid", "urn:uuid:" + string(id.Base62())} {
+		if _, err := ParseURN(s); err == nil {
+			t.Fatalf("expect error for invalid URN %q", s)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package xxid
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+const uuidEncodedLen = 36
+
+var errNotUUIDRepresentable = errors.New("xxid: ID can not be represented as an UUID")
+
+// UUID formats the ID's binary form as a hyphenated UUID text, e.g.
+// "0d3c2a51-a5f8-c801-0a09-08072694800c" (8-4-4-4-12 hex digits).
+//
+// Only IDs whose binary form is 16 bytes (machine ID type Random, HostID,
+// IPv4, Specified4 and HashedIPv4, in the full layout) can be
+// represented as an UUID, for other machine ID types and IDs in the
+// compact layout it returns "".
+// Note that the version and variant bits are not set, the returned
+// string is not a RFC 4122 UUID, it only shares the same shape.
+func (id ID) UUID() string {
+	if id.binaryLen() != 16 {
+		return ""
+	}
+	buf := id.encodeBinary()
+	out := make([]byte, uuidEncodedLen)
+	encodeUUID(out, buf)
+	return b2s(out)
+}
+
+// ParseUUID parses an ID from the hyphenated UUID text produced by UUID.
+func ParseUUID(str string) (ID, error) {
+	if len(str) != uuidEncodedLen {
+		return zeroID, errInvalidUUIDRepr
+	}
+	var buf [16]byte
+	if err := decodeUUID(buf[:], str); err != nil {
+		return zeroID, err
+	}
+	return decodeBinary(buf[:])
+}
+
+func encodeUUID(dst, src []byte) {
+	hex.Encode(dst[0:8], src[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], src[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], src[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], src[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:36], src[10:16])
+}
+
+func decodeUUID(dst []byte, str string) error {
+	if str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+		return errInvalidUUIDRepr
+	}
+	var tmp [32]byte
+	copy(tmp[0:8], str[0:8])
+	copy(tmp[8:12], str[9:13])
+	copy(tmp[12:16], str[14:18])
+	copy(tmp[16:20], str[19:23])
+	copy(tmp[20:32], str[24:36])
+	if _, err := hex.Decode(dst, tmp[:]); err != nil {
+		return errInvalidUUIDRepr
+	}
+	return nil
+}
+
+// ToUUIDBytes converts ids to 16-byte arrays of their binary forms in
+// a single allocation, it is intended to hand batches of IDs to drivers
+// which expect UUID byte arrays, e.g. pgx CopyFrom and Cassandra batches.
+//
+// It returns an error on the first ID which can not be represented as
+// an UUID (see ID.UUID), i.e. its binary form is not 16 bytes or it is
+// in the compact layout.
+func ToUUIDBytes(ids []ID) ([][16]byte, error) {
+	out := make([][16]byte, len(ids))
+	for i := range ids {
+		if ids[i].compact || ids[i].binaryLen() != 16 {
+			return nil, errNotUUIDRepresentable
+		}
+		ids[i].putBinary(out[i][:])
+	}
+	return out, nil
+}
+
+// FromUUIDBytes converts 16-byte arrays returned by ToUUIDBytes back to
+// IDs in a single allocation.
+func FromUUIDBytes(src [][16]byte) ([]ID, error) {
+	out := make([]ID, len(src))
+	for i := range src {
+		id, err := decodeBinary(src[i][:])
+		if err != nil {
+			return nil, err
+		}
+		out[i] = id
+	}
+	return out, nil
+}
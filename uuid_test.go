@@ -0,0 +1,74 @@
+package xxid
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+func TestIDUUID(t *testing.T) {
+	id := New()
+	encoded := id.UUID()
+	if len(encoded) != 36 {
+		t.Fatalf("UUID length not match, got= %v", encoded)
+	}
+	got, err := ParseUUID(encoded)
+	if err != nil {
+		t.Fatalf("failed parse ID from UUID representation, err= %v", err)
+	}
+	if got != id {
+		t.Fatalf("ParseUUID result not match, src= %v, got= %v", id, got)
+	}
+
+	id = NewGenerator().UseIPv6(net.ParseIP("::1")).New()
+	if got := id.UUID(); got != "" {
+		t.Fatalf("IPv6 ID should not be represented as UUID, got= %v", got)
+	}
+
+	for _, bad := range []string{
+		"",
+		"0d3c2a51a5f8c8010a0908072694800c",
+		"0d3c2a51-a5f8-c801-0a09_08072694800c",
+		"zd3c2a51-a5f8-c801-0a09-08072694800c",
+	} {
+		if _, err := ParseUUID(bad); err == nil {
+			t.Fatalf("expect error for invalid UUID %q", bad)
+		}
+	}
+}
+
+func TestToUUIDBytes(t *testing.T) {
+	ids := []ID{New(), New(), NewGenerator().UseMachineID([]byte("12345678")).New()}
+	if _, err := ToUUIDBytes(ids); err == nil {
+		t.Fatalf("expect error for ID which can not be represented as UUID")
+	}
+	arrs, err := ToUUIDBytes(ids[:2])
+	if err != nil || !bytes.Equal(arrs[0][:], ids[0].Binary()) {
+		t.Fatalf("UUID bytes not match, err= %v", err)
+	}
+	got, err := FromUUIDBytes(arrs)
+	if err != nil || got[0] != ids[0] || got[1] != ids[1] {
+		t.Fatalf("failed convert UUID bytes back, err= %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		arrs, _ := ToUUIDBytes(ids[:2])
+		FromUUIDBytes(arrs)
+	})
+	if allocs > 2 {
+		t.Fatalf("expect 2 allocations, got %v", allocs)
+	}
+}
+
+func TestIDUUID_Compact(t *testing.T) {
+	id := NewGenerator().UseCompactLayout().New()
+	if got := id.UUID(); got != "" {
+		t.Fatalf("UUID of compact ID should be empty, got= %v", got)
+	}
+	if NewGenerator().UseCompactLayout().EncodedLen(EncodingUUID) != 0 {
+		t.Fatalf("EncodedLen of UUID should be 0 in the compact layout")
+	}
+	if _, err := ToUUIDBytes([]ID{id}); err == nil {
+		t.Fatalf("expect error for compact ID")
+	}
+}
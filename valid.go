@@ -0,0 +1,96 @@
+package xxid
+
+import "errors"
+
+var errZeroID = errors.New("xxid: ID is zero")
+
+// Valid is an ID which has passed validation, it can only be obtained
+// from Validate, ParseValid or by unmarshaling, which validate the ID.
+//
+// Code behind an API boundary can require Valid in signatures to skip
+// re-validating IDs that already passed the boundary check. The zero
+// value of Valid holds the zero ID, which is not valid, check IsZero
+// when a Valid may be absent, e.g. an optional field.
+type Valid struct {
+	id ID
+}
+
+// Validate checks that id is not zero and its machine ID is consistent
+// with its type, see ID.ValidateMachineID, and returns it as a Valid.
+func Validate(id ID) (Valid, error) {
+	if id.IsZero() {
+		return Valid{}, errZeroID
+	}
+	if err := id.ValidateMachineID(); err != nil {
+		return Valid{}, err
+	}
+	return Valid{id: id}, nil
+}
+
+// ParseValid parses an ID like Parse and validates it like Validate.
+func ParseValid(s string) (Valid, error) {
+	id, err := Parse(s)
+	if err != nil {
+		return Valid{}, err
+	}
+	return Validate(id)
+}
+
+// ID returns the validated ID.
+func (v Valid) ID() ID {
+	return v.id
+}
+
+// IsZero reports whether v holds no ID.
+func (v Valid) IsZero() bool {
+	return v.id.IsZero()
+}
+
+// String returns the ID's string form, see ID.String.
+func (v Valid) String() string {
+	return v.id.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface, see ID.MarshalJSON.
+func (v Valid) MarshalJSON() ([]byte, error) {
+	return v.id.MarshalJSON()
+}
+
+// UnmarshalJSON decodes and validates an ID, it implements the
+// json.Unmarshaler interface. JSON null leaves v unchanged.
+func (v *Valid) UnmarshalJSON(buf []byte) error {
+	if string(buf) == "null" {
+		return nil
+	}
+	id, err := UnmarshalJSONBytes(buf)
+	if err != nil {
+		return err
+	}
+	tmp, err := Validate(id)
+	if err != nil {
+		return err
+	}
+	*v = tmp
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, see
+// ID.MarshalText.
+func (v Valid) MarshalText() ([]byte, error) {
+	return v.id.MarshalText()
+}
+
+// UnmarshalText decodes and validates an ID from its base62 form, it
+// implements the encoding.TextUnmarshaler interface.
+func (v *Valid) UnmarshalText(buf []byte) error {
+	id, err := ParseBase62(buf)
+	if err != nil {
+		return err
+	}
+	tmp, err := Validate(id)
+	if err != nil {
+		return err
+	}
+	*v = tmp
+	return nil
+}
@@ -0,0 +1,59 @@
+package xxid
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+func TestValid(t *testing.T) {
+	id := New()
+	v, err := Validate(id)
+	if err != nil || v.ID() != id || v.IsZero() {
+		t.Fatalf("Validate = %v, %v", v, err)
+	}
+	if v.String() != id.String() {
+		t.Fatalf("String not match")
+	}
+	if _, err := Validate(ID{}); err == nil {
+		t.Fatalf("expect error for zero ID")
+	}
+	broadcast := NewGenerator().UseIPv4(net.IPv4bcast).New()
+	if _, err := Validate(broadcast); err == nil {
+		t.Fatalf("expect error for broadcast IPv4 machine ID")
+	}
+
+	for _, s := range []string{string(id.Base62()), id.String()} {
+		if v, err := ParseValid(s); err != nil || v.ID() != id {
+			t.Fatalf("ParseValid(%q) = %v, %v", s, v, err)
+		}
+	}
+	if _, err := ParseValid("garbage"); err == nil {
+		t.Fatalf("expect error for garbage")
+	}
+
+	var payload struct {
+		ID  Valid  `json:"id"`
+		Opt *Valid `json:"opt"`
+	}
+	buf, _ := json.Marshal(map[string]interface{}{"id": id, "opt": nil})
+	if err := json.Unmarshal(buf, &payload); err != nil || payload.ID.ID() != id || payload.Opt != nil {
+		t.Fatalf("unmarshal failed, err= %v", err)
+	}
+	out, _ := json.Marshal(payload.ID)
+	if string(out) != `"`+string(id.Base62())+`"` {
+		t.Fatalf("MarshalJSON = %s", out)
+	}
+	buf, _ = json.Marshal(map[string]interface{}{"id": broadcast})
+	if err := json.Unmarshal(buf, &payload); err == nil {
+		t.Fatalf("expect error for invalid ID in JSON")
+	}
+
+	var tv Valid
+	if err := tv.UnmarshalText(id.Base62()); err != nil || tv.ID() != id {
+		t.Fatalf("UnmarshalText failed, err= %v", err)
+	}
+	if err := tv.UnmarshalText([]byte(MinBase62)); err == nil {
+		t.Fatalf("expect error for zero ID text")
+	}
+}
@@ -0,0 +1,52 @@
+package xxid
+
+import (
+	"errors"
+	"strings"
+)
+
+var errVanityNotFound = errors.New("xxid: no ID matches the requested suffix")
+
+// NewWithSuffix generates an ID whose base62 form ends with the given
+// suffix, it is intended for marketing-visible identifiers, e.g. coupon
+// codes or share links.
+//
+// The leading characters of the base62 form encode the timestamp, which
+// can not be chosen, thus only the trailing characters are searched.
+// It searches the flag space (15 bits) for at most maxTries values, a
+// suffix of one or two characters can be found in most cases, longer
+// suffixes are unlikely to be found. The found ID reports its flag as
+// not set, an error is returned if the generator is configured with a
+// flag or the compact layout, which has no flag to search, or no
+// matching ID is found.
+func (g *Generator) NewWithSuffix(suffix string, maxTries int) (ID, error) {
+	id := g.New()
+	if id.compact {
+		return zeroID, errors.New("xxid: vanity suffix is unavailable with the compact layout")
+	}
+	if id.flag&flagMask != 0 {
+		return zeroID, errors.New("xxid: vanity suffix is unavailable with user specified flag")
+	}
+	for _, c := range []byte(suffix) {
+		if c >= 128 || dec[c] == 0xff {
+			return zeroID, errInvalidBase62Character(c)
+		}
+	}
+	if maxTries > flagMask {
+		maxTries = flagMask
+	}
+
+	buf := id.encodeBinary()
+	out := make([]byte, b62EncodedLength[id.mIDType])
+	start := id.flag
+	for i := 0; i < maxTries; i++ {
+		flag := (start + uint16(i)) & ^uint16(flagMask)
+		beEnc.PutUint16(buf[len(buf)-2:], flag)
+		encodeBase62(out, buf)
+		if strings.HasSuffix(b2s(out), suffix) {
+			id.flag = flag
+			return id, nil
+		}
+	}
+	return zeroID, errVanityNotFound
+}
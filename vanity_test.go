@@ -0,0 +1,35 @@
+package xxid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerator_NewWithSuffix(t *testing.T) {
+	gen := NewGenerator()
+	for _, suffix := range []string{"", "Z", "ab", "00"} {
+		id, err := gen.NewWithSuffix(suffix, 1<<15)
+		if err != nil {
+			t.Fatalf("failed generate ID with suffix %q, err= %v", suffix, err)
+		}
+		if b62 := string(id.Base62()); !strings.HasSuffix(b62, suffix) {
+			t.Fatalf("ID %v does not end with %q", b62, suffix)
+		}
+		if got, _ := ParseBase62(id.Base62()); got != id {
+			t.Fatalf("failed parse vanity ID")
+		}
+	}
+
+	if _, err := gen.NewWithSuffix("abcdef", 100); err == nil {
+		t.Fatalf("expect error for unreachable suffix")
+	}
+	if _, err := gen.NewWithSuffix("-", 100); err == nil {
+		t.Fatalf("expect error for invalid suffix")
+	}
+	if _, err := NewGenerator().UseFlag(1).NewWithSuffix("a", 100); err == nil {
+		t.Fatalf("expect error for generator with flag")
+	}
+	if _, err := NewGenerator().UseCompactLayout().NewWithSuffix("a", 100); err == nil {
+		t.Fatalf("expect error for generator with compact layout")
+	}
+}
@@ -0,0 +1,106 @@
+package xxid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// TestVector is a canonical tuple of ID fields and the corresponding
+// encodings, it is intended to validate implementations in other
+// languages against this package.
+//
+// MachineID and Binary are hex encoded, UUID is empty if the ID can not
+// be represented as an UUID. String is rendered in UTC, implementations
+// which format the string form in local time zone should convert it.
+type TestVector struct {
+	TimeMsec      int64         `json:"timeMsec"`
+	MachineIDType MachineIDType `json:"machineIDType"`
+	MachineID     string        `json:"machineID"`
+	PidOrPort     uint16        `json:"pidOrPort"`
+	Counter       uint16        `json:"counter"`
+	RawFlag       uint16        `json:"rawFlag"`
+	Flag          uint16        `json:"flag"`
+
+	Binary string `json:"binary"`
+	Base62 string `json:"base62"`
+	String string `json:"string"`
+	UUID   string `json:"uuid"`
+}
+
+// TestVectors returns canonical test vectors for every machine ID type.
+// The returned vectors are stable across releases as long as the wire
+// format does not change.
+func TestVectors() []TestVector {
+	machineIDs := [...][]byte{
+		Random:      {0xde, 0xad, 0xbe, 0xef},
+		HostID:      {0x0a, 0x1b, 0x2c, 0x3d},
+		IPv4:        {10, 9, 8, 7},
+		IPv6:        {0x20, 0x01, 0x0d, 0xb8, 0x85, 0xa3, 0, 0, 0, 0, 0x8a, 0x2e, 0x03, 0x70, 0x73, 0x34},
+		Specified4:  {1, 2, 3, 4},
+		Specified8:  {8, 7, 6, 5, 4, 3, 2, 1},
+		Specified16: {0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		HashedIPv4:  {0x5e, 0x1f, 0x7a, 0x93},
+	}
+	fields := []struct {
+		timeMsec  int64
+		pidOrPort uint16
+		counter   uint16
+		flag      uint16
+	}{
+		{1637371300634, 0x56a1, 0xabe7, 123 | flagMask},
+		{1637371300634, 8888, 0x0000, 0x1abc},
+		{0, 0, 0, 0},
+		{4102444799999, 0xffff, 0xffff, 0xffff},
+	}
+
+	var out []TestVector
+	for typ, mID := range machineIDs {
+		for _, f := range fields {
+			id := ID{
+				timeMsec:  f.timeMsec,
+				pidOrPort: f.pidOrPort,
+				counter:   f.counter,
+				flag:      f.flag,
+				mIDType:   MachineIDType(typ),
+			}
+			copy(id.machineID[:], mID)
+			out = append(out, TestVector{
+				TimeMsec:      id.timeMsec,
+				MachineIDType: id.mIDType,
+				MachineID:     hex.EncodeToString(id.MachineID()),
+				PidOrPort:     id.pidOrPort,
+				Counter:       id.counter,
+				RawFlag:       id.flag,
+				Flag:          id.Flag(),
+				Binary:        hex.EncodeToString(id.Binary()),
+				Base62:        string(id.Base62()),
+				String:        id.formatString(time.UTC),
+				UUID:          id.UUID(),
+			})
+		}
+	}
+	return out
+}
+
+// MarshalJSON implements the json.Marshaler interface, the machine ID
+// type is encoded as number, which keeps the JSON form of vectors stable.
+func (v TestVector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		TimeMsec      int64  `json:"timeMsec"`
+		MachineIDType uint8  `json:"machineIDType"`
+		MachineID     string `json:"machineID"`
+		PidOrPort     uint16 `json:"pidOrPort"`
+		Counter       uint16 `json:"counter"`
+		RawFlag       uint16 `json:"rawFlag"`
+		Flag          uint16 `json:"flag"`
+
+		Binary string `json:"binary"`
+		Base62 string `json:"base62"`
+		String string `json:"string"`
+		UUID   string `json:"uuid"`
+	}{
+		v.TimeMsec, uint8(v.MachineIDType), v.MachineID, v.PidOrPort, v.Counter, v.RawFlag, v.Flag,
+		v.Binary, v.Base62, v.String, v.UUID,
+	})
+}
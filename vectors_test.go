@@ -0,0 +1,95 @@
+package xxid
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestTestVectors(t *testing.T) {
+	vectors := TestVectors()
+	if len(vectors) == 0 {
+		t.Fatalf("no test vectors")
+	}
+	for _, v := range vectors {
+		bin, _ := hex.DecodeString(v.Binary)
+		fromBin, err := ParseBinary(bin)
+		if err != nil {
+			t.Fatalf("failed parse binary %v, err= %v", v.Binary, err)
+		}
+		fromB62, err := ParseBase62([]byte(v.Base62))
+		if err != nil {
+			t.Fatalf("failed parse base62 %v, err= %v", v.Base62, err)
+		}
+		fromStr, err := parseString(v.String, time.UTC)
+		if err != nil {
+			t.Fatalf("failed parse string %v, err= %v", v.String, err)
+		}
+		if fromBin != fromB62 || fromBin != fromStr {
+			t.Fatalf("test vector encodings not match: %+v", v)
+		}
+		if fromBin.timeMsec != v.TimeMsec || fromBin.mIDType != v.MachineIDType ||
+			hex.EncodeToString(fromBin.MachineID()) != v.MachineID ||
+			fromBin.pidOrPort != v.PidOrPort || fromBin.counter != v.Counter ||
+			fromBin.flag != v.RawFlag || fromBin.Flag() != v.Flag {
+			t.Fatalf("test vector fields not match: %+v", v)
+		}
+		if v.UUID != "" {
+			fromUUID, err := ParseUUID(v.UUID)
+			if err != nil || fromUUID != fromBin {
+				t.Fatalf("failed parse UUID %v, err= %v", v.UUID, err)
+			}
+		}
+	}
+}
+
+// TestTestVectors_golden compares the encodings against the committed
+// vectors in testdata, which must not change unless the wire format
+// changes intentionally. The file is generated by "xxid -vectors".
+func TestTestVectors_golden(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("failed read golden vectors, err= %v", err)
+	}
+	var golden []TestVector
+	if err = json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("failed unmarshal golden vectors, err= %v", err)
+	}
+	vectors := TestVectors()
+	if len(vectors) != len(golden) {
+		t.Fatalf("number of vectors not match, want= %v, got= %v", len(golden), len(vectors))
+	}
+	for i, want := range golden {
+		if vectors[i] != want {
+			t.Fatalf("test vector not match, want= %+v, got= %+v", want, vectors[i])
+		}
+
+		id := ID{
+			timeMsec:  want.TimeMsec,
+			pidOrPort: want.PidOrPort,
+			counter:   want.Counter,
+			flag:      want.RawFlag,
+			mIDType:   want.MachineIDType,
+		}
+		mID, _ := hex.DecodeString(want.MachineID)
+		copy(id.machineID[:], mID)
+		if got := hex.EncodeToString(id.Binary()); got != want.Binary {
+			t.Fatalf("binary not match, want= %v, got= %v", want.Binary, got)
+		}
+		if got := string(id.Base62()); got != want.Base62 {
+			t.Fatalf("base62 not match, want= %v, got= %v", want.Base62, got)
+		}
+		if got := id.formatString(time.UTC); got != want.String {
+			t.Fatalf("string not match, want= %v, got= %v", want.String, got)
+		}
+		if got := id.UUID(); got != want.UUID {
+			t.Fatalf("UUID not match, want= %v, got= %v", want.UUID, got)
+		}
+		fromB62, err := ParseBase62([]byte(want.Base62))
+		if err != nil || fromB62 != id {
+			t.Fatalf("failed parse golden base62 %v, err= %v", want.Base62, err)
+		}
+	}
+}
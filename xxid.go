@@ -1,6 +1,8 @@
 package xxid
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/binary"
 	"encoding/hex"
 	"errors"
@@ -39,6 +41,7 @@ type ID struct {
 	flag      uint16
 	mIDType   MachineIDType
 	machineID [16]byte
+	compact   bool // in the compact layout, see Generator.UseCompactLayout
 }
 
 // MachineIDType indicates the type of and ID's machine ID.
@@ -71,9 +74,13 @@ const (
 	// Specified16 indicates the machine ID is a 16 bytes value specified
 	// by user.
 	Specified16 MachineIDType = 6
+
+	// HashedIPv4 indicates the machine ID is a keyed hash of an IPv4
+	// address specified by user, see Generator.UseHashedIPv4.
+	HashedIPv4 MachineIDType = 7
 )
 
-const maxMachineIDType = Specified16
+const maxMachineIDType = HashedIPv4
 
 const (
 	minBinEncodedLen    = 16
@@ -86,10 +93,10 @@ const (
 const flagMask = 1 << 15
 
 var (
-	machineIdLength  = [...]int{4, 4, 4, 16, 4, 8, 16}
-	binEncodedLength = [...]int{16, 16, 16, 28, 16, 20, 28}
-	b62EncodedLength = [...]int{22, 22, 22, 38, 22, 27, 38}
-	strEncodedLength = [...]int{38, 38, 38, 62, 38, 46, 62}
+	machineIdLength  = [...]int{4, 4, 4, 16, 4, 8, 16, 4}
+	binEncodedLength = [...]int{16, 16, 16, 28, 16, 20, 28, 16}
+	b62EncodedLength = [...]int{22, 22, 22, 38, 22, 27, 38, 22}
+	strEncodedLength = [...]int{38, 38, 38, 62, 38, 46, 62, 38}
 	binDecodedLength = [...]int{22: 16, 27: 20, 38: 28}
 )
 
@@ -101,13 +108,31 @@ var (
 	errIncorrectStringLength = errors.New("xxid: length of string form is incorrect")
 	errInvalidStringRepr     = errors.New("xxid: string representation is invalid")
 	errInvalidJSONString     = errors.New("xxid: JSON string is invalid")
+	errInvalidUUIDRepr       = errors.New("xxid: UUID representation is invalid")
+	errInvalidURLEncoding    = errors.New("xxid: URL encoding is invalid")
+	errInvalidURN            = errors.New("xxid: URN is invalid")
+	errIncorrectBase36Length = errors.New("xxid: length of base36 form is incorrect")
+	errEncodedValueOverflow  = errors.New("xxid: encoded value overflows")
+	errInvalidProquint       = errors.New("xxid: proquint representation is invalid")
 	errUnknownMachineIDType  = errors.New("xxid: machine ID type is unknown")
+	errInvalidShortJSON      = errors.New("xxid: JSON number of short form is invalid")
+	errInvalidShort          = errors.New("xxid: short form is invalid")
+	errNoFixedMachineID      = errors.New("xxid: generator has no fixed machine ID")
+	errInvalidMachineID      = errors.New("xxid: machine ID contradicts its type")
 )
 
 func errInvalidBase62Character(char byte) error {
 	return fmt.Errorf("xxid: base62 character %v is invalid", char)
 }
 
+func errUnsupportedType(v interface{}) error {
+	return fmt.Errorf("xxid: unsupported type %T", v)
+}
+
+func errInvalidBase36Character(char byte) error {
+	return fmt.Errorf("xxid: base36 character %v is invalid", char)
+}
+
 var errUnsupportedMachineIDLength = errors.New("xxid: length of specified machine ID is unsupported")
 
 var beEnc = binary.BigEndian
@@ -115,17 +140,36 @@ var beEnc = binary.BigEndian
 // New generates a unique ID.
 func New() ID {
 	timeMsec, incr := readTimeAndCounter()
-	return newID(defaultGenerator, timeMsec, incr)
+	return newID(defaultGenerator.config(), timeMsec, incr)
 }
 
-// NewWithTime generates an ID with the given time.
+// NewWithTime generates an ID with the given time, the default generator
+// has no duplicate guard, it's the same as NewWithTimeUnsafe, use a
+// generator configured by Generator.UseTimeGuard if the same time may
+// be used repeatedly.
 func NewWithTime(t time.Time) ID {
 	timeMsec := t.UnixNano() / 1e6
 	incr := incrCounter()
-	return newID(defaultGenerator, timeMsec, incr)
+	return newID(defaultGenerator.config(), timeMsec, incr)
+}
+
+// NewString generates a unique ID and returns its string form, it's
+// the same as New().String() but saves the intermediate ID copy.
+func NewString() string {
+	timeMsec, incr := readTimeAndCounter()
+	id := newID(defaultGenerator.config(), timeMsec, incr)
+	return id.formatString(time.Local)
+}
+
+// NewBase62 generates a unique ID and returns its base62 form, it's
+// the same as New().Base62() but encodes with a single allocation.
+func NewBase62() []byte {
+	timeMsec, incr := readTimeAndCounter()
+	id := newID(defaultGenerator.config(), timeMsec, incr)
+	return id.Base62()
 }
 
-func newID(gen *Generator, timeMsec int64, counter uint16) ID {
+func newID(gen *genConfig, timeMsec int64, counter uint16) ID {
 	var id = ID{
 		timeMsec:  timeMsec,
 		pidOrPort: gen.pidOrPort,
@@ -137,10 +181,32 @@ func newID(gen *Generator, timeMsec int64, counter uint16) ID {
 	if id.flag == 0 {
 		id.flag = randFlag()
 	}
+	if gen.privacyMode {
+		var tmp [8]byte
+		mustCryptoRandRead(tmp[:])
+		copy(id.machineID[:4], tmp[:4])
+		id.pidOrPort = beEnc.Uint16(tmp[4:6])
+		id.flag = beEnc.Uint16(tmp[6:8]) & ^uint16(flagMask)
+	} else if gen.ephemeralMachineID {
+		var tmp [4]byte
+		mustCryptoRandRead(tmp[:])
+		copy(id.machineID[:4], tmp[:])
+	}
+	if gen.compact {
+		id.flag = 0
+		id.compact = true
+	}
 	return id
 }
 
-// SetFlag returns a new ID value with the given flag.
+// IsZero reports whether the ID is the zero value, it lets optional ID
+// fields tagged with "omitzero" be omitted by encoding/json (Go 1.24+).
+func (id ID) IsZero() bool {
+	return id == zeroID
+}
+
+// SetFlag returns a new ID value with the given flag, if the ID is in
+// the compact layout, the returned ID is in the full layout.
 //
 // Note that the function receiver is an ID value, which means that the
 // receiver ID value won't be changed, the caller need to save the
@@ -148,10 +214,12 @@ func newID(gen *Generator, timeMsec int64, counter uint16) ID {
 // returned value is a no-op.
 func (id ID) SetFlag(flag uint16) ID {
 	id.flag = flag | flagMask
+	id.compact = false
 	return id
 }
 
-// Flag returns the ID's flag value.
+// Flag returns the ID's flag value, it returns 0 if the flag is not
+// set, use HasFlag to tell an unset flag from a flag set to 0.
 func (id ID) Flag() uint16 {
 	if id.flag&flagMask == 0 {
 		return 0
@@ -159,11 +227,25 @@ func (id ID) Flag() uint16 {
 	return id.flag & ^uint16(flagMask)
 }
 
+// HasFlag reports whether the ID's flag is set, by Generator.UseFlag,
+// ID.SetFlag or the like.
+func (id ID) HasFlag() bool {
+	return id.flag&flagMask != 0
+}
+
 // Time returns the ID's time value.
 func (id ID) Time() time.Time {
 	return time.Unix(0, id.timeMsec*1e6)
 }
 
+// Precision returns the precision of the ID's time value, which is the
+// unit the time is stored in. All IDs of the current layout store
+// milliseconds, callers comparing or converting times should use it
+// instead of assuming the unit, in case other precisions are added.
+func (id ID) Precision() time.Duration {
+	return time.Millisecond
+}
+
 // MachineIDType returns the ID's machine ID type.
 func (id ID) MachineIDType() MachineIDType {
 	return id.mIDType
@@ -193,6 +275,45 @@ func (id ID) IP() net.IP {
 	return nil
 }
 
+// MatchHashedIPv4 reports whether the ID's machine ID is the keyed hash
+// of the given IP v4 produced by Generator.UseHashedIPv4.
+func (id ID) MatchHashedIPv4(ip net.IP, key []byte) bool {
+	if id.mIDType != HashedIPv4 || ip.To4() == nil {
+		return false
+	}
+	h := hashIPv4(ip, key)
+	return hmac.Equal(h[:], id.machineID[:4])
+}
+
+func hashIPv4(ip net.IP, key []byte) (out [4]byte) {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(ip.To4())
+	copy(out[:], mac.Sum(nil))
+	return
+}
+
+// ValidateMachineID checks that the machine ID is consistent with its
+// type, it is intended to catch corrupted or handcrafted IDs.
+//
+// An IPv4 machine ID must not be an unspecified, multicast or broadcast
+// address, an IPv6 machine ID must not be an unspecified, multicast or
+// IPv4-mapped address. Other types accept any value.
+func (id ID) ValidateMachineID() error {
+	switch id.mIDType {
+	case IPv4:
+		ip := id.IP()
+		if ip.IsUnspecified() || ip.IsMulticast() || ip.Equal(net.IPv4bcast) {
+			return errInvalidMachineID
+		}
+	case IPv6:
+		ip := id.IP()
+		if ip.IsUnspecified() || ip.IsMulticast() || ip.To4() != nil {
+			return errInvalidMachineID
+		}
+	}
+	return nil
+}
+
 // Pid returns the ID's pid value, note that the returned value may
 // be a port number if the Generator is configured by UsePort.
 func (id ID) Pid() uint16 {
@@ -205,6 +326,18 @@ func (id ID) Port() uint16 {
 	return id.pidOrPort
 }
 
+// LookupPid returns the ID's pid value and true, or false if the pid
+// or port is omitted by Generator.UseNoPid.
+func (id ID) LookupPid() (uint16, bool) {
+	return id.pidOrPort, id.pidOrPort != 0
+}
+
+// LookupPort returns the ID's port number and true, or false if the pid
+// or port is omitted by Generator.UseNoPid.
+func (id ID) LookupPort() (uint16, bool) {
+	return id.pidOrPort, id.pidOrPort != 0
+}
+
 // IPPortAddr returns and address string consists of the IP address and
 // the port number if the machine ID is an IP address, else it returns "".
 func (id ID) IPPortAddr() string {
@@ -227,13 +360,25 @@ func (id ID) Counter() uint16 {
 }
 
 // Short returns the time and counter value of the ID as an int64, the
-// returned value is guaranteed to be unique inside a process.
+// returned value is unique inside a process among IDs generated by New
+// of generators using SharedSequence. IDs generated by generators using
+// IsolatedSequence or by NewWithTime may have the same short value,
+// use ProcessUniqueShort if the value is used as a key on its own.
+// Short values of IDs from different processes may collide, see
+// ShortFolder to disambiguate them.
 func (id ID) Short() int64 {
 	return id.timeMsec<<16 | int64(id.counter)
 }
 
 func (id ID) encodeBinary() []byte {
 	out := make([]byte, binEncodedLength[id.mIDType])
+	id.putBinary(out)
+	return out
+}
+
+// putBinary writes the binary form of the ID to out, the length of out
+// must not be less than the length of the binary form.
+func (id ID) putBinary(out []byte) {
 	offset := 0
 
 	// timestamp since epoch and machine ID type, 6 bytes
@@ -245,7 +390,7 @@ func (id ID) encodeBinary() []byte {
 	offset += 2
 	// machine ID
 	switch id.mIDType {
-	case Random, HostID, IPv4, Specified4:
+	case Random, HostID, IPv4, Specified4, HashedIPv4:
 		copy(out[offset:offset+4], id.machineID[:4])
 		offset += 4
 	case Specified8:
@@ -260,13 +405,12 @@ func (id ID) encodeBinary() []byte {
 	offset += 2
 	// flag, 2 bytes
 	beEnc.PutUint16(out[offset:offset+2], id.flag)
-	return out
 }
 
 func decodeBinary(src []byte) (ID, error) {
 	var id ID
 	inputLen := len(src)
-	if inputLen < minBinEncodedLen {
+	if inputLen < minCompactBinLen {
 		return zeroID, errIncorrectBinaryLength
 	}
 
@@ -277,7 +421,11 @@ func decodeBinary(src []byte) (ID, error) {
 	if id.mIDType > maxMachineIDType {
 		return zeroID, errUnknownMachineIDType
 	}
-	if inputLen != binEncodedLength[id.mIDType] {
+	switch inputLen {
+	case binEncodedLength[id.mIDType]:
+	case compactBinLength[id.mIDType]:
+		id.compact = true
+	default:
 		return zeroID, errIncorrectBinaryLength
 	}
 
@@ -296,34 +444,43 @@ func decodeBinary(src []byte) (ID, error) {
 	offset += 2
 
 	// flag, 2 bytes
-	id.flag = beEnc.Uint16(src[offset : offset+2])
+	if !id.compact {
+		id.flag = beEnc.Uint16(src[offset : offset+2])
+	}
 
 	return id, nil
 }
 
 // Binary encodes the ID into its binary form. The returned bytes may
-// be of length 16, 20, or 28 according to the machine ID type.
+// be of length 16, 20, or 28 according to the machine ID type, or 14,
+// 18, or 26 in the compact layout.
 func (id ID) Binary() []byte {
-	return id.encodeBinary()
+	out := id.encodeBinary()
+	return out[:id.binaryLen()]
 }
 
 // Base62 encodes the ID into its base62 form. The returned bytes may
-// be of length 22, 27, or 38 according to the machine ID type.
+// be of length 22, 27, or 38 according to the machine ID type, or 19,
+// 25, or 35 in the compact layout.
 func (id ID) Base62() []byte {
-	buf := id.encodeBinary()
-	out := make([]byte, b62EncodedLength[id.mIDType])
-	encodeBase62(out, buf)
+	out := make([]byte, id.base62Len())
+	id.putBase62(out)
 	return out
 }
 
 // String encodes the ID into its string form. The returned string may
-// be of length 38, 46, or 62 according to the machine ID type,
+// be of length 38, 46, or 62 according to the machine ID type, or 34,
+// 42, or 58 in the compact layout,
 func (id ID) String() string {
-	var out = make([]byte, strEncodedLength[id.mIDType])
+	return id.formatString(time.Local)
+}
+
+func (id ID) formatString(loc *time.Location) string {
+	var out = make([]byte, id.stringLen())
 	var tmp [2]byte
 
 	// timestamp
-	t := time.Unix(0, id.timeMsec*1e6)
+	t := time.Unix(0, id.timeMsec*1e6).In(loc)
 	msec := id.timeMsec % 1000
 	year, month, day := t.Date()
 	hour, minute, second := t.Clock()
@@ -335,14 +492,18 @@ func (id ID) String() string {
 	int2byte(out[12:14], second)
 	int2byte(out[14:17], int(msec))
 
+	offset := 17
+
 	// flag
-	beEnc.PutUint16(tmp[:2], id.flag)
-	hex.Encode(out[17:21], tmp[:2])
+	if !id.compact {
+		beEnc.PutUint16(tmp[:2], id.flag)
+		hex.Encode(out[17:21], tmp[:2])
+		offset += 4
+	}
 
 	// machine ID type
-	int2byte(out[21:22], int(id.mIDType))
-
-	offset := 22
+	int2byte(out[offset:offset+1], int(id.mIDType))
+	offset++
 
 	// machine ID
 	mIdLen := machineIdLength[id.mIDType]
@@ -365,19 +526,62 @@ func (id ID) String() string {
 
 // MarshalJSON encodes ID to a JSON string using its base62 form.
 func (id ID) MarshalJSON() ([]byte, error) {
-	buf := id.encodeBinary()
-	out := make([]byte, b62EncodedLength[id.mIDType]+2)
-	encodeBase62(out[1:len(out)-1], buf[:])
-	out[0], out[len(out)-1] = '"', '"'
-	return out, nil
+	out := make([]byte, 0, id.base62Len()+2)
+	return id.AppendJSON(out), nil
 }
 
-// UnmarshalJSON decodes ID from a JSON string in its base62 form.
+// AppendJSON appends the JSON string of ID in its base62 form to dst,
+// and returns the extended buffer.
+//
+// It is intended to be used by JSON engines and code generators which
+// write directly into a buffer, e.g. sonic, go-json and easyjson.
+func (id ID) AppendJSON(dst []byte) []byte {
+	n := len(dst)
+	b62Len := id.base62Len()
+	for i := 0; i < b62Len+2; i++ {
+		dst = append(dst, '"')
+	}
+	id.putBase62(dst[n+1 : n+1+b62Len])
+	return dst
+}
+
+// UnmarshalJSON decodes ID from a JSON string in its base62 form, a JSON
+// null is a no-op, following the convention of encoding/json.
 func (id *ID) UnmarshalJSON(buf []byte) error {
+	if string(buf) == "null" {
+		return nil
+	}
+	tmp, err := UnmarshalJSONBytes(buf)
+	if err != nil {
+		return err
+	}
+	*id = tmp
+	return nil
+}
+
+// UnmarshalJSONBytes decodes an ID from a JSON string in its base62 form.
+//
+// Unlike ID.UnmarshalJSON, it returns the decoded ID as a value, which
+// is convenient for JSON engines and code generators which decode
+// values without a pointer receiver.
+func UnmarshalJSONBytes(buf []byte) (ID, error) {
 	if len(buf) < 2 || buf[0] != '"' || buf[len(buf)-1] != '"' {
-		return errInvalidJSONString
+		return zeroID, errInvalidJSONString
 	}
-	tmp, err := ParseBase62(buf[1 : len(buf)-1])
+	return ParseBase62(buf[1 : len(buf)-1])
+}
+
+// MarshalText encodes ID to its base62 form, it implements the
+// encoding.TextMarshaler interface, thus ID can also be used as
+// JSON object keys.
+func (id ID) MarshalText() ([]byte, error) {
+	return id.Base62(), nil
+}
+
+// UnmarshalText decodes ID from its base62 form, it implements the
+// encoding.TextUnmarshaler interface.
+func (id *ID) UnmarshalText(buf []byte) error {
+	tmp, err := ParseBase62(buf)
 	if err != nil {
 		return err
 	}
@@ -392,12 +596,35 @@ func ParseBinary(src []byte) (ID, error) {
 
 // ParseBase62 parses an ID from its base62 form.
 func ParseBase62(src []byte) (ID, error) {
+	if caches := getParseCaches(); caches != nil {
+		if id, ok := caches.base62.get(src); ok {
+			return id, nil
+		}
+		id, err := parseBase62(src)
+		if err == nil {
+			caches.base62.add(src, id)
+		}
+		return id, err
+	}
+	return parseBase62(src)
+}
+
+// ParseBase62String parses an ID from its base62 form given as a string,
+// it avoids converting the string to bytes by the caller.
+func ParseBase62String(src string) (ID, error) {
+	return ParseBase62(s2b(src))
+}
+
+func parseBase62(src []byte) (ID, error) {
 	inputLen := len(src)
-	if inputLen < minBase62EncodedLen || inputLen > maxBase62EncodedLen {
+	if inputLen < minCompactBase62Len || inputLen > maxBase62EncodedLen {
 		return zeroID, errIncorrectBase62Length
 	}
 	binLen := binDecodedLength[inputLen]
 	if binLen == 0 {
+		if inputLen < len(compactBinDecodedLength) && compactBinDecodedLength[inputLen] != 0 {
+			return parseCompactBase62(src, compactBinDecodedLength[inputLen])
+		}
 		return zeroID, errIncorrectBase62Length
 	}
 
@@ -411,25 +638,51 @@ func ParseBase62(src []byte) (ID, error) {
 
 // ParseString parses an ID from its string form.
 func ParseString(str string) (ID, error) {
+	if caches := getParseCaches(); caches != nil {
+		if id, ok := caches.str.get(s2b(str)); ok {
+			return id, nil
+		}
+		id, err := parseString(str, time.Local)
+		if err == nil {
+			caches.str.add(s2b(str), id)
+		}
+		return id, err
+	}
+	return parseString(str, time.Local)
+}
+
+func parseString(str string, loc *time.Location) (ID, error) {
 	var id ID
 	inputLen := len(str)
-	if inputLen < minStringEncodedLen {
+	if inputLen < minCompactStringLen {
 		return zeroID, errIncorrectStringLength
 	}
-	machineIdType := MachineIDType(str[21] - '0')
+	// the flag is omitted in the compact layout
+	compact := false
+	switch inputLen {
+	case compactStrLength[HostID], compactStrLength[Specified8], compactStrLength[Specified16]:
+		compact = true
+	}
+	typeOffset := 21
+	if compact {
+		typeOffset = 17
+	}
+	machineIdType := MachineIDType(str[typeOffset] - '0')
 	if machineIdType < 0 || machineIdType > maxMachineIDType {
 		return zeroID, errUnknownMachineIDType
 	}
-	if inputLen != strEncodedLength[machineIdType] {
+	if compact && inputLen != compactStrLength[machineIdType] ||
+		!compact && inputLen != strEncodedLength[machineIdType] {
 		return zeroID, errIncorrectStringLength
 	}
+	id.compact = compact
 
 	var err error
 	var tmp [2]byte
 
 	var parseTimestamp = func(buf string) (timeMsec int64, err error) {
 		layout := "20060102150405"
-		t, err := time.ParseInLocation(layout, buf[:14], time.Local)
+		t, err := time.ParseInLocation(layout, buf[:14], loc)
 		if err != nil {
 			return
 		}
@@ -454,15 +707,17 @@ func ParseString(str string) (ID, error) {
 	}
 
 	// flag 2, bytes
-	id.flag, err = parseUint16(str[17:21])
-	if err != nil {
-		return zeroID, errInvalidStringRepr
+	if !compact {
+		id.flag, err = parseUint16(str[17:21])
+		if err != nil {
+			return zeroID, errInvalidStringRepr
+		}
 	}
 
 	// machine ID type, 1 byte
 	id.mIDType = machineIdType
 
-	offset := 22
+	offset := typeOffset + 1
 
 	// machine ID
 	mIdLen := machineIdLength[machineIdType]
@@ -521,7 +776,3 @@ func s2b(s string) []byte {
 	}
 	return *(*[]byte)(unsafe.Pointer(bh))
 }
-
-//go:noescape
-//go:linkname runtime_fastrand runtime.fastrand
-func runtime_fastrand() uint32
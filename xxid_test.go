@@ -1,8 +1,10 @@
 package xxid
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestID_simple(t *testing.T) {
@@ -42,6 +44,11 @@ func TestIDBase62(t *testing.T) {
 			id.timeMsec, id.pidOrPort, id.counter, id.flag, id.mIDType, id.machineID,
 			got.timeMsec, got.pidOrPort, got.counter, got.flag, got.mIDType, got.machineID)
 	}
+
+	got, err = ParseBase62String(string(encoded))
+	if err != nil || got != id {
+		t.Fatalf("ParseBase62String result not match, err= %v", err)
+	}
 }
 
 func TestIDString(t *testing.T) {
@@ -82,6 +89,17 @@ func TestID_Methods(t *testing.T) {
 	}
 }
 
+func TestID_ShardKey(t *testing.T) {
+	id := New()
+	if id.ShardKey() != id.ShardKey() {
+		t.Fatalf("ShardKey is not stable")
+	}
+	got, _ := ParseBase62(id.Base62())
+	if got.ShardKey() != id.ShardKey() {
+		t.Fatalf("ShardKey not match after parsing")
+	}
+}
+
 func BenchmarkNew(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = New()
@@ -129,3 +147,105 @@ func BenchmarkParseString(b *testing.B) {
 		_, _ = ParseString(str)
 	}
 }
+
+func TestID_JSON(t *testing.T) {
+	id := New()
+	buf, err := json.Marshal(id)
+	if err != nil {
+		t.Fatalf("failed marshal JSON, err= %v", err)
+	}
+	if want := `"` + string(id.Base62()) + `"`; string(buf) != want {
+		t.Fatalf("JSON not match, want= %v, got= %v", want, string(buf))
+	}
+	if got := id.AppendJSON([]byte("x")); string(got) != "x"+string(buf) {
+		t.Fatalf("AppendJSON result not match, got= %v", string(got))
+	}
+
+	got, err := UnmarshalJSONBytes(buf)
+	if err != nil || got != id {
+		t.Fatalf("failed UnmarshalJSONBytes, err= %v", err)
+	}
+	var tmp ID
+	if err = json.Unmarshal(buf, &tmp); err != nil || tmp != id {
+		t.Fatalf("failed unmarshal JSON, err= %v", err)
+	}
+	if _, err = UnmarshalJSONBytes(id.Base62()); err == nil {
+		t.Fatalf("expect error for unquoted JSON value")
+	}
+
+	if err = json.Unmarshal([]byte("null"), &tmp); err != nil || tmp != id {
+		t.Fatalf("JSON null should leave ID unchanged, err= %v", err)
+	}
+	ptrBuf, err := json.Marshal(&id)
+	if err != nil || string(ptrBuf) != string(buf) {
+		t.Fatalf("pointer and value should marshal the same, got= %s", ptrBuf)
+	}
+
+	m := map[ID]int{id: 1}
+	buf, err = json.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed marshal JSON map, err= %v", err)
+	}
+	var m2 map[ID]int
+	if err = json.Unmarshal(buf, &m2); err != nil || m2[id] != 1 {
+		t.Fatalf("failed unmarshal JSON map, err= %v", err)
+	}
+}
+
+func TestID_IsZero(t *testing.T) {
+	var id ID
+	if !id.IsZero() {
+		t.Fatalf("zero ID should be zero")
+	}
+	if New().IsZero() {
+		t.Fatalf("new ID should not be zero")
+	}
+	parsed, err := ParseBase62(id.Base62())
+	if err != nil || !parsed.IsZero() {
+		t.Fatalf("parsed zero ID should be zero, err= %v", err)
+	}
+}
+
+func TestID_HasFlag(t *testing.T) {
+	if id := New(); id.HasFlag() || id.Flag() != 0 {
+		t.Fatalf("flag should not be set by default")
+	}
+	if id := New().SetFlag(0); !id.HasFlag() || id.Flag() != 0 {
+		t.Fatalf("flag set to 0 should be distinguishable from unset flag")
+	}
+	if id := NewGenerator().UseFlag(123).New(); !id.HasFlag() || id.Flag() != 123 {
+		t.Fatalf("flag set by generator not match")
+	}
+}
+
+func TestID_Precision(t *testing.T) {
+	id := New()
+	if id.Precision() != time.Millisecond {
+		t.Fatalf("precision not match, got= %v", id.Precision())
+	}
+	if !id.Time().Equal(id.Time().Truncate(id.Precision())) {
+		t.Fatalf("time should be in the unit of precision")
+	}
+}
+
+func TestNewStringBase62(t *testing.T) {
+	s := NewString()
+	id, err := ParseString(s)
+	if err != nil || id.String() != s {
+		t.Fatalf("NewString = %v, parse err= %v", s, err)
+	}
+	b := NewBase62()
+	id, err = ParseBase62(b)
+	if err != nil || string(id.Base62()) != string(b) {
+		t.Fatalf("NewBase62 = %s, parse err= %v", b, err)
+	}
+	if raceEnabled {
+		return
+	}
+	if n := testing.AllocsPerRun(100, func() { NewBase62() }); n > 1 {
+		t.Fatalf("NewBase62 allocates %v times", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { NewString() }); n > 1 {
+		t.Fatalf("NewString allocates %v times", n)
+	}
+}
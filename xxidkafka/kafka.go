@@ -0,0 +1,92 @@
+// Package xxidkafka provides helpers to use xxid IDs as Kafka record keys
+// and headers.
+//
+// The helpers don't depend on any specific Kafka client, they work with
+// plain bytes, which can be used with sarama, franz-go or other clients,
+// e.g. with sarama:
+//
+//	msg := &sarama.ProducerMessage{
+//		Key: sarama.ByteEncoder(xxidkafka.RecordKey(id)),
+//		Headers: []sarama.RecordHeader{
+//			{Key: []byte(xxidkafka.RequestIDHeader), Value: xxidkafka.HeaderValue(id)},
+//		},
+//	}
+//
+// or with franz-go:
+//
+//	rec := &kgo.Record{
+//		Key: xxidkafka.RecordKey(id),
+//		Headers: []kgo.RecordHeader{
+//			{Key: xxidkafka.RequestIDHeader, Value: xxidkafka.HeaderValue(id)},
+//		},
+//	}
+package xxidkafka
+
+import (
+	"github.com/jxskiss/xxid/v2"
+)
+
+// RequestIDHeader is the record header key which carries a request ID.
+const RequestIDHeader = "x-request-id"
+
+// RecordKey returns the record key of an ID, which is the ID's binary form.
+func RecordKey(id xxid.ID) []byte {
+	return id.Binary()
+}
+
+// ParseRecordKey parses an ID from a record key returned by RecordKey.
+func ParseRecordKey(key []byte) (xxid.ID, error) {
+	return xxid.ParseBinary(key)
+}
+
+// HeaderValue returns the header value of an ID, which is the ID's
+// base62 form, it is readable when inspecting records by tools.
+func HeaderValue(id xxid.ID) []byte {
+	return id.Base62()
+}
+
+// ParseHeaderValue parses an ID from a header value returned by HeaderValue.
+func ParseHeaderValue(value []byte) (xxid.ID, error) {
+	return xxid.ParseBase62(value)
+}
+
+// Header is a record header, it's the common shape of record header
+// types of most Kafka clients.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// InjectRequestID sets the request ID header to headers, an existing
+// request ID header will be replaced.
+func InjectRequestID(headers []Header, id xxid.ID) []Header {
+	value := HeaderValue(id)
+	for i := range headers {
+		if headers[i].Key == RequestIDHeader {
+			headers[i].Value = value
+			return headers
+		}
+	}
+	return append(headers, Header{Key: RequestIDHeader, Value: value})
+}
+
+// ExtractRequestID finds the request ID header from headers and parses
+// the ID, ok is false if the header does not exist or is invalid.
+func ExtractRequestID(headers []Header) (id xxid.ID, ok bool) {
+	for _, h := range headers {
+		if h.Key == RequestIDHeader {
+			id, err := ParseHeaderValue(h.Value)
+			return id, err == nil
+		}
+	}
+	return id, false
+}
+
+// Partition computes the partition of an ID from ID.ShardKey.
+// It panics if numPartitions is not positive.
+func Partition(id xxid.ID, numPartitions int32) int32 {
+	if numPartitions <= 0 {
+		panic("xxidkafka: numPartitions must be positive")
+	}
+	return int32(id.ShardKey() % uint32(numPartitions))
+}
@@ -0,0 +1,42 @@
+package xxidkafka
+
+import (
+	"testing"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestRecordKeyAndHeader(t *testing.T) {
+	id := xxid.New()
+
+	got, err := ParseRecordKey(RecordKey(id))
+	if err != nil || got != id {
+		t.Fatalf("record key round trip failed, err= %v", err)
+	}
+
+	headers := []Header{{Key: "other", Value: []byte("x")}}
+	headers = InjectRequestID(headers, xxid.New())
+	headers = InjectRequestID(headers, id)
+	if len(headers) != 2 {
+		t.Fatalf("request ID header should be replaced, got %d headers", len(headers))
+	}
+	got, ok := ExtractRequestID(headers)
+	if !ok || got != id {
+		t.Fatalf("extract request ID failed, ok= %v", ok)
+	}
+	if _, ok = ExtractRequestID(headers[:1]); ok {
+		t.Fatalf("extract request ID from headers without it should fail")
+	}
+}
+
+func TestPartition(t *testing.T) {
+	const numPartitions = 12
+	id := xxid.New()
+	p := Partition(id, numPartitions)
+	if p < 0 || p >= numPartitions {
+		t.Fatalf("partition out of range: %v", p)
+	}
+	if Partition(id, numPartitions) != p {
+		t.Fatalf("partition is not stable")
+	}
+}
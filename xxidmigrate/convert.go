@@ -0,0 +1,186 @@
+// Package xxidmigrate converts IDs generated by other libraries into
+// xxid IDs, and helps migrating database columns which hold such IDs.
+//
+// The conversions preserve the timestamp of the source IDs in millisecond
+// precision, and keep all other bits of the source IDs in the machine ID
+// and counter fields, thus converted IDs are unique as long as the source
+// IDs are unique, and they sort in the same order as the source IDs by
+// time.
+package xxidmigrate
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+// Kind indicates the kind of source IDs.
+type Kind string
+
+const (
+	// Xid indicates github.com/rs/xid IDs, in 20 chars string or
+	// 12 bytes binary form.
+	Xid Kind = "xid"
+
+	// UUIDv1 indicates version 1 UUIDs, in 36 chars hyphenated text or
+	// 16 bytes binary form.
+	UUIDv1 Kind = "uuidv1"
+
+	// ULID indicates ULIDs, in 26 chars Crockford base32 text or 16 bytes
+	// binary form.
+	ULID Kind = "ulid"
+)
+
+var (
+	errInvalidXid    = errors.New("xxidmigrate: xid is invalid")
+	errInvalidUUIDv1 = errors.New("xxidmigrate: UUIDv1 is invalid")
+	errInvalidULID   = errors.New("xxidmigrate: ULID is invalid")
+)
+
+var (
+	xidEncoding  = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+	ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// Convert converts src of the given kind to an xxid ID.
+func Convert(kind Kind, src []byte) (xxid.ID, error) {
+	switch kind {
+	case Xid:
+		return FromXid(src)
+	case UUIDv1:
+		return FromUUIDv1(src)
+	case ULID:
+		return FromULID(src)
+	}
+	return xxid.ID{}, fmt.Errorf("xxidmigrate: unknown kind %q", kind)
+}
+
+// FromXid converts a xid to an xxid ID.
+//
+// The 3 bytes machine ID, 2 bytes pid and 3 bytes counter of the xid are
+// kept as an 8 bytes machine ID (Specified8), the pid and lower 2 bytes
+// of counter are also kept in the pid and counter fields.
+func FromXid(src []byte) (xxid.ID, error) {
+	var buf [12]byte
+	switch len(src) {
+	case 12:
+		copy(buf[:], src)
+	case 20:
+		n, err := xidEncoding.Decode(buf[:], src)
+		if err != nil || n != 12 {
+			return xxid.ID{}, errInvalidXid
+		}
+	default:
+		return xxid.ID{}, errInvalidXid
+	}
+	sec := int64(binary.BigEndian.Uint32(buf[0:4]))
+	pid := binary.BigEndian.Uint16(buf[7:9])
+	counter := binary.BigEndian.Uint16(buf[10:12])
+	return build(sec*1000, buf[4:12], pid, counter), nil
+}
+
+// FromUUIDv1 converts a version 1 UUID to an xxid ID.
+//
+// The whole UUID is kept as a 16 bytes machine ID (Specified16), the
+// sub-millisecond part of the UUID timestamp is kept in the counter.
+func FromUUIDv1(src []byte) (xxid.ID, error) {
+	var buf [16]byte
+	switch len(src) {
+	case 16:
+		copy(buf[:], src)
+	case 36:
+		s := strings.Replace(string(src), "-", "", -1)
+		if len(s) != 32 {
+			return xxid.ID{}, errInvalidUUIDv1
+		}
+		if _, err := hex.Decode(buf[:], []byte(s)); err != nil {
+			return xxid.ID{}, errInvalidUUIDv1
+		}
+	default:
+		return xxid.ID{}, errInvalidUUIDv1
+	}
+	if buf[6]>>4 != 1 {
+		return xxid.ID{}, errInvalidUUIDv1
+	}
+
+	// 100-nanosecond intervals since 1582-10-15 00:00:00 UTC
+	const gregorianToUnix = 122192928000000000
+	timeLow := uint64(binary.BigEndian.Uint32(buf[0:4]))
+	timeMid := uint64(binary.BigEndian.Uint16(buf[4:6]))
+	timeHigh := uint64(binary.BigEndian.Uint16(buf[6:8]) & 0x0fff)
+	ticks := int64(timeHigh<<48|timeMid<<32|timeLow) - gregorianToUnix
+	timeMsec := ticks / 10000
+	counter := uint16(ticks % 10000)
+	return build(timeMsec, buf[:], 0, counter), nil
+}
+
+// FromULID converts an ULID to an xxid ID.
+//
+// The first 8 bytes of the 10 bytes random part are kept as an 8 bytes
+// machine ID (Specified8), the remaining 2 bytes are kept in the counter.
+func FromULID(src []byte) (xxid.ID, error) {
+	var buf [16]byte
+	switch len(src) {
+	case 16:
+		copy(buf[:], src)
+	case 26:
+		if err := decodeULID(buf[:], src); err != nil {
+			return xxid.ID{}, err
+		}
+	default:
+		return xxid.ID{}, errInvalidULID
+	}
+	var tmp [8]byte
+	copy(tmp[2:], buf[0:6])
+	timeMsec := int64(binary.BigEndian.Uint64(tmp[:]))
+	counter := binary.BigEndian.Uint16(buf[14:16])
+	return build(timeMsec, buf[6:14], 0, counter), nil
+}
+
+func decodeULID(dst []byte, src []byte) error {
+	// The first character must not be larger than '7', else the
+	// value overflows 128 bits.
+	if strings.IndexByte("01234567", src[0]) < 0 {
+		return errInvalidULID
+	}
+	var hi, lo uint64
+	for _, c := range src {
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		x := strings.IndexByte(ulidAlphabet, c)
+		if x < 0 {
+			return errInvalidULID
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(x)
+	}
+	binary.BigEndian.PutUint64(dst[0:8], hi)
+	binary.BigEndian.PutUint64(dst[8:16], lo)
+	return nil
+}
+
+// build makes an ID by assembling its binary form, the machine ID must
+// be 8 or 16 bytes, the flag is left unset.
+func build(timeMsec int64, machineID []byte, pid, counter uint16) xxid.ID {
+	mIDType := xxid.Specified8
+	if len(machineID) == 16 {
+		mIDType = xxid.Specified16
+	}
+	buf := make([]byte, 8, 8+len(machineID)+4)
+	binary.BigEndian.PutUint64(buf, uint64(timeMsec)<<3|uint64(mIDType))
+	copy(buf[:6], buf[2:8])
+	binary.BigEndian.PutUint16(buf[6:8], counter)
+	buf = append(buf, machineID...)
+	buf = append(buf, byte(pid>>8), byte(pid), 0, 0)
+	id, err := xxid.ParseBinary(buf)
+	if err != nil {
+		panic(err) // unreachable
+	}
+	return id
+}
@@ -0,0 +1,56 @@
+package xxidmigrate
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	table := []struct {
+		kind      Kind
+		src       string
+		timeMsec  int64
+		machineID []byte
+		counter   uint16
+	}{
+		{Xid, "c6c4r901081h4d2mf2d0", 1637371300000,
+			[]byte{1, 2, 3, 0x12, 0x34, 0x56, 0x78, 0x9a}, 0x789a},
+		{UUIDv1, "36e6fdce-49a0-11ec-9234-000102030405", 1637371300634,
+			[]byte{0x36, 0xe6, 0xfd, 0xce, 0x49, 0xa0, 0x11, 0xec, 0x92, 0x34, 0, 1, 2, 3, 4, 5}, 5678},
+		{ULID, "01FMXEYJRT041061050R3GG28A", 1637371300634,
+			[]byte{1, 2, 3, 4, 5, 6, 7, 8}, 0x090a},
+		{ULID, "01fmxeyjrt041061050r3gg28a", 1637371300634,
+			[]byte{1, 2, 3, 4, 5, 6, 7, 8}, 0x090a},
+	}
+	for _, tc := range table {
+		id, err := Convert(tc.kind, []byte(tc.src))
+		if err != nil {
+			t.Fatalf("failed convert %v %v, err= %v", tc.kind, tc.src, err)
+		}
+		if got := id.Time().UnixNano() / 1e6; got != tc.timeMsec {
+			t.Fatalf("%v time not match, want= %v, got= %v", tc.kind, tc.timeMsec, got)
+		}
+		if !bytes.Equal(id.MachineID(), tc.machineID) {
+			t.Fatalf("%v machine ID not match, got= %v", tc.kind, id.MachineID())
+		}
+		if id.Counter() != tc.counter {
+			t.Fatalf("%v counter not match, got= %v", tc.kind, id.Counter())
+		}
+	}
+
+	for _, tc := range []struct {
+		kind Kind
+		src  string
+	}{
+		{Xid, "c6c4r901081h4d2mf2d"},
+		{Xid, "c6c4r901081h4d2mf2dz"},
+		{UUIDv1, "36e6fdce-49a0-41ec-9234-000102030405"},
+		{ULID, "81FMXEYJRT041061050R3GG28A"},
+		{ULID, "01FMXEYJRT041061050R3GG28U"},
+		{"unknown", "x"},
+	} {
+		if _, err := Convert(tc.kind, []byte(tc.src)); err == nil {
+			t.Fatalf("expect error for invalid %v %v", tc.kind, tc.src)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package xxidmigrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DefaultBatchSize is the number of rows Migrate reads and updates in
+// one transaction when Options.BatchSize is not set.
+const DefaultBatchSize = 1000
+
+// Options configures Migrate.
+//
+// Table and column names are put into SQL statements verbatim, they
+// should be quoted by the caller if necessary.
+type Options struct {
+	// Kind is the kind of IDs stored in KeyColumn.
+	Kind Kind
+
+	// Table is the table to migrate.
+	Table string
+
+	// KeyColumn is the column which holds the source IDs, it must be
+	// unique since rows are paged and updated by it.
+	KeyColumn string
+
+	// TargetColumn is the column to write the converted IDs to, in
+	// base62 form. If it is empty, the table won't be changed.
+	TargetColumn string
+
+	// DollarPlaceholder makes the statements use placeholders like
+	// "$1" (e.g. PostgreSQL), else "?" is used (e.g. MySQL, SQLite).
+	DollarPlaceholder bool
+
+	// BatchSize is the number of rows read and updated in one
+	// transaction, DefaultBatchSize is used if it is not positive.
+	BatchSize int
+}
+
+// Migrate reads all values of KeyColumn from Table in batches ordered
+// by KeyColumn, converts them into xxid IDs, and writes them back to
+// TargetColumn, each batch in its own transaction. If an error occurs,
+// the batches committed before are kept, a later run converts the
+// same keys to the same IDs, thus it is safe to run Migrate again.
+//
+// Source IDs are bound in statements as strings if they are read in
+// text form, or as bytes if they are read in binary form, so that they
+// match the column type, e.g. PostgreSQL does not compare bytea values
+// with text columns.
+//
+// If mapping is not nil, a line "<source>,<base62 ID>" is written to it
+// for each row. It returns the number of converted rows.
+func Migrate(ctx context.Context, db *sql.DB, opts Options, mapping io.Writer) (int, error) {
+	if opts.Table == "" || opts.KeyColumn == "" {
+		return 0, errors.New("xxidmigrate: table and key column must be specified")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	p1, p2 := "?", "?"
+	if opts.DollarPlaceholder {
+		p1, p2 = "$1", "$2"
+	}
+	limit := " ORDER BY " + opts.KeyColumn + " LIMIT " + strconv.Itoa(batchSize)
+	firstQuery := "SELECT " + opts.KeyColumn + " FROM " + opts.Table + limit
+	nextQuery := "SELECT " + opts.KeyColumn + " FROM " + opts.Table +
+		" WHERE " + opts.KeyColumn + " > " + p1 + limit
+	update := "UPDATE " + opts.Table + " SET " + opts.TargetColumn + " = " + p1 +
+		" WHERE " + opts.KeyColumn + " = " + p2
+
+	var count int
+	var last interface{}
+	for {
+		keys, err := readKeys(ctx, db, firstQuery, nextQuery, last)
+		if err != nil {
+			return count, err
+		}
+		if len(keys) == 0 {
+			return count, nil
+		}
+
+		var tx *sql.Tx
+		if opts.TargetColumn != "" {
+			if tx, err = db.BeginTx(ctx, nil); err != nil {
+				return count, err
+			}
+		}
+		for i, key := range keys {
+			src := keyBytes(key)
+			id, err := Convert(opts.Kind, src)
+			if err != nil {
+				rollback(tx)
+				return count, fmt.Errorf("%v: %q", err, src)
+			}
+			dst := string(id.Base62())
+			if mapping != nil {
+				if _, err = fmt.Fprintf(mapping, "%s,%s\n", src, dst); err != nil {
+					rollback(tx)
+					return count, err
+				}
+			}
+			if tx != nil {
+				if _, err = tx.ExecContext(ctx, update, dst, key); err != nil {
+					tx.Rollback()
+					return count, errors.New("xxidmigrate: update row " + strconv.Itoa(count+i) + ": " + err.Error())
+				}
+			}
+		}
+		if tx != nil {
+			if err = tx.Commit(); err != nil {
+				return count, err
+			}
+		}
+		count += len(keys)
+		if len(keys) < batchSize {
+			return count, nil
+		}
+		last = keys[len(keys)-1]
+	}
+}
+
+// readKeys reads a batch of keys following last, or the first batch
+// if last is nil. Keys read as bytes are converted to strings unless
+// they are IDs in binary form.
+func readKeys(ctx context.Context, db *sql.DB, firstQuery, nextQuery string, last interface{}) ([]interface{}, error) {
+	var rows *sql.Rows
+	var err error
+	if last == nil {
+		rows, err = db.QueryContext(ctx, firstQuery)
+	} else {
+		rows, err = db.QueryContext(ctx, nextQuery, last)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []interface{}
+	for rows.Next() {
+		var key interface{}
+		if err = rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if b, ok := key.([]byte); ok && !isBinaryForm(b) {
+			key = string(b)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// isBinaryForm tells whether b is a source ID in binary form, xids are
+// 12 bytes, UUIDs and ULIDs are 16 bytes, their text forms are longer.
+func isBinaryForm(b []byte) bool {
+	return len(b) == 12 || len(b) == 16
+}
+
+func keyBytes(key interface{}) []byte {
+	switch x := key.(type) {
+	case []byte:
+		return x
+	case string:
+		return []byte(x)
+	}
+	return []byte(fmt.Sprint(key))
+}
+
+func rollback(tx *sql.Tx) {
+	if tx != nil {
+		tx.Rollback()
+	}
+}
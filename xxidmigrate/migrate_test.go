@@ -0,0 +1,182 @@
+package xxidmigrate
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// memDriver is a minimal driver which understands only the statements
+// issued by Migrate on a table with a text key column. Like PostgreSQL,
+// it refuses to compare the key column with bytes values.
+type memDriver struct {
+	mu      sync.Mutex
+	rows    map[string]string
+	commits int
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) { return memConn{d}, nil }
+
+type memConn struct{ d *memDriver }
+
+func (c memConn) Prepare(query string) (driver.Stmt, error) { return memStmt{c.d, query}, nil }
+func (c memConn) Close() error                              { return nil }
+func (c memConn) Begin() (driver.Tx, error)                 { return memTx{c.d}, nil }
+
+type memTx struct{ d *memDriver }
+
+func (tx memTx) Commit() error {
+	tx.d.mu.Lock()
+	tx.d.commits++
+	tx.d.mu.Unlock()
+	return nil
+}
+
+func (tx memTx) Rollback() error { return nil }
+
+type memStmt struct {
+	d     *memDriver
+	query string
+}
+
+func (s memStmt) Close() error  { return nil }
+func (s memStmt) NumInput() int { return -1 }
+
+func (s memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if !strings.HasPrefix(s.query, "UPDATE t SET dst = $1 WHERE src = $2") {
+		return nil, errors.New("unknown statement: " + s.query)
+	}
+	key, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("operator does not exist: text = %T", args[1])
+	}
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if _, ok := s.d.rows[key]; !ok {
+		return driver.RowsAffected(0), nil
+	}
+	s.d.rows[key] = args[0].(string)
+	return driver.RowsAffected(1), nil
+}
+
+func (s memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	q := s.query
+	if !strings.HasPrefix(q, "SELECT src FROM t") {
+		return nil, errors.New("unknown statement: " + q)
+	}
+	i := strings.Index(q, " LIMIT ")
+	if i < 0 {
+		return nil, errors.New("missing limit: " + q)
+	}
+	limit, _ := strconv.Atoi(q[i+len(" LIMIT "):])
+	var after string
+	if strings.Contains(q, " WHERE src > $1 ") {
+		key, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("operator does not exist: text > %T", args[0])
+		}
+		after = key
+	}
+
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	var keys []string
+	for k := range s.d.rows {
+		if k > after {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return &memRows{keys: keys}, nil
+}
+
+type memRows struct{ keys []string }
+
+func (r *memRows) Columns() []string { return []string{"src"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.keys) == 0 {
+		return io.EOF
+	}
+	dest[0] = []byte(r.keys[0])
+	r.keys = r.keys[1:]
+	return nil
+}
+
+var testDriver = &memDriver{}
+
+func init() {
+	sql.Register("xxidmigrate-mem", testDriver)
+}
+
+func TestMigrate(t *testing.T) {
+	sources := []string{
+		"01FMXEYJRT041061050R3GG28A",
+		"01FMXEYJRT041061050R3GG28B",
+		"01FMXEYJRT041061050R3GG28C",
+		"01FMXEYJRT041061050R3GG28D",
+		"01FMXEYJRT041061050R3GG28E",
+	}
+	testDriver.rows = make(map[string]string)
+	testDriver.commits = 0
+	for _, src := range sources {
+		testDriver.rows[src] = ""
+	}
+	db, _ := sql.Open("xxidmigrate-mem", "")
+	defer db.Close()
+
+	opts := Options{
+		Kind:              ULID,
+		Table:             "t",
+		KeyColumn:         "src",
+		TargetColumn:      "dst",
+		DollarPlaceholder: true,
+		BatchSize:         2,
+	}
+	var mapping bytes.Buffer
+	n, err := Migrate(context.Background(), db, opts, &mapping)
+	if err != nil {
+		t.Fatalf("Migrate failed, err= %v", err)
+	}
+	if n != len(sources) {
+		t.Fatalf("want %d rows, got= %v", len(sources), n)
+	}
+	if testDriver.commits != 3 {
+		t.Fatalf("want 3 batches, got= %v", testDriver.commits)
+	}
+	lines := strings.Split(strings.TrimSpace(mapping.String()), "\n")
+	if len(lines) != len(sources) {
+		t.Fatalf("want %d mapping lines, got= %v", len(sources), len(lines))
+	}
+	for i, src := range sources {
+		id, _ := Convert(ULID, []byte(src))
+		want := string(id.Base62())
+		if got := testDriver.rows[src]; got != want {
+			t.Fatalf("row %v not migrated, want= %v, got= %v", src, want, got)
+		}
+		if lines[i] != src+","+want {
+			t.Fatalf("mapping line %d not match, got= %v", i, lines[i])
+		}
+	}
+
+	testDriver.rows["not-an-ulid"] = ""
+	if _, err = Migrate(context.Background(), db, opts, nil); err == nil {
+		t.Fatalf("expect error for invalid source ID")
+	}
+	if _, err = Migrate(context.Background(), db, Options{Kind: ULID}, nil); err == nil {
+		t.Fatalf("expect error for missing table")
+	}
+}
@@ -0,0 +1,107 @@
+package xxidmsg
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+// Verdict is the result of Deduper.Observe.
+type Verdict int
+
+const (
+	// Fresh means the ID is seen for the first time in the window,
+	// the message should be processed.
+	Fresh Verdict = iota
+
+	// Duplicate means the ID has been seen in the window, the message
+	// is a redelivery and should be skipped.
+	Duplicate
+
+	// Expired means the ID is older than the window, the deduper can not
+	// tell whether it has been seen. Consumers may reject it or fall back
+	// to a persistent idempotency check.
+	Expired
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Fresh:
+		return "Fresh"
+	case Duplicate:
+		return "Duplicate"
+	case Expired:
+		return "Expired"
+	}
+	return "Unknown"
+}
+
+// Deduper remembers the IDs observed within a time window, the window
+// is determined by the timestamp embedded in IDs instead of the time
+// they are observed, thus the memory used is bounded by the number of
+// messages produced in the window, and a redelivered message is always
+// detected as long as it's produced within the window.
+//
+// A Deduper is safe for concurrent use.
+type Deduper struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[xxid.ID]struct{}
+	ids  idHeap
+}
+
+// NewDeduper returns a Deduper with the given window.
+func NewDeduper(window time.Duration) *Deduper {
+	return &Deduper{
+		window: window,
+		now:    time.Now,
+		seen:   make(map[xxid.ID]struct{}),
+	}
+}
+
+// Observe records id and reports whether it is fresh, a duplicate or
+// expired.
+func (d *Deduper) Observe(id xxid.ID) Verdict {
+	cutoff := d.now().Add(-d.window)
+	if id.Time().Before(cutoff) {
+		return Expired
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for len(d.ids) > 0 && d.ids[0].Time().Before(cutoff) {
+		delete(d.seen, heap.Pop(&d.ids).(xxid.ID))
+	}
+	if _, ok := d.seen[id]; ok {
+		return Duplicate
+	}
+	d.seen[id] = struct{}{}
+	heap.Push(&d.ids, id)
+	return Fresh
+}
+
+// Len returns the number of IDs remembered.
+func (d *Deduper) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.seen)
+}
+
+// idHeap is a min-heap of IDs ordered by time.
+type idHeap []xxid.ID
+
+func (h idHeap) Len() int            { return len(h) }
+func (h idHeap) Less(i, j int) bool  { return xxid.CompareIDs(h[i], h[j]) < 0 }
+func (h idHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *idHeap) Push(x interface{}) { *h = append(*h, x.(xxid.ID)) }
+
+func (h *idHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
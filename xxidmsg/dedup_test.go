@@ -0,0 +1,49 @@
+package xxidmsg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestDeduper(t *testing.T) {
+	now := time.Now()
+	d := NewDeduper(time.Minute)
+	d.now = func() time.Time { return now }
+
+	gen := xxid.NewGenerator()
+	old := gen.NewWithTime(now.Add(-2 * time.Minute))
+	id1 := gen.NewWithTime(now.Add(-30 * time.Second))
+	id2 := gen.NewWithTime(now)
+
+	for _, x := range []struct {
+		id   xxid.ID
+		want Verdict
+	}{
+		{id2, Fresh},
+		{id1, Fresh},
+		{id2, Duplicate},
+		{id1, Duplicate},
+		{old, Expired},
+	} {
+		if got := d.Observe(x.id); got != x.want {
+			t.Fatalf("verdict not match, want= %v, got= %v", x.want, got)
+		}
+	}
+	if d.Len() != 2 {
+		t.Fatalf("length not match, want= %v, got= %v", 2, d.Len())
+	}
+
+	// id1 falls out of the window and is evicted
+	now = now.Add(45 * time.Second)
+	if got := d.Observe(id1); got != Expired {
+		t.Fatalf("verdict not match, want= %v, got= %v", Expired, got)
+	}
+	if got := d.Observe(id2); got != Duplicate {
+		t.Fatalf("verdict not match, want= %v, got= %v", Duplicate, got)
+	}
+	if d.Len() != 1 {
+		t.Fatalf("length not match, want= %v, got= %v", 1, d.Len())
+	}
+}
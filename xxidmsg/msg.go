@@ -0,0 +1,54 @@
+// Package xxidmsg provides helpers to use xxid IDs as message IDs of
+// NATS JetStream and AMQP, and a deduplication window built on the
+// timestamp embedded in IDs, giving at-least-once consumers an
+// idempotency key scheme.
+//
+// The helpers don't depend on any specific client, e.g. with nats.go:
+//
+//	msg := nats.NewMsg(subject)
+//	xxidmsg.SetNatsMsgID(msg.Header, id)
+//
+// or with amqp091-go:
+//
+//	ch.Publish(exchange, key, false, false, amqp.Publishing{
+//		MessageId: xxidmsg.AMQPMessageID(id),
+//		Body:      body,
+//	})
+package xxidmsg
+
+import (
+	"github.com/jxskiss/xxid/v2"
+)
+
+// NatsMsgIDHeader is the header used by NATS JetStream to deduplicate
+// published messages.
+const NatsMsgIDHeader = "Nats-Msg-Id"
+
+// SetNatsMsgID sets the Nats-Msg-Id header to the ID's base62 form,
+// an existing value is replaced. header is typically nats.Header.
+func SetNatsMsgID(header map[string][]string, id xxid.ID) {
+	header[NatsMsgIDHeader] = []string{string(id.Base62())}
+}
+
+// NatsMsgID parses the ID from the Nats-Msg-Id header, ok is false if
+// the header does not exist or is not an ID.
+func NatsMsgID(header map[string][]string) (id xxid.ID, ok bool) {
+	values := header[NatsMsgIDHeader]
+	if len(values) == 0 {
+		return id, false
+	}
+	id, err := xxid.ParseBase62String(values[0])
+	return id, err == nil
+}
+
+// AMQPMessageID returns the message-id property of an ID, which is the
+// ID's base62 form.
+func AMQPMessageID(id xxid.ID) string {
+	return string(id.Base62())
+}
+
+// ParseAMQPMessageID parses an ID from a message-id property returned
+// by AMQPMessageID.
+func ParseAMQPMessageID(messageID string) (xxid.ID, error) {
+	return xxid.ParseBase62String(messageID)
+}
@@ -0,0 +1,35 @@
+package xxidmsg
+
+import (
+	"testing"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestNatsMsgID(t *testing.T) {
+	id := xxid.New()
+	header := map[string][]string{}
+	SetNatsMsgID(header, xxid.New())
+	SetNatsMsgID(header, id)
+	if len(header[NatsMsgIDHeader]) != 1 {
+		t.Fatalf("header should be replaced, got= %v", header)
+	}
+	got, ok := NatsMsgID(header)
+	if !ok || got != id {
+		t.Fatalf("NatsMsgID result not match, want= %v, got= %v, %v", id, got, ok)
+	}
+	if _, ok := NatsMsgID(map[string][]string{}); ok {
+		t.Fatalf("NatsMsgID from empty header should fail")
+	}
+	if _, ok := NatsMsgID(map[string][]string{NatsMsgIDHeader: {"x"}}); ok {
+		t.Fatalf("NatsMsgID from invalid header should fail")
+	}
+}
+
+func TestAMQPMessageID(t *testing.T) {
+	id := xxid.New()
+	got, err := ParseAMQPMessageID(AMQPMessageID(id))
+	if err != nil || got != id {
+		t.Fatalf("round trip failed, got= %v, err= %v", got, err)
+	}
+}
@@ -0,0 +1,57 @@
+package xxidseq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HTTPSource leases blocks from a sequence service over HTTP, it sends
+// a POST request with the JSON body {"size": n} to URL, and expects a
+// 200 response with a JSON Block, e.g. {"start": 1000, "end": 2000}.
+type HTTPSource struct {
+	// URL is the endpoint to lease blocks.
+	URL string
+
+	// Client is the HTTP client, http.DefaultClient is used if nil.
+	Client *http.Client
+
+	// Header is added to each request, e.g. for authentication.
+	Header http.Header
+}
+
+// Lease implements Source.
+func (s *HTTPSource) Lease(ctx context.Context, size int) (Block, error) {
+	body, _ := json.Marshal(struct {
+		Size int `json:"size"`
+	}{size})
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return Block{}, err
+	}
+	req = req.WithContext(ctx)
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Block{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Block{}, fmt.Errorf("xxidseq: lease failed with status %s", resp.Status)
+	}
+	var block Block
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return Block{}, fmt.Errorf("xxidseq: invalid lease response: %v", err)
+	}
+	return block, nil
+}
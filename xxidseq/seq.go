@@ -0,0 +1,143 @@
+// Package xxidseq mints xxid IDs from blocks of sequence numbers leased
+// from a central sequence service, for deployments which require dense
+// and auditable allocation of sequence numbers across processes.
+//
+// Each ID carries its sequence number as the 8 bytes machine ID, i.e.
+// the machine ID type is xxid.Specified8, thus IDs are still ordered by
+// time and unique as long as the sequence numbers are unique, and the
+// sequence number can be read back by Sequence.
+//
+// The sequence service is pluggable by implementing Source, HTTPSource
+// talks to a service over HTTP with JSON, a gRPC client can be adapted
+// by SourceFunc.
+package xxidseq
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+var (
+	errInvalidBlockSize = errors.New("xxidseq: block size must be positive")
+	errInvalidBlock     = errors.New("xxidseq: leased block is invalid")
+)
+
+// Block is a range of sequence numbers [Start, End) leased from a
+// sequence service.
+type Block struct {
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+}
+
+// Len returns the number of sequence numbers in the block.
+func (b Block) Len() uint64 {
+	if b.End <= b.Start {
+		return 0
+	}
+	return b.End - b.Start
+}
+
+// Source leases blocks of sequence numbers.
+type Source interface {
+	// Lease leases a block of about size sequence numbers, the service
+	// may return a smaller or larger block.
+	Lease(ctx context.Context, size int) (Block, error)
+}
+
+// SourceFunc adapts a function to Source.
+type SourceFunc func(ctx context.Context, size int) (Block, error)
+
+// Lease calls f(ctx, size).
+func (f SourceFunc) Lease(ctx context.Context, size int) (Block, error) {
+	return f(ctx, size)
+}
+
+// Client mints IDs from blocks leased from a Source, a block is leased
+// when the current one is exhausted. A Client is safe for concurrent use.
+type Client struct {
+	src       Source
+	blockSize int
+	gen       *xxid.Generator
+
+	// OnLease, if not nil, is called with each leased block, e.g. to
+	// write an audit log. It must be set before minting IDs.
+	OnLease func(Block)
+
+	mu    sync.Mutex
+	block Block
+	next  uint64
+}
+
+// NewClient returns a Client which leases blocks of blockSize sequence
+// numbers from src, opts are applied like xxid.NewGeneratorE, options
+// setting the machine ID are overridden.
+func NewClient(src Source, blockSize int, opts ...xxid.Option) (*Client, error) {
+	if blockSize <= 0 {
+		return nil, errInvalidBlockSize
+	}
+	opts = append(opts, xxid.WithMachineID(make([]byte, 8)))
+	gen, err := xxid.NewGeneratorE(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{src: src, blockSize: blockSize, gen: gen}, nil
+}
+
+// New mints an ID with the next sequence number, it leases a new block
+// if the current one is exhausted.
+func (c *Client) New(ctx context.Context) (xxid.ID, error) {
+	seq, err := c.nextSequence(ctx)
+	if err != nil {
+		return xxid.ID{}, err
+	}
+	id := c.gen.New()
+	payload := id.Payload()
+	binary.BigEndian.PutUint64(payload[2:10], seq)
+	return id.WithPayload(payload)
+}
+
+// Remaining returns the number of sequence numbers left in the current
+// block.
+func (c *Client) Remaining() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.next >= c.block.End {
+		return 0
+	}
+	return c.block.End - c.next
+}
+
+func (c *Client) nextSequence(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.next >= c.block.End {
+		block, err := c.src.Lease(ctx, c.blockSize)
+		if err != nil {
+			return 0, err
+		}
+		if block.Len() == 0 {
+			return 0, errInvalidBlock
+		}
+		if c.OnLease != nil {
+			c.OnLease(block)
+		}
+		c.block = block
+		c.next = block.Start
+	}
+	seq := c.next
+	c.next++
+	return seq, nil
+}
+
+// Sequence returns the sequence number of an ID minted by Client, ok is
+// false if the machine ID type is not xxid.Specified8.
+func Sequence(id xxid.ID) (seq uint64, ok bool) {
+	if id.MachineIDType() != xxid.Specified8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(id.MachineID()), true
+}
@@ -0,0 +1,107 @@
+package xxidseq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestClient(t *testing.T) {
+	var mu sync.Mutex
+	next := uint64(100)
+	src := SourceFunc(func(ctx context.Context, size int) (Block, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		b := Block{Start: next, End: next + uint64(size)}
+		next = b.End
+		return b, nil
+	})
+	client, err := NewClient(src, 10, xxid.WithFlag(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var leases []Block
+	client.OnLease = func(b Block) { leases = append(leases, b) }
+
+	for i := 0; i < 25; i++ {
+		id, err := client.New(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		seq, ok := Sequence(id)
+		if !ok || seq != 100+uint64(i) {
+			t.Fatalf("sequence not match, want= %v, got= %v, %v", 100+i, seq, ok)
+		}
+		if id.Flag() != 3 {
+			t.Fatalf("option not applied")
+		}
+	}
+	if len(leases) != 3 || client.Remaining() != 5 {
+		t.Fatalf("leases = %v, remaining = %v", leases, client.Remaining())
+	}
+	if _, ok := Sequence(xxid.New()); ok {
+		t.Fatalf("Sequence should fail for IDs not minted by Client")
+	}
+
+	failing, _ := NewClient(SourceFunc(func(context.Context, int) (Block, error) {
+		return Block{}, errors.New("unavailable")
+	}), 10)
+	if _, err := failing.New(context.Background()); err == nil {
+		t.Fatalf("expect error from source")
+	}
+	empty, _ := NewClient(SourceFunc(func(context.Context, int) (Block, error) {
+		return Block{Start: 5, End: 5}, nil
+	}), 10)
+	if _, err := empty.New(context.Background()); err == nil {
+		t.Fatalf("expect error for empty block")
+	}
+	if _, err := NewClient(src, 0); err == nil {
+		t.Fatalf("expect error for invalid block size")
+	}
+}
+
+func TestClient_Compact(t *testing.T) {
+	src := SourceFunc(func(ctx context.Context, size int) (Block, error) {
+		return Block{Start: 7, End: 7 + uint64(size)}, nil
+	})
+	client, err := NewClient(src, 10, xxid.WithCompactLayout())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := client.New(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq, ok := Sequence(id); !ok || seq != 7 || !id.IsCompact() {
+		t.Fatalf("sequence = %v, %v, compact = %v", seq, ok, id.IsCompact())
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Size int }
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "token" ||
+			json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(Block{Start: 1, End: 1 + uint64(req.Size)})
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{URL: srv.URL, Header: http.Header{"Authorization": {"token"}}}
+	block, err := src.Lease(context.Background(), 50)
+	if err != nil || block != (Block{Start: 1, End: 51}) {
+		t.Fatalf("Lease = %v, %v", block, err)
+	}
+	src.Header = nil
+	if _, err := src.Lease(context.Background(), 50); err == nil {
+		t.Fatalf("expect error for bad status")
+	}
+}
@@ -0,0 +1,185 @@
+// Package xxidsqltest certifies that a database driver round-trips xxid
+// IDs through ID.Value and ID.Scan, in both the base62 and binary
+// storage forms, with the column types recommended by xxid.ColumnDDL
+// and xxid.CompactColumnDDL.
+//
+// It is intended to be called from the users' own test suites against
+// the specific driver and database they use, e.g.:
+//
+//	func TestXXIDRoundTrip(t *testing.T) {
+//		db, _ := sql.Open("mysql", dsn)
+//		if err := xxidsqltest.Certify(context.Background(), db, "mysql"); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+package xxidsqltest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+// TableName is the name of the temporary table created by Certify.
+const TableName = "xxidsqltest_ids"
+
+// storages are the storage forms certified by Certify, StoreShort is
+// not certified since it does not retain all fields of an ID.
+var storages = []xxid.SQLStorage{xxid.StoreBase62, xxid.StoreBinary}
+
+// Certify checks that IDs of every machine ID type, in both the full and
+// the compact layout, survive a round trip through db, in each of the
+// base62 and binary storage forms. A NULL value is also checked to scan
+// into the zero ID.
+//
+// For each storage form, machine ID type and layout, it creates a
+// temporary table named TableName with the column type returned by
+// xxid.ColumnDDL or xxid.CompactColumnDDL, inserts the IDs, reads them
+// back and drops the table.
+// All statements run on a single connection, since temporary tables are
+// visible only to the connection which created them. dialect is passed
+// to xxid.ColumnDDL, and selects "$1" style placeholders if it is
+// "postgres", else "?" is used.
+//
+// It changes the process-wide storage form by xxid.SetSQLStorage and
+// restores it on return, thus it must not run in parallel with other
+// code which saves IDs into databases.
+func Certify(ctx context.Context, db *sql.DB, dialect string) (err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("xxidsqltest: %v", err)
+	}
+	defer conn.Close()
+
+	defer xxid.SetSQLStorage(xxid.CurrentSQLStorage())
+	samples := sampleIDs()
+	for _, storage := range storages {
+		xxid.SetSQLStorage(storage)
+		for _, set := range samples {
+			err = certifyType(ctx, conn, dialect, set)
+			if err != nil {
+				layout := "full"
+				if set.compact {
+					layout = "compact"
+				}
+				return fmt.Errorf("xxidsqltest: %v storage, %v, %s layout: %v",
+					storage, set.typ, layout, err)
+			}
+		}
+	}
+	return nil
+}
+
+func certifyType(ctx context.Context, conn *sql.Conn, dialect string, set sampleSet) (err error) {
+	ddl := xxid.ColumnDDL
+	if set.compact {
+		ddl = xxid.CompactColumnDDL
+	}
+	column, err := ddl(dialect, set.typ)
+	if err != nil {
+		return err
+	}
+	create := "CREATE TEMPORARY TABLE " + TableName + " (n INTEGER NOT NULL, id " + column + ")"
+	if _, err = conn.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("create table: %v", err)
+	}
+	defer func() {
+		_, dropErr := conn.ExecContext(ctx, "DROP TABLE "+TableName)
+		if err == nil && dropErr != nil {
+			err = fmt.Errorf("drop table: %v", dropErr)
+		}
+	}()
+
+	insert := "INSERT INTO " + TableName + " (n, id) VALUES (?, ?)"
+	if dialect == "postgres" {
+		insert = "INSERT INTO " + TableName + " (n, id) VALUES ($1, $2)"
+	}
+	ids := set.ids
+	for i, id := range ids {
+		if _, err = conn.ExecContext(ctx, insert, i, id); err != nil {
+			return fmt.Errorf("insert %v: %v", id, err)
+		}
+	}
+	// the last row checks NULL
+	if _, err = conn.ExecContext(ctx, insert, len(ids), nil); err != nil {
+		return fmt.Errorf("insert NULL: %v", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT n, id FROM "+TableName+" ORDER BY n")
+	if err != nil {
+		return fmt.Errorf("select: %v", err)
+	}
+	defer rows.Close()
+	count := 0
+	for rows.Next() {
+		var n int
+		var got xxid.ID
+		if err = rows.Scan(&n, &got); err != nil {
+			return fmt.Errorf("scan row %d: %v", count, err)
+		}
+		if n != count {
+			return fmt.Errorf("row %d: got row %d", count, n)
+		}
+		want := xxid.ID{}
+		if n < len(ids) {
+			want = ids[n]
+		}
+		if got != want {
+			return fmt.Errorf("row %d: want %v, got %v", n, want, got)
+		}
+		count++
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("select: %v", err)
+	}
+	if count != len(ids)+1 {
+		return fmt.Errorf("want %d rows, got %d", len(ids)+1, count)
+	}
+	return nil
+}
+
+// sampleSet is the IDs of a machine ID type and layout, which are
+// stored in a table.
+type sampleSet struct {
+	typ     xxid.MachineIDType
+	compact bool
+	ids     []xxid.ID
+}
+
+// sampleIDs returns the IDs to check, the full layout of each machine ID
+// type is followed by the compact layout, indexed by machine ID type
+// times 2 plus 1 for the compact layout. Each set has the min and max
+// IDs, and generated IDs if the type can be set on a generator.
+func sampleIDs() []sampleSet {
+	layouts := xxid.Layout()
+	out := make([]sampleSet, 2*len(layouts))
+	for _, l := range layouts {
+		typ := l.MachineIDType
+		min, max := xxid.MinID(typ), xxid.MaxID(typ)
+		out[2*typ] = sampleSet{typ, false, []xxid.ID{min, max}}
+		out[2*typ+1] = sampleSet{typ, true, []xxid.ID{min.Compact(), max.Compact()}}
+	}
+	ip4 := net.ParseIP("10.1.2.3")
+	gens := []*xxid.Generator{
+		xxid.NewGenerator(),
+		xxid.NewGenerator().UseEphemeralMachineID().UseFlag(1),
+		xxid.NewGenerator().UseIPv4(ip4).UsePort(8080),
+		xxid.NewGenerator().UseIPv6(net.ParseIP("fd00::1")),
+		xxid.NewGenerator().UseMachineID([]byte{1, 2, 3, 4}),
+		xxid.NewGenerator().UseMachineID([]byte{1, 2, 3, 4, 5, 6, 7, 8}),
+		xxid.NewGenerator().UseMachineID([]byte("0123456789abcdef")),
+		xxid.NewGenerator().UseHashedIPv4(ip4, []byte("xxidsqltest")),
+	}
+	for _, gen := range gens {
+		for i := 0; i < 3; i++ {
+			id := gen.New()
+			full, compact := &out[2*id.MachineIDType()], &out[2*id.MachineIDType()+1]
+			full.ids = append(full.ids, id)
+			compact.ids = append(compact.ids, id.Compact())
+		}
+	}
+	return out
+}
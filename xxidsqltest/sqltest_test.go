@@ -0,0 +1,131 @@
+package xxidsqltest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+// memDriver is a minimal driver which understands only the statements
+// issued by Certify, text values are returned as []byte like many real
+// drivers do. If truncate is positive, []byte values are truncated to
+// that length, which simulates a column too narrow to hold the IDs.
+// Like MySQL, shorter values in BINARY(n) columns are padded with zeros.
+type memDriver struct {
+	truncate int
+
+	mu    sync.Mutex
+	rows  [][]driver.Value
+	width int
+}
+
+func (d *memDriver) Open(name string) (driver.Conn, error) { return memConn{d}, nil }
+
+type memConn struct{ d *memDriver }
+
+func (c memConn) Prepare(query string) (driver.Stmt, error) { return memStmt{c.d, query}, nil }
+func (c memConn) Close() error                              { return nil }
+func (c memConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type memStmt struct {
+	d     *memDriver
+	query string
+}
+
+func (s memStmt) Close() error  { return nil }
+func (s memStmt) NumInput() int { return -1 }
+
+func (s memStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case strings.HasPrefix(s.query, "CREATE"), strings.HasPrefix(s.query, "DROP"):
+		d.rows = nil
+		d.width = 0
+		if i := strings.Index(s.query, "BINARY("); i >= 0 {
+			n := s.query[i+len("BINARY("):]
+			d.width, _ = strconv.Atoi(n[:strings.IndexByte(n, ')')])
+		}
+	case strings.HasPrefix(s.query, "INSERT"):
+		row := make([]driver.Value, len(args))
+		for i, v := range args {
+			switch x := v.(type) {
+			case string:
+				v = []byte(x)
+			case []byte:
+				if d.truncate > 0 && len(x) > d.truncate {
+					x = x[:d.truncate]
+				}
+				v = append([]byte(nil), x...)
+				if i == 1 && len(x) < d.width {
+					v = append(v.([]byte), make([]byte, d.width-len(x))...)
+				}
+			}
+			row[i] = v
+		}
+		d.rows = append(d.rows, row)
+	default:
+		return nil, errors.New("unknown statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s memStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &memRows{rows: append([][]driver.Value(nil), s.d.rows...)}, nil
+}
+
+type memRows struct{ rows [][]driver.Value }
+
+func (r *memRows) Columns() []string { return []string{"n", "id"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("xxidsqltest-mem", &memDriver{})
+	sql.Register("xxidsqltest-truncate", &memDriver{truncate: 16})
+}
+
+func TestCertify(t *testing.T) {
+	xxid.SetSQLStorage(xxid.StoreShort)
+	defer xxid.SetSQLStorage(xxid.StoreBase62)
+
+	db, _ := sql.Open("xxidsqltest-mem", "")
+	defer db.Close()
+	for _, dialect := range []string{"mysql", "postgres", "sqlite"} {
+		if err := Certify(context.Background(), db, dialect); err != nil {
+			t.Fatalf("Certify(%v) failed, err= %v", dialect, err)
+		}
+	}
+	if got := xxid.CurrentSQLStorage(); got != xxid.StoreShort {
+		t.Fatalf("storage not restored, got= %v", got)
+	}
+	if err := Certify(context.Background(), db, "oracle"); err == nil {
+		t.Fatalf("expect error for unknown dialect")
+	}
+
+	bad, _ := sql.Open("xxidsqltest-truncate", "")
+	defer bad.Close()
+	err := Certify(context.Background(), bad, "mysql")
+	if err == nil || !strings.Contains(err.Error(), "binary storage") {
+		t.Fatalf("expect error for truncating driver, got= %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package xxidtoken
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+const version2 = 2
+
+const maxMetadataLen = 255
+
+var errMetadataTooLarge = errors.New("xxidtoken: metadata is too large")
+
+// Builder builds a signed token which carries an ID, an optional expiry
+// time and small key-value metadata, e.g. user shard or client type.
+//
+// Keys and values must not be longer than 255 bytes, and at most 255
+// pairs are allowed. Like the ID, the metadata is signed but not
+// encrypted, it must not contain secrets.
+type Builder struct {
+	id       xxid.ID
+	expiry   time.Time
+	metadata map[string]string
+}
+
+// NewBuilder returns a new Builder to build a token of id.
+func NewBuilder(id xxid.ID) *Builder {
+	return &Builder{id: id}
+}
+
+// Expiry sets the expiry time of the token, a token without expiry time
+// never expires.
+func (b *Builder) Expiry(expiry time.Time) *Builder {
+	b.expiry = expiry
+	return b
+}
+
+// Set sets a metadata key-value pair.
+func (b *Builder) Set(key, value string) *Builder {
+	if b.metadata == nil {
+		b.metadata = make(map[string]string)
+	}
+	b.metadata[key] = value
+	return b
+}
+
+// Sign makes the token and signs it by key.
+func (b *Builder) Sign(key []byte) (string, error) {
+	if len(b.metadata) > maxMetadataLen {
+		return "", errMetadataTooLarge
+	}
+	keys := make([]string, 0, len(b.metadata))
+	for k, v := range b.metadata {
+		if len(k) > maxMetadataLen || len(v) > maxMetadataLen {
+			return "", errMetadataTooLarge
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	bin := b.id.Binary()
+	var expiry uint64
+	if !b.expiry.IsZero() {
+		expiry = uint64(b.expiry.Unix())
+	}
+	buf := make([]byte, 0, 64)
+	buf = append(buf, version2, byte(len(bin)))
+	buf = append(buf, bin...)
+	buf = appendUint64(buf, expiry)
+	buf = append(buf, byte(len(keys)))
+	for _, k := range keys {
+		v := b.metadata[k]
+		buf = append(buf, byte(len(k)))
+		buf = append(buf, k...)
+		buf = append(buf, byte(len(v)))
+		buf = append(buf, v...)
+	}
+	buf = appendSignature(buf, key)
+	return b64Enc.EncodeToString(buf), nil
+}
+
+// Token is a parsed token.
+type Token struct {
+	ID       xxid.ID
+	Expiry   time.Time // zero if the token never expires
+	Metadata map[string]string
+}
+
+// Get returns the metadata value of key.
+func (t *Token) Get(key string) string {
+	return t.Metadata[key]
+}
+
+// Parse checks the signature and expiry time of token, and returns the
+// ID and metadata carried by the token.
+// Tokens made by Issue are also accepted, which don't have metadata.
+func Parse(key []byte, token string) (*Token, error) {
+	buf, err := b64Enc.DecodeString(token)
+	if err != nil || len(buf) < 1+signatureLen {
+		return nil, ErrInvalidToken
+	}
+	var out *Token
+	switch buf[0] {
+	case version1:
+		id, expiry, err := Decode(key, token)
+		if err != nil {
+			return nil, err
+		}
+		out = &Token{ID: id, Expiry: expiry}
+	case version2:
+		payload, err := checkSignature(buf, key)
+		if err != nil {
+			return nil, err
+		}
+		if out, err = decodeV2(payload); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidToken
+	}
+	if !out.Expiry.IsZero() && time.Now().After(out.Expiry) {
+		return nil, ErrExpired
+	}
+	return out, nil
+}
+
+func decodeV2(payload []byte) (*Token, error) {
+	r := reader{buf: payload[1:]}
+	bin := r.next(int(r.byte()))
+	expiry := r.next(expiryLen)
+	n := int(r.byte())
+	md := make(map[string]string, n)
+	for i := 0; i < n && !r.bad; i++ {
+		k := r.next(int(r.byte()))
+		v := r.next(int(r.byte()))
+		md[string(k)] = string(v)
+	}
+	if r.bad || len(r.buf) != 0 {
+		return nil, ErrInvalidToken
+	}
+	id, err := xxid.ParseBinary(bin)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	out := &Token{ID: id, Metadata: md}
+	if sec := binary.BigEndian.Uint64(expiry); sec != 0 {
+		out.Expiry = time.Unix(int64(sec), 0)
+	}
+	return out, nil
+}
+
+type reader struct {
+	buf []byte
+	bad bool
+}
+
+func (r *reader) next(n int) []byte {
+	if r.bad || len(r.buf) < n {
+		r.bad = true
+		return nil
+	}
+	out := r.buf[:n]
+	r.buf = r.buf[n:]
+	return out
+}
+
+func (r *reader) byte() byte {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
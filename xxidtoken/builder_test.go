@@ -0,0 +1,47 @@
+package xxidtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestBuilder(t *testing.T) {
+	key := []byte("secret")
+	id := xxid.New()
+	token, err := NewBuilder(id).
+		Set("shard", "42").
+		Set("client", "ios").
+		Sign(key)
+	if err != nil {
+		t.Fatalf("failed sign token, err= %v", err)
+	}
+
+	got, err := Parse(key, token)
+	if err != nil {
+		t.Fatalf("failed parse token, err= %v", err)
+	}
+	if got.ID != id || !got.Expiry.IsZero() || got.Get("shard") != "42" || got.Get("client") != "ios" {
+		t.Fatalf("parsed token not match: %+v", got)
+	}
+	if _, err = Parse([]byte("other"), token); err != ErrSignature {
+		t.Fatalf("expect ErrSignature, got= %v", err)
+	}
+
+	expired, _ := NewBuilder(id).Expiry(time.Now().Add(-time.Second)).Sign(key)
+	if _, err = Parse(key, expired); err != ErrExpired {
+		t.Fatalf("expect ErrExpired, got= %v", err)
+	}
+
+	v1 := Issue(key, id, time.Now().Add(time.Hour))
+	got, err = Parse(key, v1)
+	if err != nil || got.ID != id || len(got.Metadata) != 0 {
+		t.Fatalf("failed parse token made by Issue, err= %v", err)
+	}
+
+	if _, err = NewBuilder(id).Set(strings.Repeat("k", 256), "v").Sign(key); err == nil {
+		t.Fatalf("expect error for too large metadata")
+	}
+}
@@ -0,0 +1,111 @@
+// Package xxidtoken builds expiring signed tokens on top of xxid IDs.
+//
+// A token combines an ID, an expiry time and a HMAC-SHA256 signature
+// into one opaque URL-safe string, it is suitable for password-reset or
+// invite links, where the ID identifies the server-side record.
+// Builder additionally packs small key-value metadata into a token,
+// which is suitable for request or session tokens.
+//
+// The ID is not encrypted, anyone holding a token can read the ID and
+// the expiry time, but can not forge or modify a token without the key.
+package xxidtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+const (
+	version1 = 1
+
+	expiryLen    = 8
+	signatureLen = sha256.Size
+)
+
+var (
+	// ErrInvalidToken is returned when a token is malformed.
+	ErrInvalidToken = errors.New("xxidtoken: token is invalid")
+
+	// ErrSignature is returned when the signature of a token is incorrect.
+	ErrSignature = errors.New("xxidtoken: signature is incorrect")
+
+	// ErrExpired is returned when a token is expired.
+	ErrExpired = errors.New("xxidtoken: token is expired")
+)
+
+var b64Enc = base64.RawURLEncoding
+
+// Issue makes a token of id which expires at the given time, the token
+// is signed by key.
+func Issue(key []byte, id xxid.ID, expiry time.Time) string {
+	bin := id.Binary()
+	buf := make([]byte, 0, 1+len(bin)+expiryLen+signatureLen)
+	buf = append(buf, version1)
+	buf = append(buf, bin...)
+	buf = appendUint64(buf, uint64(expiry.Unix()))
+	buf = appendSignature(buf, key)
+	return b64Enc.EncodeToString(buf)
+}
+
+// Verify checks the signature and expiry time of token, and returns the
+// ID carried by the token.
+func Verify(key []byte, token string) (xxid.ID, error) {
+	id, expiry, err := Decode(key, token)
+	if err != nil {
+		return id, err
+	}
+	if time.Now().After(expiry) {
+		return xxid.ID{}, ErrExpired
+	}
+	return id, nil
+}
+
+// Decode checks the signature of token, and returns the ID and expiry
+// time carried by the token, the expiry time is not checked.
+func Decode(key []byte, token string) (id xxid.ID, expiry time.Time, err error) {
+	buf, err := b64Enc.DecodeString(token)
+	if err != nil || len(buf) < 1+expiryLen+signatureLen || buf[0] != version1 {
+		return id, expiry, ErrInvalidToken
+	}
+	payload, err := checkSignature(buf, key)
+	if err != nil {
+		return id, expiry, err
+	}
+	bin := payload[1 : len(payload)-expiryLen]
+	id, err = xxid.ParseBinary(bin)
+	if err != nil {
+		return id, expiry, ErrInvalidToken
+	}
+	sec := binary.BigEndian.Uint64(payload[len(payload)-expiryLen:])
+	return id, time.Unix(int64(sec), 0), nil
+}
+
+func appendUint64(buf []byte, x uint64) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], x)
+	return append(buf, tmp[:]...)
+}
+
+func appendSignature(payload, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(payload)
+}
+
+// checkSignature verifies the signature at the tail of buf, and returns
+// the signed payload.
+func checkSignature(buf, key []byte) ([]byte, error) {
+	payload := buf[:len(buf)-signatureLen]
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	if !hmac.Equal(mac.Sum(nil), buf[len(payload):]) {
+		return nil, ErrSignature
+	}
+	return payload, nil
+}
@@ -0,0 +1,44 @@
+package xxidtoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestIssueVerify(t *testing.T) {
+	key := []byte("secret")
+	id := xxid.New()
+	token := Issue(key, id, time.Now().Add(time.Hour))
+
+	got, err := Verify(key, token)
+	if err != nil || got != id {
+		t.Fatalf("failed verify token, err= %v", err)
+	}
+
+	if _, err = Verify([]byte("other"), token); err != ErrSignature {
+		t.Fatalf("expect ErrSignature, got= %v", err)
+	}
+
+	tampered := []byte(token)
+	tampered[5] ^= 1
+	if _, err = Verify(key, string(tampered)); err == nil {
+		t.Fatalf("expect error for tampered token")
+	}
+
+	expired := Issue(key, id, time.Now().Add(-time.Second))
+	if _, err = Verify(key, expired); err != ErrExpired {
+		t.Fatalf("expect ErrExpired, got= %v", err)
+	}
+	got, expiry, err := Decode(key, expired)
+	if err != nil || got != id || expiry.After(time.Now()) {
+		t.Fatalf("failed decode expired token, err= %v", err)
+	}
+
+	for _, s := range []string{"", "!!!", "AQ"} {
+		if _, err = Verify(key, s); err != ErrInvalidToken {
+			t.Fatalf("expect ErrInvalidToken for %q, got= %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/jxskiss/xxid/v2/xxiduuid
+
+go 1.13
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jxskiss/xxid/v2 v2.0.0
+)
+
+replace github.com/jxskiss/xxid/v2 => ../
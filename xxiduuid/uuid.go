@@ -0,0 +1,27 @@
+// Package xxiduuid converts between xxid IDs and github.com/google/uuid
+// UUIDs, for libraries which speak google/uuid types at their boundaries.
+//
+// It is a separate module to keep google/uuid out of the dependencies
+// of xxid.
+package xxiduuid
+
+import (
+	"github.com/google/uuid"
+	"github.com/jxskiss/xxid/v2"
+)
+
+// ToGoogleUUID converts id to an UUID of its binary form, see ID.UUID.
+// It returns uuid.Nil if id can not be represented as an UUID, i.e.
+// its binary form is not 16 bytes.
+func ToGoogleUUID(id xxid.ID) uuid.UUID {
+	arrs, err := xxid.ToUUIDBytes([]xxid.ID{id})
+	if err != nil {
+		return uuid.Nil
+	}
+	return uuid.UUID(arrs[0])
+}
+
+// FromGoogleUUID converts an UUID returned by ToGoogleUUID back to ID.
+func FromGoogleUUID(u uuid.UUID) (xxid.ID, error) {
+	return xxid.ParseBinary(u[:])
+}
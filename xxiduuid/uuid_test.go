@@ -0,0 +1,25 @@
+package xxiduuid
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jxskiss/xxid/v2"
+)
+
+func TestGoogleUUID(t *testing.T) {
+	id := xxid.New()
+	u := ToGoogleUUID(id)
+	if u.String() != id.UUID() {
+		t.Fatalf("UUID text not match, want= %v, got= %v", id.UUID(), u.String())
+	}
+	got, err := FromGoogleUUID(u)
+	if err != nil || got != id {
+		t.Fatalf("failed convert UUID back, err= %v", err)
+	}
+
+	wide := xxid.NewGenerator().UseMachineID([]byte("12345678")).New()
+	if ToGoogleUUID(wide) != uuid.Nil {
+		t.Fatalf("expect uuid.Nil for ID which can not be represented as UUID")
+	}
+}